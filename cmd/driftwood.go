@@ -26,6 +26,16 @@ func main() {
 
 	// Pass GuildID to bot for command registration
 	b.SetGuildID(cfg.GuildID)
+	b.SetCommandScope(cfg.GlobalCommands, cfg.EveryGuild)
+	b.SetStatePath(cfg.StatePath)
+	b.SetCacheSize(cfg.CacheSize)
+	b.SetSendRetries(cfg.SendRetries)
+	b.SetDryRun(cfg.DryRun)
+	b.SetAuditChannel(cfg.AuditChannelID)
+	if err := b.SetIntents(cfg.Intents); err != nil {
+		slog.Error("Invalid INTENTS", "error", err)
+		os.Exit(1)
+	}
 
 	// Start the bot
 	go func() {
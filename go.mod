@@ -3,7 +3,7 @@ module driftwood
 go 1.23.0
 
 require (
-	github.com/bwmarrin/discordgo v0.28.1
+	github.com/bwmarrin/discordgo v0.29.0
 	github.com/joho/godotenv v1.5.1
 	github.com/yuin/gopher-lua v1.1.1
 )
@@ -2,18 +2,27 @@ package bot
 
 import (
 	"log/slog"
+	"strings"
 
 	"driftwood/internal/lua"
+	"driftwood/internal/lua/utils"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // Bot represents the Discord bot instance.
 type Bot struct {
-	Session *discordgo.Session // Discord session
-	GuildID string             // Guild ID (Server ID) for command registration
-
-	luaMgr *lua.LuaManager // Lua script manager
+	Session        *discordgo.Session // Discord session
+	GuildID        string             // Guild ID (Server ID) for command registration
+	StatePath      string             // File Lua state is persisted to, empty for in-memory state
+	AuditChannelID string             // Channel command invocations are posted to, empty to not post them
+	GlobalCommands bool               // Whether commands are registered globally instead of in the guild
+	EveryGuild     bool               // Whether commands are registered in every guild the bot is in
+	CacheSize      int                // Most entries the Lua cache holds, 0 for the default
+
+	luaMgr          *lua.LuaManager  // Lua script manager
+	intents         discordgo.Intent // Gateway intents declared in the configuration
+	explicitIntents bool             // Whether intents were declared, rather than derived from the scripts
 }
 
 // NewBot initializes a new bot instance with the given Discord token.
@@ -33,6 +42,61 @@ func (b *Bot) SetGuildID(guildID string) {
 	b.GuildID = guildID
 }
 
+// SetStatePath sets the file Lua state is persisted to across restarts.
+func (b *Bot) SetStatePath(path string) {
+	b.StatePath = path
+}
+
+// SetCommandScope sets whether commands are registered globally, or in every
+// guild the bot is in, rather than in the guild where changes show up instantly.
+func (b *Bot) SetCommandScope(global, everyGuild bool) {
+	b.GlobalCommands = global
+	b.EveryGuild = everyGuild
+}
+
+// SetCacheSize sets how many entries the Lua cache holds before it evicts
+// the least recently used ones.
+func (b *Bot) SetCacheSize(size int) {
+	b.CacheSize = size
+}
+
+// SetSendRetries sets how often message bindings retry a request Discord
+// rate limited before failing it.
+func (b *Bot) SetSendRetries(retries int) {
+	utils.SetSendRetries(retries)
+}
+
+// SetAuditChannel sets the channel every command invocation is posted to.
+func (b *Bot) SetAuditChannel(channelID string) {
+	b.AuditChannelID = channelID
+}
+
+// SetDryRun makes destructive bindings log and preview their changes
+// without making them, for testing scripts against a live guild.
+func (b *Bot) SetDryRun(enabled bool) {
+	utils.SetDryRun(enabled)
+	if enabled {
+		slog.Warn("Dry run mode is enabled, destructive bindings will not make changes")
+	}
+}
+
+// SetIntents declares the gateway intents as a comma separated list of names,
+// such as "default,message_content". An empty list keeps the default of every
+// non-privileged intent plus the intents the loaded scripts need.
+func (b *Bot) SetIntents(spec string) error {
+	if strings.TrimSpace(spec) == "" {
+		return nil
+	}
+
+	intents, err := parseIntents(spec)
+	if err != nil {
+		return err
+	}
+	b.intents = intents
+	b.explicitIntents = true
+	return nil
+}
+
 // Start opens the Discord WebSocket connection and registers event handlers.
 // It also loads Lua scripts to initialize commands and events.
 func (b *Bot) Start(path string) error {
@@ -44,9 +108,16 @@ func (b *Bot) Start(path string) error {
 		return err
 	}
 
+	b.applyIntents()
+
 	// Register the command interaction handler
 	b.Session.AddHandler(b.luaMgr.ReadyHandler)
 	b.Session.AddHandler(b.commandHandler)
+	b.Session.AddHandler(b.luaMgr.MessageCreateHandler)
+	b.Session.AddHandler(b.luaMgr.ReactionAddHandler)
+	b.Session.AddHandler(b.luaMgr.ReactionRemoveHandler)
+	b.Session.AddHandler(b.luaMgr.GuildCreateHandler)
+	b.Session.AddHandler(b.luaMgr.GuildDeleteHandler)
 
 	// Open the session
 	if err := b.Session.Open(); err != nil {
@@ -58,13 +129,41 @@ func (b *Bot) Start(path string) error {
 	return nil
 }
 
-// Stop gracefully closes the Discord session.
+// Stop gracefully closes the Discord session, then saves state changes that
+// are still waiting to be written.
 func (b *Bot) Stop() {
 	slog.Info("Stopping bot session")
 	err := b.Session.Close()
 	if err != nil {
 		slog.Error("Failed to close Discord session", "error", err)
 	}
+
+	if b.luaMgr != nil {
+		b.luaMgr.StateManager.Flush()
+	}
+}
+
+// applyIntents sets the gateway intents to identify with. Intents needed by
+// the bindings the scripts use are requested automatically, unless intents
+// were declared explicitly, in which case missing ones are only warned about.
+func (b *Bot) applyIntents() {
+	required := b.luaMgr.RequiredIntents()
+
+	if !b.explicitIntents {
+		b.Session.Identify.Intents = discordgo.IntentsAllWithoutPrivileged | required
+		for intent, name := range privilegedIntents {
+			if required&intent == intent {
+				slog.Info("Requesting privileged intent needed by Lua scripts, it must be enabled in the developer portal", "intent", name)
+			}
+		}
+	} else {
+		b.Session.Identify.Intents = b.intents
+		if missing := required &^ b.intents; missing != 0 {
+			slog.Warn("Lua scripts use events that need intents which are not enabled, they will never fire", "missing", intentList(missing))
+		}
+	}
+
+	slog.Info("Gateway intents configured", "intents", intentList(b.Session.Identify.Intents))
 }
 
 // commandHandler processes incoming interactions and routes them to Lua-defined commands.
@@ -79,6 +178,19 @@ func (b *Bot) commandHandler(s *discordgo.Session, i *discordgo.InteractionCreat
 func (b *Bot) loadLuaScripts(path string) error {
 	// Initialize the Lua manager with the bot's session and Guild ID
 	b.luaMgr = lua.NewManager(b.Session, b.GuildID)
+	b.luaMgr.Audit.SetChannel(b.AuditChannelID)
+	b.luaMgr.Commands.SetGlobal(b.GlobalCommands)
+	b.luaMgr.Commands.SetEveryGuild(b.EveryGuild)
+	if b.CacheSize > 0 {
+		b.luaMgr.Cache.SetCapacity(b.CacheSize)
+	}
+
+	// Restore persisted state before any script can read it
+	if b.StatePath != "" {
+		if err := b.luaMgr.StateManager.EnablePersistence(b.StatePath); err != nil {
+			return err
+		}
+	}
 
 	// Load Lua scripts from the configured directory
 	if err := b.luaMgr.LoadScripts(path); err != nil {
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// intentNames maps the names accepted in the INTENTS variable to gateway intents.
+var intentNames = map[string]discordgo.Intent{
+	"guilds":                        discordgo.IntentsGuilds,
+	"guild_members":                 discordgo.IntentsGuildMembers,
+	"guild_bans":                    discordgo.IntentsGuildBans,
+	"guild_emojis":                  discordgo.IntentsGuildEmojis,
+	"guild_integrations":            discordgo.IntentsGuildIntegrations,
+	"guild_webhooks":                discordgo.IntentsGuildWebhooks,
+	"guild_invites":                 discordgo.IntentsGuildInvites,
+	"guild_voice_states":            discordgo.IntentsGuildVoiceStates,
+	"guild_presences":               discordgo.IntentsGuildPresences,
+	"guild_messages":                discordgo.IntentsGuildMessages,
+	"guild_message_reactions":       discordgo.IntentsGuildMessageReactions,
+	"guild_message_typing":          discordgo.IntentsGuildMessageTyping,
+	"direct_messages":               discordgo.IntentsDirectMessages,
+	"direct_message_reactions":      discordgo.IntentsDirectMessageReactions,
+	"direct_message_typing":         discordgo.IntentsDirectMessageTyping,
+	"message_content":               discordgo.IntentsMessageContent,
+	"guild_scheduled_events":        discordgo.IntentsGuildScheduledEvents,
+	"auto_moderation_configuration": discordgo.IntentAutoModerationConfiguration,
+	"auto_moderation_execution":     discordgo.IntentAutoModerationExecution,
+	"default":                       discordgo.IntentsAllWithoutPrivileged,
+}
+
+// privilegedIntents must also be enabled for the bot in the developer portal,
+// otherwise Discord refuses the gateway connection.
+var privilegedIntents = map[discordgo.Intent]string{
+	discordgo.IntentsGuildMembers:   "guild_members",
+	discordgo.IntentsGuildPresences: "guild_presences",
+	discordgo.IntentsMessageContent: "message_content",
+}
+
+// parseIntents parses a comma separated list of intent names, such as
+// "default,message_content".
+func parseIntents(spec string) (discordgo.Intent, error) {
+	intents := discordgo.IntentsNone
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		intent, ok := intentNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown intent '%s'", name)
+		}
+		intents |= intent
+	}
+	return intents, nil
+}
+
+// intentList names each intent in the set that has a name of its own.
+func intentList(intents discordgo.Intent) []string {
+	var names []string
+	for name, intent := range intentNames {
+		if name != "default" && intents&intent == intent {
+			names = append(names, name)
+		}
+	}
+	return names
+}
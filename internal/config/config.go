@@ -15,6 +15,14 @@ type Config struct {
 	DiscordToken   string // Discord bot token
 	LuaScriptsPath string // Path to the Lua scripts directory
 	GuildID        string // Guild ID (Server ID) for bot commands
+	StatePath      string // File Lua state is saved to, empty keeps state in memory only
+	Intents        string // Comma separated gateway intents, empty derives them from the scripts
+	DryRun         bool   // Whether destructive bindings only preview their changes
+	AuditChannelID string // Channel command invocations are posted to, empty to not post them
+	GlobalCommands bool   // Whether commands are registered globally instead of in the guild
+	EveryGuild     bool   // Whether commands are registered in every guild the bot is in
+	CacheSize      int    // Most entries the Lua cache holds, 0 for the default
+	SendRetries    int    // How often rate limited message requests are retried
 }
 
 // Load loads the configuration from environment variables and `.env` files.
@@ -31,6 +39,42 @@ func Load() (*Config, error) {
 		DiscordToken:   os.Getenv("DISCORD_TOKEN"),
 		LuaScriptsPath: getEnvOrDefault("LUA_SCRIPTS_PATH", "/lua"),
 		GuildID:        os.Getenv("GUILD_ID"),
+		StatePath:      os.Getenv("STATE_PATH"),
+		Intents:        os.Getenv("INTENTS"),
+		AuditChannelID: os.Getenv("AUDIT_CHANNEL_ID"),
+	}
+
+	if value := os.Getenv("DRY_RUN"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("DRY_RUN must be a boolean: %s", value)
+		}
+		cfg.DryRun = dryRun
+	}
+
+	if value := os.Getenv("CACHE_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("CACHE_SIZE must be a positive integer: %s", value)
+		}
+		cfg.CacheSize = size
+	}
+
+	value := getEnvOrDefault("SEND_RETRIES", "3")
+	retries, err := strconv.Atoi(value)
+	if err != nil || retries < 0 {
+		return nil, fmt.Errorf("SEND_RETRIES must be a non-negative integer: %s", value)
+	}
+	cfg.SendRetries = retries
+
+	switch scope := getEnvOrDefault("COMMAND_SCOPE", "guild"); scope {
+	case "guild":
+	case "global":
+		cfg.GlobalCommands = true
+	case "guilds":
+		cfg.EveryGuild = true
+	default:
+		return nil, fmt.Errorf("COMMAND_SCOPE must be 'guild', 'guilds' or 'global': %s", scope)
 	}
 
 	// Validate required fields
@@ -38,7 +82,7 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
-	slog.Info("Configuration loaded successfully", "LuaScriptsPath", cfg.LuaScriptsPath, "GuildID", cfg.GuildID)
+	slog.Info("Configuration loaded successfully", "LuaScriptsPath", cfg.LuaScriptsPath, "GuildID", cfg.GuildID, "StatePath", cfg.StatePath, "DryRun", cfg.DryRun, "GlobalCommands", cfg.GlobalCommands, "EveryGuild", cfg.EveryGuild, "CacheSize", cfg.CacheSize, "SendRetries", cfg.SendRetries)
 	return cfg, nil
 }
 
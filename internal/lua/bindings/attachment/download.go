@@ -0,0 +1,115 @@
+package attachment
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// defaultMaxSize caps downloads at 8 MiB unless a smaller limit is requested.
+	defaultMaxSize = 8 * 1024 * 1024
+
+	downloadTimeout = 30 * time.Second
+)
+
+// allowedHosts restricts downloads to Discord's attachment CDNs, so scripts
+// cannot be tricked into fetching arbitrary URLs from user input.
+var allowedHosts = map[string]bool{
+	"cdn.discordapp.com":   true,
+	"media.discordapp.net": true,
+}
+
+// AttachmentBindingDownload provides Lua bindings for downloading attachment contents.
+type AttachmentBindingDownload struct {
+	Client *http.Client
+}
+
+// NewAttachmentBindingDownload initializes a new attachment download instance.
+func NewAttachmentBindingDownload() *AttachmentBindingDownload {
+	slog.Debug("Creating new AttachmentBindingDownload")
+	return &AttachmentBindingDownload{
+		Client: &http.Client{Timeout: downloadTimeout},
+	}
+}
+
+// Name returns the name of the binding.
+func (b *AttachmentBindingDownload) Name() string {
+	return "download"
+}
+
+func (b *AttachmentBindingDownload) SetSession(session *discordgo.Session) {}
+
+// Register registers the attachment-related functions in the Lua state. The
+// function returns the file contents as a string and its content type.
+func (b *AttachmentBindingDownload) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		rawURL := L.CheckString(1)
+		maxSize := L.OptInt(2, defaultMaxSize)
+		if maxSize <= 0 || maxSize > defaultMaxSize {
+			L.ArgError(2, fmt.Sprintf("max size must be between 1 and %d bytes", defaultMaxSize))
+			return 0
+		}
+
+		parsed, err := url.Parse(rawURL)
+		if err != nil || parsed.Scheme != "https" || !allowedHosts[parsed.Hostname()] {
+			L.ArgError(1, "url must be a Discord attachment URL")
+			return 0
+		}
+
+		content, contentType, err := b.download(parsed.String(), int64(maxSize))
+		if err != nil {
+			slog.Error("Failed to download attachment", "url", rawURL, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to download attachment: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(lua.LString(content))
+		L.Push(lua.LString(contentType))
+		return 2
+	}
+}
+
+// download fetches the URL, refusing bodies larger than maxSize bytes.
+func (b *AttachmentBindingDownload) download(rawURL string, maxSize int64) ([]byte, string, error) {
+	resp, err := b.Client.Get(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if resp.ContentLength > maxSize {
+		return nil, "", fmt.Errorf("attachment is %d bytes, the limit is %d", resp.ContentLength, maxSize)
+	}
+
+	// Read one byte past the limit to detect bodies without a Content-Length.
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(content)) > maxSize {
+		return nil, "", fmt.Errorf("attachment exceeds the limit of %d bytes", maxSize)
+	}
+
+	return content, resp.Header.Get("Content-Type"), nil
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *AttachmentBindingDownload) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *AttachmentBindingDownload) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// AuditBindingRecent provides Lua bindings for reading recent command invocations.
+type AuditBindingRecent struct {
+	Audit *utils.AuditLog
+}
+
+// NewAuditBindingRecent initializes a new audit recent instance.
+func NewAuditBindingRecent(audit *utils.AuditLog) *AuditBindingRecent {
+	slog.Debug("Creating new AuditBindingRecent")
+	return &AuditBindingRecent{
+		Audit: audit,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *AuditBindingRecent) Name() string {
+	return "recent"
+}
+
+func (b *AuditBindingRecent) SetSession(session *discordgo.Session) {}
+
+// Register adds the audit-related functions to the Lua state. The returned
+// array lists the most recent invocations first.
+func (b *AuditBindingRecent) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		n := L.OptInt(1, 20)
+		if n < 1 || n > utils.MaxAuditEntries {
+			L.ArgError(1, fmt.Sprintf("count must be between 1 and %d", utils.MaxAuditEntries))
+			return 0
+		}
+
+		entriesTable := L.NewTable()
+		for _, entry := range b.Audit.Recent(n) {
+			entryTable := L.NewTable()
+			entryTable.RawSetString("command", lua.LString(entry.Command))
+			entryTable.RawSetString("user_id", lua.LString(entry.UserID))
+			entryTable.RawSetString("channel_id", lua.LString(entry.ChannelID))
+			entryTable.RawSetString("timestamp", lua.LNumber(entry.At.Unix()))
+			entryTable.RawSetString("success", lua.LBool(entry.Success))
+			entriesTable.Append(entryTable)
+		}
+
+		L.Push(entriesTable)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *AuditBindingRecent) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *AuditBindingRecent) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,79 @@
+package bindings
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// autocompleteCache keeps the choices suggested for what was typed in an
+// option, so repeated keystrokes within the option's TTL are answered without
+// calling its handler again. Autocomplete fires on every character, which adds
+// up quickly for handlers querying a slow backend.
+type autocompleteCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedChoices
+}
+
+// cachedChoices is the suggestion for one input, kept until it expires.
+type cachedChoices struct {
+	choices []*discordgo.ApplicationCommandOptionChoice
+	expires time.Time
+}
+
+// newAutocompleteCache initializes an empty autocomplete cache.
+func newAutocompleteCache() *autocompleteCache {
+	return &autocompleteCache{
+		entries: make(map[string]cachedChoices),
+	}
+}
+
+// get returns the choices cached for the input of an option, keyed by
+// `command/option`.
+func (c *autocompleteCache) get(option, input string) ([]*discordgo.ApplicationCommandOptionChoice, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[autocompleteCacheKey(option, input)]
+	if !exists || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.choices, true
+}
+
+// set caches the choices suggested for the input of an option for ttl.
+func (c *autocompleteCache) set(option, input string, choices []*discordgo.ApplicationCommandOptionChoice, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Expired entries are dropped here so the map doesn't grow forever
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[autocompleteCacheKey(option, input)] = cachedChoices{choices: choices, expires: now.Add(ttl)}
+}
+
+// clear forgets every input cached for an option, such as when its handler
+// is replaced.
+func (c *autocompleteCache) clear(option string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := autocompleteCacheKey(option, "")
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// autocompleteCacheKey joins an option and its input into a cache key. The
+// separator can't appear in command or option names.
+func autocompleteCacheKey(option, input string) string {
+	return option + "\x00" + input
+}
@@ -0,0 +1,229 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// defaultAwaitComponentTimeout is how long `await_component` waits when no timeout is given.
+const defaultAwaitComponentTimeout = 60 * time.Second
+
+// componentWaiter is a pending `await_component` call.
+type componentWaiter struct {
+	messageID string
+	userID    string // Empty accepts clicks from anyone
+	handler   string // Lua global holding the callback
+	timer     *time.Timer
+
+	// native replaces the Lua callback for waits started from Go; it is
+	// called on the Lua runner with the interaction, or nil on timeout.
+	native func(L *lua.LState, interaction *discordgo.InteractionCreate)
+}
+
+// AwaitComponentBinding implements the `await_component` Lua function, which
+// calls back once a button or select menu on a message is used, or when the
+// wait times out. While a wait is pending it takes precedence over handlers
+// registered with `register_interaction` for components on that message.
+type AwaitComponentBinding struct {
+	Session *discordgo.Session
+
+	mu      sync.Mutex
+	waiters map[string]*componentWaiter // Keyed by message ID
+	nextID  int
+}
+
+// NewAwaitComponentBinding creates a new AwaitComponentBinding.
+func NewAwaitComponentBinding() *AwaitComponentBinding {
+	slog.Debug("Creating new AwaitComponentBinding")
+	return &AwaitComponentBinding{
+		waiters: make(map[string]*componentWaiter),
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *AwaitComponentBinding) Name() string {
+	return "await_component"
+}
+
+func (b *AwaitComponentBinding) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register creates the `await_component` Lua function. Waiting on a message
+// again replaces the previous wait, which is resolved as timed out.
+func (b *AwaitComponentBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		messageID := L.CheckString(1)
+		options := L.OptTable(2, nil)
+		callback := L.CheckFunction(3)
+
+		// Only the user who triggered the current interaction may respond by default
+		userID := utils.GetLuaRunner().CurrentUser()
+		wait := defaultAwaitComponentTimeout
+		if options != nil {
+			if raw := options.RawGetString("user_id"); raw != lua.LNil {
+				if raw.Type() != lua.LTString {
+					L.ArgError(2, "'user_id' in options must be a string")
+					return 0
+				}
+				userID = raw.String()
+			}
+			if raw := options.RawGetString("timeout"); raw != lua.LNil {
+				seconds, ok := raw.(lua.LNumber)
+				wait = time.Duration(float64(seconds) * float64(time.Second))
+				if !ok || wait <= 0 || wait > maxAwaitTimeout {
+					L.ArgError(2, fmt.Sprintf("'timeout' in options must be between 0 and %d seconds", int(maxAwaitTimeout.Seconds())))
+					return 0
+				}
+			}
+		}
+
+		b.mu.Lock()
+		waiter := &componentWaiter{
+			messageID: messageID,
+			userID:    userID,
+			handler:   fmt.Sprintf("await_component_handler_%d", b.nextID),
+		}
+		b.nextID++
+		b.mu.Unlock()
+
+		L.SetGlobal(waiter.handler, callback)
+		b.add(waiter, wait)
+		return 0
+	}
+}
+
+// awaitNative waits for a component on a message to be used by the user, for
+// bindings built on top of `await_component`. The callback runs on the Lua
+// runner with the interaction, or with nil when the wait times out.
+func (b *AwaitComponentBinding) awaitNative(messageID, userID string, wait time.Duration, callback func(L *lua.LState, interaction *discordgo.InteractionCreate)) {
+	b.add(&componentWaiter{
+		messageID: messageID,
+		userID:    userID,
+		native:    callback,
+	}, wait)
+}
+
+// add starts the waiter's timeout and makes it the pending wait on its message.
+func (b *AwaitComponentBinding) add(waiter *componentWaiter, wait time.Duration) {
+	b.mu.Lock()
+	waiter.timer = time.AfterFunc(wait, func() {
+		if b.remove(waiter) {
+			slog.Debug("Await component timed out", "message_id", waiter.messageID)
+			b.resolve(waiter, nil)
+		}
+	})
+	previous := b.waiters[waiter.messageID]
+	b.waiters[waiter.messageID] = waiter
+	b.mu.Unlock()
+
+	if previous != nil && previous.timer.Stop() {
+		b.resolve(previous, nil)
+	}
+}
+
+// CanHandleInteraction matches component interactions on a message with a pending wait.
+func (b *AwaitComponentBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	if interaction.Type != discordgo.InteractionMessageComponent || interaction.Message == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, exists := b.waiters[interaction.Message.ID]
+	return exists
+}
+
+// HandleInteraction resolves the wait on the interaction's message. Other
+// users are told the components aren't meant for them, and the wait goes on.
+func (b *AwaitComponentBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	b.mu.Lock()
+	waiter, exists := b.waiters[interaction.Message.ID]
+	if !exists {
+		b.mu.Unlock()
+		return fmt.Errorf("no pending wait on message '%s'", interaction.Message.ID)
+	}
+	if waiter.userID != "" && waiter.userID != utils.InteractionUserID(interaction) {
+		b.mu.Unlock()
+
+		if err := b.Session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "These controls aren't for you.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}); err != nil {
+			slog.Error("Failed to reject awaited component interaction", "error", err)
+		}
+		return nil
+	}
+	delete(b.waiters, interaction.Message.ID)
+	b.mu.Unlock()
+
+	waiter.timer.Stop()
+	b.resolve(waiter, interaction)
+	return nil
+}
+
+// remove drops a waiter, reporting whether it was still pending.
+func (b *AwaitComponentBinding) remove(waiter *componentWaiter) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.waiters[waiter.messageID] != waiter {
+		return false
+	}
+	delete(b.waiters, waiter.messageID)
+	return true
+}
+
+// resolve calls the waiter's callback with the component interaction, or
+// with nil when it timed out, then removes the callback global.
+func (b *AwaitComponentBinding) resolve(waiter *componentWaiter, interaction *discordgo.InteractionCreate) {
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		if waiter.native != nil {
+			waiter.native(L, interaction)
+			return
+		}
+
+		fn := L.GetGlobal(waiter.handler)
+		L.SetGlobal(waiter.handler, lua.LNil)
+
+		if interaction == nil {
+			if err := L.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, lua.LNil); err != nil {
+				slog.Error("Error executing Lua await_component callback", "error", err)
+			}
+			return
+		}
+
+		data := interaction.MessageComponentData()
+		interactionTable, state := utils.PrepareInteractionTable(L, b.Session, interaction)
+		interactionTable.RawSetString("custom_id", lua.LString(data.CustomID))
+		if data.Values != nil {
+			valuesTable := L.NewTable()
+			for _, value := range data.Values {
+				valuesTable.Append(lua.LString(value))
+			}
+			interactionTable.RawSetString("values", valuesTable)
+		}
+		if selected := utils.PrepareSelectedTable(L, data); selected != nil {
+			interactionTable.RawSetString("selected", selected)
+		}
+
+		var err error
+		utils.GetLuaRunner().WithInteraction(interaction, func() {
+			err = L.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, interactionTable)
+		})
+		utils.EnsureResponded(b.Session, interaction, state, waiter.handler)
+		utils.ReleaseInteraction(interactionTable, state)
+		if err != nil {
+			slog.Error("Error executing Lua await_component callback", "error", err, "custom_id", data.CustomID)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxAwaitTimeout bounds how long a script can wait for a reply.
+const maxAwaitTimeout = 15 * time.Minute
+
+// messageWaiter is a pending `await_message` call.
+type messageWaiter struct {
+	channelID string
+	userID    string
+	handler   string // Lua global holding the callback
+	timer     *time.Timer
+}
+
+// AwaitMessageBinding implements the `await_message` Lua function, which
+// calls back once a user sends a message in a channel, or when the wait
+// times out. The runner is never blocked while waiting.
+type AwaitMessageBinding struct {
+	Session *discordgo.Session
+
+	mu      sync.Mutex
+	waiters []*messageWaiter // In the order the waits started
+	nextID  int
+
+	warnIntents sync.Once
+}
+
+// NewAwaitMessageBinding creates a new AwaitMessageBinding.
+func NewAwaitMessageBinding() *AwaitMessageBinding {
+	slog.Debug("Creating new AwaitMessageBinding")
+	return &AwaitMessageBinding{}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *AwaitMessageBinding) Name() string {
+	return "await_message"
+}
+
+func (b *AwaitMessageBinding) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register creates the `await_message` Lua function.
+func (b *AwaitMessageBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID := L.CheckString(1)
+		userID := L.CheckString(2)
+		timeout := L.CheckNumber(3)
+		callback := L.CheckFunction(4)
+
+		wait := time.Duration(float64(timeout) * float64(time.Second))
+		if wait <= 0 || wait > maxAwaitTimeout {
+			L.ArgError(3, fmt.Sprintf("timeout must be between 0 and %d seconds", int(maxAwaitTimeout.Seconds())))
+			return 0
+		}
+
+		b.checkIntents()
+
+		b.mu.Lock()
+		waiter := &messageWaiter{
+			channelID: channelID,
+			userID:    userID,
+			handler:   fmt.Sprintf("await_message_handler_%d", b.nextID),
+		}
+		b.nextID++
+		L.SetGlobal(waiter.handler, callback)
+		waiter.timer = time.AfterFunc(wait, func() {
+			if b.remove(waiter) {
+				slog.Debug("Await message timed out", "channel_id", channelID, "user_id", userID)
+				b.resolve(waiter, nil)
+			}
+		})
+		b.waiters = append(b.waiters, waiter)
+		b.mu.Unlock()
+
+		return 0
+	}
+}
+
+// checkIntents warns once when the gateway intents can't deliver the messages
+// being waited for. Waits only start while handling events, after the
+// connection is identified, so unlike other bindings the intents can't be
+// requested up front.
+func (b *AwaitMessageBinding) checkIntents() {
+	if b.Session == nil {
+		return
+	}
+
+	b.warnIntents.Do(func() {
+		needed := discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+		if b.Session.Identify.Intents&needed != needed {
+			slog.Warn("await_message needs the guild_messages and message_content intents, set INTENTS to include them")
+		}
+	})
+}
+
+// HandleMessage resolves the oldest wait matching the message's channel and author.
+func (b *AwaitMessageBinding) HandleMessage(message *discordgo.MessageCreate) {
+	if message.Author == nil {
+		return
+	}
+
+	b.mu.Lock()
+	var matched *messageWaiter
+	for idx, waiter := range b.waiters {
+		if waiter.channelID == message.ChannelID && waiter.userID == message.Author.ID {
+			matched = waiter
+			b.waiters = append(b.waiters[:idx], b.waiters[idx+1:]...)
+			break
+		}
+	}
+	b.mu.Unlock()
+
+	if matched == nil {
+		return
+	}
+	matched.timer.Stop()
+	b.resolve(matched, message.Message)
+}
+
+// remove drops a waiter, reporting whether it was still pending.
+func (b *AwaitMessageBinding) remove(target *messageWaiter) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for idx, waiter := range b.waiters {
+		if waiter == target {
+			b.waiters = append(b.waiters[:idx], b.waiters[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// resolve calls the waiter's callback with the message content and table,
+// or with nil when it timed out, then removes the callback global.
+func (b *AwaitMessageBinding) resolve(waiter *messageWaiter, message *discordgo.Message) {
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		fn := L.GetGlobal(waiter.handler)
+		L.SetGlobal(waiter.handler, lua.LNil)
+
+		args := []lua.LValue{lua.LNil, lua.LNil}
+		if message != nil {
+			args = []lua.LValue{lua.LString(message.Content), utils.PrepareMessageTable(L, message, message.GuildID)}
+		}
+
+		if err := L.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    0,
+			Protect: true,
+		}, args...); err != nil {
+			slog.Error("Error executing Lua await_message callback", "error", err)
+		}
+	})
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *AwaitMessageBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *AwaitMessageBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -12,3 +12,30 @@ type LuaBinding interface {
 	HandleInteraction(interaction *discordgo.InteractionCreate) error
 	CanHandleInteraction(interaction *discordgo.InteractionCreate) bool
 }
+
+// IntentBinding is implemented by bindings that need gateway intents to
+// receive their events. It is asked once the scripts have finished running on
+// the Lua runner, before the session opens, so a binding can require intents
+// only once a script actually uses it.
+type IntentBinding interface {
+	RequiredIntents() discordgo.Intent
+}
+
+// MessageHandler is implemented by bindings that react to messages sent in
+// channels the bot can see.
+type MessageHandler interface {
+	HandleMessage(message *discordgo.MessageCreate)
+}
+
+// ReactionHandler is implemented by bindings that react to reactions being
+// added to or removed from messages.
+type ReactionHandler interface {
+	HandleReaction(reaction *discordgo.MessageReaction, added bool)
+}
+
+// ResettableBinding is implemented by bindings that hold handlers registered
+// by scripts. They are reset before the scripts are reloaded, so handlers of
+// removed scripts stop running and re-registered ones aren't doubled up.
+type ResettableBinding interface {
+	Reset(L *lua.LState)
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// BotBindingPing provides Lua bindings for measuring the latency to Discord.
+type BotBindingPing struct {
+	Session *discordgo.Session
+}
+
+// NewBotBindingPing initializes a new bot ping instance.
+func NewBotBindingPing() *BotBindingPing {
+	slog.Debug("Creating new BotBindingPing")
+	return &BotBindingPing{}
+}
+
+// Name returns the name of the binding.
+func (b *BotBindingPing) Name() string {
+	return "ping"
+}
+
+func (b *BotBindingPing) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the bot-related functions in the Lua state.
+func (b *BotBindingPing) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		opts := L.OptTable(1, nil)
+
+		rest := false
+		if opts != nil {
+			if raw := opts.RawGetString("rest"); raw != lua.LNil {
+				if raw.Type() != lua.LTBool {
+					L.ArgError(1, "options.rest must be a boolean")
+					return 0
+				}
+				rest = lua.LVAsBool(raw)
+			}
+		}
+
+		pingTable := L.NewTable()
+		pingTable.RawSetString("gateway_ms", lua.LNumber(b.Session.HeartbeatLatency().Milliseconds()))
+
+		// Fetching the bot's own user is the cheapest request, so its round
+		// trip is close to the bare API latency
+		if rest {
+			started := time.Now()
+			if _, err := b.Session.User("@me"); err != nil {
+				slog.Error("Failed to measure REST latency", "error", err)
+				L.Push(lua.LNil)
+				L.Push(lua.LString(fmt.Sprintf("Failed to measure REST latency: %s", err.Error())))
+				return 2
+			}
+			pingTable.RawSetString("rest_ms", lua.LNumber(time.Since(started).Milliseconds()))
+		}
+
+		L.Push(pingTable)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *BotBindingPing) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *BotBindingPing) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
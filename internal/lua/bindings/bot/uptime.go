@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// BotBindingUptime provides Lua bindings for reading how long the bot has
+// been connected.
+type BotBindingUptime struct {
+	ReadyAt func() time.Time // When the bot first connected, zero until then
+}
+
+// NewBotBindingUptime initializes a new bot uptime instance.
+func NewBotBindingUptime(readyAt func() time.Time) *BotBindingUptime {
+	slog.Debug("Creating new BotBindingUptime")
+	return &BotBindingUptime{
+		ReadyAt: readyAt,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *BotBindingUptime) Name() string {
+	return "uptime"
+}
+
+func (b *BotBindingUptime) SetSession(session *discordgo.Session) {}
+
+// Register registers the bot-related functions in the Lua state. The
+// function returns the uptime in seconds followed by when the bot connected.
+func (b *BotBindingUptime) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		readyAt := b.ReadyAt()
+		if readyAt.IsZero() {
+			L.Push(lua.LNumber(0))
+			L.Push(lua.LNil)
+			return 2
+		}
+
+		L.Push(lua.LNumber(int64(time.Since(readyAt).Seconds())))
+		L.Push(lua.LNumber(readyAt.Unix()))
+		return 2
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *BotBindingUptime) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *BotBindingUptime) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// CacheBindingGet provides Lua bindings for reading the in-memory cache.
+type CacheBindingGet struct {
+	Cache *utils.LRUCache
+}
+
+// NewCacheBindingGet initializes a new cache get instance.
+func NewCacheBindingGet(cache *utils.LRUCache) *CacheBindingGet {
+	slog.Debug("Creating new CacheBindingGet")
+	return &CacheBindingGet{
+		Cache: cache,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *CacheBindingGet) Name() string {
+	return "get"
+}
+
+func (b *CacheBindingGet) SetSession(session *discordgo.Session) {}
+
+// Register adds the cache-related functions to the Lua state.
+func (b *CacheBindingGet) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		key := L.CheckString(1)
+
+		L.Push(b.Cache.Get(key))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *CacheBindingGet) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *CacheBindingGet) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
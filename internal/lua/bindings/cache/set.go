@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// CacheBindingSet provides Lua bindings for writing to the in-memory cache.
+type CacheBindingSet struct {
+	Cache *utils.LRUCache
+}
+
+// NewCacheBindingSet initializes a new cache set instance.
+func NewCacheBindingSet(cache *utils.LRUCache) *CacheBindingSet {
+	slog.Debug("Creating new CacheBindingSet")
+	return &CacheBindingSet{
+		Cache: cache,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *CacheBindingSet) Name() string {
+	return "set"
+}
+
+func (b *CacheBindingSet) SetSession(session *discordgo.Session) {}
+
+// Register adds the cache-related functions to the Lua state.
+func (b *CacheBindingSet) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		key := L.CheckString(1)
+		value := L.CheckAny(2)
+		ttl := L.OptNumber(3, 0) // Optional time to live in seconds
+		if ttl < 0 {
+			L.ArgError(3, "ttl must not be negative")
+			return 0
+		}
+
+		b.Cache.Set(key, value, time.Duration(float64(ttl)*float64(time.Second)))
+		return 0
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *CacheBindingSet) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *CacheBindingSet) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -1,6 +1,7 @@
 package bindings
 
 import (
+	"driftwood/internal/lua/utils"
 	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
@@ -35,22 +36,17 @@ func (b *ChannelBindingGet) Register() lua.LGFunction {
 	return func(L *lua.LState) int {
 		channelName := L.CheckString(1)
 
-		channels, err := b.Session.GuildChannels(b.GuildID)
+		// Names shared by several channels are reported rather than
+		// resolved to whichever comes first
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, channelName)
 		if err != nil {
-			slog.Error("Failed to get channels", "guild_id", b.GuildID, "error", err)
-			L.RaiseError("Failed to get channels: %s", err.Error())
+			slog.Debug("Failed to resolve channel", "guild_id", b.GuildID, "name", channelName, "error", err)
 			L.Push(lua.LNil)
-			return 1
+			L.Push(lua.LString(err.Error()))
+			return 2
 		}
 
-		for _, channel := range channels {
-			if channel.Name == channelName {
-				L.Push(lua.LString(channel.ID))
-				return 1
-			}
-		}
-
-		L.Push(lua.LNil)
+		L.Push(lua.LString(channelID))
 		return 1
 	}
 }
@@ -0,0 +1,54 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ColorBindingRGB provides a Lua helper for building embed colors from RGB components.
+type ColorBindingRGB struct{}
+
+// NewColorBindingRGB initializes a new color rgb instance.
+func NewColorBindingRGB() *ColorBindingRGB {
+	slog.Debug("Creating new ColorBindingRGB")
+	return &ColorBindingRGB{}
+}
+
+// Name returns the name of the binding.
+func (b *ColorBindingRGB) Name() string {
+	return "rgb"
+}
+
+func (b *ColorBindingRGB) SetSession(session *discordgo.Session) {}
+
+// Register creates the `rgb` Lua function, which packs red, green and blue
+// components (0-255) into the integer color value used by embeds.
+func (b *ColorBindingRGB) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		color := 0
+		for idx, name := range []string{"red", "green", "blue"} {
+			component := L.CheckNumber(idx + 1)
+			if component < 0 || component > 255 || component != lua.LNumber(int(component)) {
+				L.ArgError(idx+1, fmt.Sprintf("%s must be an integer between 0 and 255", name))
+				return 0
+			}
+			color = color<<8 | int(component)
+		}
+
+		L.Push(lua.LNumber(color))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ColorBindingRGB) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ColorBindingRGB) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,106 @@
+package bindings
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// commandDiff compares a command registered with Discord against the desired
+// definition and returns a human-readable list of the fields that differ.
+// An empty result means the command does not need to be updated.
+func commandDiff(existing, desired *discordgo.ApplicationCommand) []string {
+	var diffs []string
+
+	if existing.Name != desired.Name {
+		diffs = append(diffs, fmt.Sprintf("name: %q -> %q", existing.Name, desired.Name))
+	}
+	if existing.Description != desired.Description {
+		diffs = append(diffs, fmt.Sprintf("description: %q -> %q", existing.Description, desired.Description))
+	}
+
+	return append(diffs, optionsDiff(desired.Name, existing.Options, desired.Options)...)
+}
+
+// optionsDiff recursively compares two option lists, matching options by position.
+func optionsDiff(path string, existing, desired []*discordgo.ApplicationCommandOption) []string {
+	var diffs []string
+
+	if len(existing) != len(desired) {
+		diffs = append(diffs, fmt.Sprintf("%s: %d options -> %d options", path, len(existing), len(desired)))
+	}
+
+	for idx := 0; idx < len(existing) && idx < len(desired); idx++ {
+		old, cur := existing[idx], desired[idx]
+		optPath := fmt.Sprintf("%s.%s", path, cur.Name)
+
+		if old.Name != cur.Name {
+			diffs = append(diffs, fmt.Sprintf("%s: name %q -> %q", optPath, old.Name, cur.Name))
+		}
+		if old.Description != cur.Description {
+			diffs = append(diffs, fmt.Sprintf("%s: description %q -> %q", optPath, old.Description, cur.Description))
+		}
+		if old.Type != cur.Type {
+			diffs = append(diffs, fmt.Sprintf("%s: type %d -> %d", optPath, old.Type, cur.Type))
+		}
+		if old.Required != cur.Required {
+			diffs = append(diffs, fmt.Sprintf("%s: required %t -> %t", optPath, old.Required, cur.Required))
+		}
+		if old.Autocomplete != cur.Autocomplete {
+			diffs = append(diffs, fmt.Sprintf("%s: autocomplete %t -> %t", optPath, old.Autocomplete, cur.Autocomplete))
+		}
+		if !channelTypesEqual(old.ChannelTypes, cur.ChannelTypes) {
+			diffs = append(diffs, fmt.Sprintf("%s: channel types %v -> %v", optPath, old.ChannelTypes, cur.ChannelTypes))
+		}
+
+		diffs = append(diffs, choicesDiff(optPath, old.Choices, cur.Choices)...)
+		diffs = append(diffs, optionsDiff(optPath, old.Options, cur.Options)...)
+	}
+
+	return diffs
+}
+
+// choicesDiff compares the predefined choices of an option, matching by position.
+func choicesDiff(path string, existing, desired []*discordgo.ApplicationCommandOptionChoice) []string {
+	if len(existing) != len(desired) {
+		return []string{fmt.Sprintf("%s: %d choices -> %d choices", path, len(existing), len(desired))}
+	}
+
+	var diffs []string
+	for idx := range desired {
+		old, cur := existing[idx], desired[idx]
+		if old.Name != cur.Name || fmt.Sprint(old.Value) != fmt.Sprint(cur.Value) {
+			diffs = append(diffs, fmt.Sprintf("%s: choice %q=%v -> %q=%v", path, old.Name, old.Value, cur.Name, cur.Value))
+		}
+		if !stringMapsEqual(old.NameLocalizations, cur.NameLocalizations) {
+			diffs = append(diffs, fmt.Sprintf("%s: choice %q localizations changed", path, cur.Name))
+		}
+	}
+	return diffs
+}
+
+// stringMapsEqual reports whether two localization maps hold the same entries.
+func stringMapsEqual(a, b map[discordgo.Locale]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if b[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// channelTypesEqual reports whether two channel type restrictions are the same.
+func channelTypesEqual(a, b []discordgo.ChannelType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// CommandBindingDisable provides a Lua helper for disableing a command at runtime.
+type CommandBindingDisable struct {
+	commands *ApplicationCommandBinding
+}
+
+// NewCommandBindingDisable initializes a new command disable instance.
+func NewCommandBindingDisable(commands *ApplicationCommandBinding) *CommandBindingDisable {
+	slog.Debug("Creating new CommandBindingDisable")
+	return &CommandBindingDisable{
+		commands: commands,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *CommandBindingDisable) Name() string {
+	return "disable"
+}
+
+func (b *CommandBindingDisable) SetSession(session *discordgo.Session) {}
+
+// Register creates the `disable` Lua function.
+func (b *CommandBindingDisable) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.CheckString(1)
+
+		if err := b.commands.SetEnabled(name, false); err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to disable command: %s", err.Error())))
+			return 2
+		}
+
+		slog.Info("Command disabled", "command", name)
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *CommandBindingDisable) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *CommandBindingDisable) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,56 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// CommandBindingEnable provides a Lua helper for enableing a command at runtime.
+type CommandBindingEnable struct {
+	commands *ApplicationCommandBinding
+}
+
+// NewCommandBindingEnable initializes a new command enable instance.
+func NewCommandBindingEnable(commands *ApplicationCommandBinding) *CommandBindingEnable {
+	slog.Debug("Creating new CommandBindingEnable")
+	return &CommandBindingEnable{
+		commands: commands,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *CommandBindingEnable) Name() string {
+	return "enable"
+}
+
+func (b *CommandBindingEnable) SetSession(session *discordgo.Session) {}
+
+// Register creates the `enable` Lua function.
+func (b *CommandBindingEnable) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.CheckString(1)
+
+		if err := b.commands.SetEnabled(name, true); err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to enable command: %s", err.Error())))
+			return 2
+		}
+
+		slog.Info("Command enabled", "command", name)
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *CommandBindingEnable) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *CommandBindingEnable) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
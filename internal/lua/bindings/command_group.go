@@ -0,0 +1,114 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// CommandGroupBinding implements the `command_group` Lua function, a flatter
+// way to declare a command made of subcommands. It expands the group into
+// the nested options `register_application_command` expects and registers
+// it through the same path.
+type CommandGroupBinding struct {
+	commands *ApplicationCommandBinding
+}
+
+// NewCommandGroupBinding creates a new CommandGroupBinding registering through commands.
+func NewCommandGroupBinding(commands *ApplicationCommandBinding) *CommandGroupBinding {
+	slog.Debug("Creating new CommandGroupBinding")
+	return &CommandGroupBinding{
+		commands: commands,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *CommandGroupBinding) Name() string {
+	return "command_group"
+}
+
+func (b *CommandGroupBinding) SetSession(session *discordgo.Session) {}
+
+// Register creates the `command_group` Lua function. Subcommands are given
+// either as an array of tables with a "name", or as a table keyed by name.
+func (b *CommandGroupBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		group := L.CheckTable(1)
+
+		subcommands, ok := group.RawGetString("subcommands").(*lua.LTable)
+		if !ok {
+			L.ArgError(1, "'subcommands' must be a table")
+			return 0
+		}
+
+		options := L.NewTable()
+		for _, entry := range b.subcommandEntries(L, subcommands) {
+			option := L.NewTable()
+			option.RawSetString("type", lua.LNumber(discordgo.ApplicationCommandOptionSubCommand))
+			option.RawSetString("name", entry.name)
+			option.RawSetString("description", entry.table.RawGetString("description"))
+			option.RawSetString("handler", entry.table.RawGetString("handler"))
+			option.RawSetString("options", entry.table.RawGetString("options"))
+			options.Append(option)
+		}
+
+		command := L.NewTable()
+		command.RawSetString("name", group.RawGetString("name"))
+		command.RawSetString("description", group.RawGetString("description"))
+		command.RawSetString("options", options)
+		command.RawSetString("cooldown", group.RawGetString("cooldown"))
+		command.RawSetString("guild_only", group.RawGetString("guild_only"))
+
+		b.commands.registerCommand(L, command)
+		return 0
+	}
+}
+
+// subcommandEntry is a subcommand table together with its resolved name.
+type subcommandEntry struct {
+	name  lua.LValue
+	table *lua.LTable
+}
+
+// subcommandEntries lists the subcommands in declaration order for arrays,
+// or sorted by name for tables keyed by name.
+func (b *CommandGroupBinding) subcommandEntries(L *lua.LState, subcommands *lua.LTable) []subcommandEntry {
+	var entries []subcommandEntry
+	subcommands.ForEach(func(key, value lua.LValue) {
+		table, ok := value.(*lua.LTable)
+		if !ok {
+			L.ArgError(1, fmt.Sprintf("subcommand '%s' must be a table", key.String()))
+			return
+		}
+
+		name := table.RawGetString("name")
+		if key.Type() == lua.LTString {
+			name = key
+		}
+		if name.Type() != lua.LTString {
+			L.ArgError(1, "each subcommand must have a 'name' string")
+			return
+		}
+		entries = append(entries, subcommandEntry{name: name, table: table})
+	})
+
+	if subcommands.Len() == 0 {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].name.String() < entries[j].name.String()
+		})
+	}
+	return entries
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *CommandGroupBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// Commands declared through a group are handled by the ApplicationCommandBinding
+	return nil
+}
+
+func (b *CommandGroupBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,54 @@
+package bindings
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxRegisterAttempts bounds how often a command request is tried before giving up.
+	maxRegisterAttempts = 5
+
+	// registerBackoff is the wait before the first retry, doubled after each attempt.
+	registerBackoff = time.Second
+)
+
+// isTransientError reports whether a failed request is worth retrying.
+// Server errors and network failures are transient, while other API errors,
+// such as a command failing validation, fail the same way again. Rate limits
+// are already waited out and retried by discordgo.
+func isTransientError(err error) bool {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) {
+		return restErr.Response != nil && restErr.Response.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryTransient calls fn until it succeeds, returns a permanent error, or
+// runs out of attempts, waiting exponentially longer between attempts.
+func retryTransient(operation string, fn func() error) error {
+	backoff := registerBackoff
+
+	var err error
+	for attempt := 1; attempt <= maxRegisterAttempts; attempt++ {
+		if err = fn(); err == nil || !isTransientError(err) {
+			return err
+		}
+		if attempt == maxRegisterAttempts {
+			break
+		}
+
+		slog.Warn("Command request failed, retrying", "operation", operation, "attempt", attempt, "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return err
+}
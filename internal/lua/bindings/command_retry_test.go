@@ -0,0 +1,174 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestIsTransientError(t *testing.T) {
+	restError := func(status int) error {
+		return &discordgo.RESTError{Response: &http.Response{StatusCode: status}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "server error", err: restError(http.StatusInternalServerError), want: true},
+		{name: "unavailable", err: restError(http.StatusServiceUnavailable), want: true},
+		{name: "network error", err: &url.Error{Op: "Get", URL: "https://discord.com", Err: errors.New("connection reset")}, want: true},
+		{name: "wrapped network error", err: fmt.Errorf("fetch: %w", &url.Error{Op: "Get", Err: errors.New("timeout")}), want: true},
+		{name: "rate limit", err: restError(http.StatusTooManyRequests), want: false},
+		{name: "validation error", err: restError(http.StatusBadRequest), want: false},
+		{name: "missing access", err: restError(http.StatusForbidden), want: false},
+		{name: "no response", err: &discordgo.RESTError{}, want: false},
+		{name: "not a request error", err: discordgo.ErrJSONUnmarshal, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientError(tt.err); got != tt.want {
+				t.Errorf("isTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// gatedCommandsAPI answers command requests with queued statuses, once the
+// test releases them. A status of 0 fails the request on the network.
+type gatedCommandsAPI struct {
+	release chan struct{}
+
+	mu       sync.Mutex
+	statuses map[string][]int // Statuses still to answer, per method
+	calls    map[string]int   // Requests received, per method
+	sent     string           // Body of the last bulk overwrite
+}
+
+func (f *gatedCommandsAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-f.release
+
+	f.mu.Lock()
+	f.calls[req.Method]++
+	if req.Method == http.MethodPut {
+		sent, _ := io.ReadAll(req.Body)
+		f.sent = string(sent)
+	}
+	status := http.StatusOK
+	if queued := f.statuses[req.Method]; len(queued) > 0 {
+		status, f.statuses[req.Method] = queued[0], queued[1:]
+	}
+	f.mu.Unlock()
+
+	if status == 0 {
+		return nil, errors.New("connection reset")
+	}
+	body := "[]"
+	if status != http.StatusOK {
+		body = `{"message": "failed", "code": 0}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestSyncCommandsOffRunner(t *testing.T) {
+	tests := []struct {
+		name       string
+		fetch      []int // Statuses of the requests fetching the registered commands
+		overwrite  []int // Statuses of the bulk overwrites
+		wantFetch  int
+		wantUpdate int
+	}{
+		{name: "success", wantFetch: 1, wantUpdate: 1},
+		{name: "server error is retried", overwrite: []int{http.StatusInternalServerError}, wantFetch: 1, wantUpdate: 2},
+		{name: "network error is retried", fetch: []int{0}, wantFetch: 2, wantUpdate: 1},
+		{name: "validation error is not retried", overwrite: []int{http.StatusBadRequest}, wantFetch: 1, wantUpdate: 1},
+		{name: "failed fetch overwrites all", fetch: []int{http.StatusForbidden}, wantFetch: 1, wantUpdate: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &gatedCommandsAPI{
+				release:  make(chan struct{}),
+				statuses: map[string][]int{http.MethodGet: tt.fetch, http.MethodPut: tt.overwrite},
+				calls:    make(map[string]int),
+			}
+			session, _ := discordgo.New("Bot token")
+			session.Client = &http.Client{Transport: api}
+			session.State.User = &discordgo.User{ID: "100"}
+
+			commands := newTestCommandBinding()
+			commands.Session = session
+
+			// The runner carries on while the requests are held
+			runner := utils.GetLuaRunner()
+			runner.Do(func(L *lua.LState) {
+				commands.addDefinition(&discordgo.ApplicationCommand{Name: "ping", Description: "Ping the bot"})
+				commands.syncCommands(session)
+			})
+			runner.Wait()
+
+			close(api.release)
+			commands.syncer.wait()
+
+			if api.calls[http.MethodGet] != tt.wantFetch {
+				t.Errorf("fetched %d times, want %d", api.calls[http.MethodGet], tt.wantFetch)
+			}
+			if api.calls[http.MethodPut] != tt.wantUpdate {
+				t.Errorf("overwrote %d times, want %d", api.calls[http.MethodPut], tt.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestSyncCommandsSendsLatest(t *testing.T) {
+	api := &gatedCommandsAPI{
+		release:  make(chan struct{}),
+		statuses: make(map[string][]int),
+		calls:    make(map[string]int),
+	}
+	session, _ := discordgo.New("Bot token")
+	session.Client = &http.Client{Transport: api}
+	session.State.User = &discordgo.User{ID: "100"}
+
+	commands := newTestCommandBinding()
+	commands.Session = session
+
+	// Registrations while one is in flight collapse into the latest, which
+	// can include the first when it hadn't been picked up yet
+	runner := utils.GetLuaRunner()
+	runner.Do(func(L *lua.LState) {
+		for _, name := range []string{"one", "two", "three"} {
+			commands.addDefinition(&discordgo.ApplicationCommand{Name: name, Description: "A command"})
+			commands.syncCommands(session)
+		}
+	})
+	runner.Wait()
+
+	close(api.release)
+	commands.syncer.wait()
+
+	if overwrites := api.calls[http.MethodPut]; overwrites < 1 || overwrites > 2 {
+		t.Errorf("overwrote %d times, want 1 or 2", overwrites)
+	}
+	for _, name := range []string{"one", "two", "three"} {
+		if !strings.Contains(api.sent, fmt.Sprintf(`"name":"%s"`, name)) {
+			t.Errorf("last overwrite is missing command %q: %s", name, api.sent)
+		}
+	}
+}
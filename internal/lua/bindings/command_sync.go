@@ -0,0 +1,60 @@
+package bindings
+
+import "sync"
+
+// commandSyncer sends command registrations to Discord off the Lua runner,
+// whose handlers would otherwise wait out every retry. Registrations for a
+// guild are sent one at a time, and a registration requested while another
+// is in flight replaces any still waiting, so the last one sent is always
+// the latest the scripts declared.
+type commandSyncer struct {
+	mu      sync.Mutex
+	pending map[string]func() // Waiting registration per guild, "" for global
+	running map[string]bool   // Guilds with a registration in flight
+	idle    sync.WaitGroup    // Done once no registration is in flight
+}
+
+// newCommandSyncer creates a commandSyncer with nothing in flight.
+func newCommandSyncer() *commandSyncer {
+	return &commandSyncer{
+		pending: make(map[string]func()),
+		running: make(map[string]bool),
+	}
+}
+
+// submit sends a registration for a guild in the background.
+func (s *commandSyncer) submit(guildID string, send func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[guildID] = send
+	if s.running[guildID] {
+		return // Picked up once the registration in flight finishes
+	}
+	s.running[guildID] = true
+	s.idle.Add(1)
+	go s.run(guildID)
+}
+
+// run sends the waiting registrations of a guild until none are left.
+func (s *commandSyncer) run(guildID string) {
+	defer s.idle.Done()
+	for {
+		s.mu.Lock()
+		send, ok := s.pending[guildID]
+		delete(s.pending, guildID)
+		if !ok {
+			delete(s.running, guildID)
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		send()
+	}
+}
+
+// wait blocks until every submitted registration has been sent.
+func (s *commandSyncer) wait() {
+	s.idle.Wait()
+}
@@ -0,0 +1,320 @@
+package bindings
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// maxCommandNameLength is the longest name Discord accepts for a command or option.
+	maxCommandNameLength = 32
+
+	// maxCommandDescriptionLength is the longest description Discord accepts for a command or option.
+	maxCommandDescriptionLength = 100
+
+	// maxCommandOptions is the most options Discord accepts on a command or subcommand.
+	maxCommandOptions = 25
+
+	// maxOptionChoices is the most choices Discord accepts on an option.
+	maxOptionChoices = 25
+
+	// maxChoiceLength is the longest name or string value Discord accepts for a choice.
+	maxChoiceLength = 100
+)
+
+// commandNamePattern is the pattern Discord requires chat input command and
+// option names to match.
+var commandNamePattern = regexp.MustCompile(`^[-_\p{L}\p{N}]{1,32}$`)
+
+// validateCommandName checks a command or option name against Discord's
+// rules, so a bad name is reported where it was declared rather than as an
+// opaque API error when the commands are synced.
+func validateCommandName(name string) error {
+	if !commandNamePattern.MatchString(name) {
+		if n := utf8.RuneCountInString(name); n == 0 || n > maxCommandNameLength {
+			return fmt.Errorf("name must be 1-%d characters, got %d", maxCommandNameLength, n)
+		}
+		return fmt.Errorf("name '%s' may only contain letters, numbers, '-' and '_'", name)
+	}
+	if strings.ToLower(name) != name {
+		return fmt.Errorf("name '%s' must be lowercase", name)
+	}
+	return nil
+}
+
+// validateCommandDescription checks a command or option description is
+// within the length Discord accepts.
+func validateCommandDescription(description string) error {
+	if n := utf8.RuneCountInString(description); n == 0 || n > maxCommandDescriptionLength {
+		return fmt.Errorf("description must be 1-%d characters, got %d", maxCommandDescriptionLength, n)
+	}
+	return nil
+}
+
+// validateCommandTable checks a whole command table against what Discord and
+// the command binding accept before any of it is registered. Every problem
+// is collected, so a script author sees all their mistakes after a single
+// reload rather than one at a time.
+func validateCommandTable(command *lua.LTable) []string {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	name, ok := command.RawGetString("name").(lua.LString)
+	if !ok {
+		problem("'name' must be a string")
+	} else if err := validateCommandName(string(name)); err != nil {
+		problem("%s", err)
+	}
+
+	if description, ok := command.RawGetString("description").(lua.LString); !ok {
+		problem("'description' must be a string")
+	} else if err := validateCommandDescription(string(description)); err != nil {
+		problem("%s", err)
+	}
+
+	if handler := command.RawGetString("handler"); handler != lua.LNil && handler.Type() != lua.LTFunction {
+		problem("'handler' must be a function if provided")
+	}
+
+	if cooldown := command.RawGetString("cooldown"); cooldown != lua.LNil {
+		if seconds, ok := cooldown.(lua.LNumber); !ok || seconds <= 0 {
+			problem("'cooldown' must be a positive number of seconds if provided")
+		}
+	}
+
+	if guildOnly := command.RawGetString("guild_only"); guildOnly != lua.LNil && guildOnly.Type() != lua.LTBool {
+		problem("'guild_only' must be a boolean if provided")
+	}
+
+	switch options := command.RawGetString("options").(type) {
+	case *lua.LTable:
+		problems = append(problems, validateOptionTables(string(name), options)...)
+	case *lua.LNilType:
+	default:
+		problem("'options' must be a table if provided")
+	}
+
+	return problems
+}
+
+// validateOptionTables checks the options of a command or subcommand, named
+// by its full path such as "music_play", and the options of any subcommands.
+func validateOptionTables(parentName string, options *lua.LTable) []string {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	seen := make(map[string]bool)
+	count, subcommands, optional := 0, 0, ""
+	options.ForEach(func(key, value lua.LValue) {
+		count++
+		optTable, ok := value.(*lua.LTable)
+		if !ok {
+			problem("option %s of '%s' must be a table", key.String(), parentName)
+			return
+		}
+
+		rawName := optTable.RawGetString("name")
+		name, ok := rawName.(lua.LString)
+		path := fmt.Sprintf("%s/%s", parentName, rawName.String())
+		if !ok {
+			path = fmt.Sprintf("%s/%s", parentName, key.String())
+			problem("option '%s': 'name' must be a string", path)
+		} else if err := validateCommandName(string(name)); err != nil {
+			problem("option '%s': %s", path, err)
+		} else if seen[string(name)] {
+			problem("option '%s': name is used by another option", path)
+		}
+		seen[string(name)] = true
+
+		if description, ok := optTable.RawGetString("description").(lua.LString); !ok {
+			problem("option '%s': 'description' must be a string", path)
+		} else if err := validateCommandDescription(string(description)); err != nil {
+			problem("option '%s': %s", path, err)
+		}
+
+		number, ok := optTable.RawGetString("type").(lua.LNumber)
+		if !ok {
+			problem("option '%s': 'type' must be a number", path)
+			return
+		}
+		optionType := discordgo.ApplicationCommandOptionType(number)
+		switch {
+		case optionType == discordgo.ApplicationCommandOptionSubCommandGroup:
+			problem("option '%s': subcommand groups are not supported, use subcommands", path)
+			return
+		case lua.LNumber(optionType) != number || optionType < discordgo.ApplicationCommandOptionSubCommand || optionType > discordgo.ApplicationCommandOptionAttachment:
+			problem("option '%s': unknown option type %s", path, number.String())
+			return
+		}
+
+		required := optTable.RawGetString("required")
+		if required != lua.LNil && required.Type() != lua.LTBool {
+			problem("option '%s': 'required' must be a boolean", path)
+		}
+
+		if optionType == discordgo.ApplicationCommandOptionSubCommand {
+			subcommands++
+			if optTable.RawGetString("handler").Type() != lua.LTFunction {
+				problem("subcommand '%s' must have a 'handler' function", path)
+			}
+			switch subOptions := optTable.RawGetString("options").(type) {
+			case *lua.LTable:
+				problems = append(problems, validateOptionTables(parentName+"_"+string(name), subOptions)...)
+			case *lua.LNilType:
+			default:
+				problem("subcommand '%s': 'options' must be a table if provided", path)
+			}
+			return
+		}
+
+		// Discord lists required options first, and rejects commands that don't
+		if lua.LVAsBool(required) && optional != "" {
+			problem("option '%s': required options must come before optional option '%s'", path, optional)
+		} else if !lua.LVAsBool(required) && optional == "" {
+			optional = rawName.String()
+		}
+
+		problems = append(problems, validateOptionFields(path, optionType, optTable)...)
+	})
+
+	if subcommands > 0 && subcommands != count {
+		problem("'%s' cannot mix subcommands with other options", parentName)
+	}
+	if count > maxCommandOptions {
+		problem("'%s' has %d options, the maximum is %d", parentName, count, maxCommandOptions)
+	}
+
+	return problems
+}
+
+// validateOptionFields checks the fields of an option that depend on its
+// type: choices, channel types, autocomplete and required_if.
+func validateOptionFields(path string, optionType discordgo.ApplicationCommandOptionType, optTable *lua.LTable) []string {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	choices := optTable.RawGetString("choices")
+	if choices != lua.LNil {
+		choicesTable, ok := choices.(*lua.LTable)
+		switch {
+		case !ok:
+			problem("option '%s': 'choices' must be a table", path)
+		case optionType != discordgo.ApplicationCommandOptionString && optionType != discordgo.ApplicationCommandOptionInteger && optionType != discordgo.ApplicationCommandOptionNumber:
+			problem("option '%s' does not support choices", path)
+		default:
+			problems = append(problems, validateChoiceTables(path, optionType, choicesTable)...)
+		}
+	}
+
+	if types := optTable.RawGetString("channel_types"); types != lua.LNil {
+		names, ok := types.(*lua.LTable)
+		switch {
+		case !ok:
+			problem("option '%s': 'channel_types' must be a table", path)
+		case optionType != discordgo.ApplicationCommandOptionChannel:
+			problem("option '%s' is not a channel option and cannot have 'channel_types'", path)
+		default:
+			for i := 1; i <= names.Len(); i++ {
+				if _, known := channelTypeByName(names.RawGetInt(i).String()); !known {
+					problem("option '%s': unknown channel type '%s'", path, names.RawGetInt(i).String())
+				}
+			}
+		}
+	}
+
+	if autocomplete := optTable.RawGetString("autocomplete"); autocomplete != lua.LNil {
+		switch {
+		case autocomplete.Type() != lua.LTFunction:
+			problem("option '%s': 'autocomplete' must be a function", path)
+		case optionType != discordgo.ApplicationCommandOptionString && optionType != discordgo.ApplicationCommandOptionInteger && optionType != discordgo.ApplicationCommandOptionNumber:
+			problem("option '%s' does not support autocomplete", path)
+		case choices != lua.LNil:
+			problem("option '%s' cannot have both choices and autocomplete", path)
+		}
+	}
+	if cache := optTable.RawGetString("autocomplete_cache"); cache != lua.LNil {
+		if seconds, ok := cache.(lua.LNumber); !ok || seconds <= 0 {
+			problem("option '%s': 'autocomplete_cache' must be a positive number of seconds", path)
+		}
+	}
+
+	if requiredIf := optTable.RawGetString("required_if"); requiredIf != lua.LNil {
+		if requiredIf.Type() != lua.LTString && requiredIf.Type() != lua.LTTable {
+			problem("option '%s': 'required_if' must be an option name or a table of option values", path)
+		}
+	}
+
+	return problems
+}
+
+// validateChoiceTables checks the predefined choices of an option have names
+// and values of the option's type.
+func validateChoiceTables(path string, optionType discordgo.ApplicationCommandOptionType, choices *lua.LTable) []string {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	count := 0
+	choices.ForEach(func(key, value lua.LValue) {
+		count++
+		choiceTable, ok := value.(*lua.LTable)
+		if !ok {
+			problem("option '%s': choice %s must be a table", path, key.String())
+			return
+		}
+
+		name, ok := choiceTable.RawGetString("name").(lua.LString)
+		if !ok {
+			problem("option '%s': 'name' of choice %s must be a string", path, key.String())
+			return
+		}
+		if n := utf8.RuneCountInString(string(name)); n == 0 || n > maxChoiceLength {
+			problem("option '%s': name of choice '%s' must be 1-%d characters, got %d", path, name, maxChoiceLength, n)
+		}
+
+		rawValue := choiceTable.RawGetString("value")
+		switch optionType {
+		case discordgo.ApplicationCommandOptionString:
+			if value, ok := rawValue.(lua.LString); !ok {
+				problem("option '%s': value of choice '%s' must be a string", path, name)
+			} else if n := utf8.RuneCountInString(string(value)); n == 0 || n > maxChoiceLength {
+				problem("option '%s': value of choice '%s' must be 1-%d characters, got %d", path, name, maxChoiceLength, n)
+			}
+		default:
+			if rawValue.Type() != lua.LTNumber {
+				problem("option '%s': value of choice '%s' must be a number", path, name)
+			}
+		}
+	})
+
+	if count > maxOptionChoices {
+		problem("option '%s' has %d choices, the maximum is %d", path, count, maxOptionChoices)
+	}
+
+	return problems
+}
+
+// formatCommandProblems joins the problems found in a command table into one
+// error message, one problem per line.
+func formatCommandProblems(name lua.LValue, problems []string) string {
+	subject := "command"
+	if name.Type() == lua.LTString {
+		subject = fmt.Sprintf("command '%s'", name.String())
+	}
+	if len(problems) == 1 {
+		return fmt.Sprintf("%s: %s", subject, problems[0])
+	}
+	return fmt.Sprintf("%s has %d problems:\n  - %s", subject, len(problems), strings.Join(problems, "\n  - "))
+}
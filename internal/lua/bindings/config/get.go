@@ -0,0 +1,50 @@
+package config
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ConfigBindingGet provides Lua bindings for reading per-guild configuration.
+type ConfigBindingGet struct {
+	ConfigStore *utils.ConfigStore
+}
+
+// NewConfigBindingGet initializes a new config get instance.
+func NewConfigBindingGet(cs *utils.ConfigStore) *ConfigBindingGet {
+	slog.Debug("Creating new ConfigBindingGet")
+	return &ConfigBindingGet{
+		ConfigStore: cs,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *ConfigBindingGet) Name() string {
+	return "get"
+}
+
+func (b *ConfigBindingGet) SetSession(session *discordgo.Session) {}
+
+// Register adds the config-related functions to the Lua state.
+func (b *ConfigBindingGet) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		key := L.CheckString(1)
+		guildID := b.ConfigStore.ResolveGuild(L.OptString(2, ""))
+
+		L.Push(b.ConfigStore.Get(guildID, key))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ConfigBindingGet) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ConfigBindingGet) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
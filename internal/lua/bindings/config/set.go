@@ -0,0 +1,52 @@
+package config
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ConfigBindingSet provides Lua bindings for writing per-guild configuration.
+type ConfigBindingSet struct {
+	ConfigStore *utils.ConfigStore
+}
+
+// NewConfigBindingSet initializes a new config set instance.
+func NewConfigBindingSet(cs *utils.ConfigStore) *ConfigBindingSet {
+	slog.Debug("Creating new ConfigBindingSet")
+	return &ConfigBindingSet{
+		ConfigStore: cs,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *ConfigBindingSet) Name() string {
+	return "set"
+}
+
+func (b *ConfigBindingSet) SetSession(session *discordgo.Session) {}
+
+// Register adds the config-related functions to the Lua state. Setting a key
+// to nil removes it.
+func (b *ConfigBindingSet) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		key := L.CheckString(1)
+		value := L.Get(2)
+		guildID := b.ConfigStore.ResolveGuild(L.OptString(3, ""))
+
+		b.ConfigStore.Set(guildID, key, value)
+		return 0
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ConfigBindingSet) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ConfigBindingSet) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
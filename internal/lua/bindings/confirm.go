@@ -0,0 +1,227 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// defaultConfirmTimeout is how long `confirm` waits when no timeout is given.
+	defaultConfirmTimeout = 30 * time.Second
+
+	// confirmYesID and confirmNoID are the custom IDs of the confirm buttons.
+	// They only need to be unique within the prompt, as clicks are matched
+	// by the prompt's message.
+	confirmYesID = "confirm_yes"
+	confirmNoID  = "confirm_no"
+)
+
+// ConfirmBinding implements the `confirm` Lua function, which asks the user
+// of an interaction to confirm an action with ephemeral Yes and No buttons.
+// The answer is passed to a callback, and the buttons are removed once the
+// user answers or the prompt times out.
+type ConfirmBinding struct {
+	Session *discordgo.Session
+
+	await  *AwaitComponentBinding
+	nextID int
+}
+
+// NewConfirmBinding creates a new ConfirmBinding waiting on clicks through
+// the given await_component binding.
+func NewConfirmBinding(await *AwaitComponentBinding) *ConfirmBinding {
+	slog.Debug("Creating new ConfirmBinding")
+	return &ConfirmBinding{
+		await: await,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *ConfirmBinding) Name() string {
+	return "confirm"
+}
+
+func (b *ConfirmBinding) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register creates the `confirm` Lua function. The prompt is sent as the
+// interaction's reply, or as a followup if it was already replied to, and is
+// always ephemeral unless it fills in a public deferred response.
+func (b *ConfirmBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		interactionTable := L.CheckTable(1)
+		prompt := L.CheckString(2)
+		options := L.OptTable(3, nil)
+		callback := L.CheckFunction(4)
+
+		ctx, ok := utils.LookupInteraction(interactionTable)
+		if !ok {
+			L.ArgError(1, "expected an interaction that can still be responded to")
+			return 0
+		}
+
+		wait := defaultConfirmTimeout
+		if options != nil {
+			if raw := options.RawGetString("timeout"); raw != lua.LNil {
+				seconds, ok := raw.(lua.LNumber)
+				wait = time.Duration(float64(seconds) * float64(time.Second))
+				if !ok || wait <= 0 || wait > maxAwaitTimeout {
+					L.ArgError(3, fmt.Sprintf("'timeout' in options must be between 0 and %d seconds", int(maxAwaitTimeout.Seconds())))
+					return 0
+				}
+			}
+		}
+
+		message, followup, err := b.sendPrompt(ctx, prompt)
+		if err != nil {
+			slog.Error("Failed to send confirm prompt", "interaction_id", ctx.Interaction.ID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to send confirm prompt: %s", err.Error())))
+			return 2
+		}
+
+		handler := fmt.Sprintf("confirm_handler_%d", b.nextID)
+		b.nextID++
+		L.SetGlobal(handler, callback)
+
+		interaction := ctx.Interaction
+		b.await.awaitNative(message.ID, utils.InteractionUserID(interaction), wait, func(L *lua.LState, click *discordgo.InteractionCreate) {
+			fn := L.GetGlobal(handler)
+			L.SetGlobal(handler, lua.LNil)
+
+			answer := lua.LNil
+			if click == nil {
+				b.closePrompt(interaction, message.ID, followup, prompt+"\n-# Timed out.")
+			} else {
+				confirmed := click.MessageComponentData().CustomID == confirmYesID
+				answer = lua.LBool(confirmed)
+				b.answerPrompt(click, prompt, confirmed)
+			}
+
+			var err error
+			utils.GetLuaRunner().WithInteraction(interaction, func() {
+				err = L.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, answer)
+			})
+			if err != nil {
+				slog.Error("Error executing Lua confirm callback", "error", err)
+			}
+		})
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// sendPrompt sends the prompt with its buttons, reporting whether it was
+// sent as a followup rather than as the interaction's response.
+func (b *ConfirmBinding) sendPrompt(ctx *utils.InteractionContext, prompt string) (*discordgo.Message, bool, error) {
+	interaction := ctx.Interaction
+	components := confirmComponents()
+	allowedMentions := utils.AllowedMentions(false)
+
+	switch {
+	case ctx.State.Deferred():
+		message, err := b.Session.InteractionResponseEdit(interaction.Interaction, &discordgo.WebhookEdit{
+			Content:         &prompt,
+			Components:      &components,
+			AllowedMentions: allowedMentions,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		ctx.State.MarkResponded(ctx.State.Ephemeral())
+		return message, false, nil
+	case ctx.State.Responded():
+		message, err := b.Session.FollowupMessageCreate(interaction.Interaction, true, &discordgo.WebhookParams{
+			Content:         prompt,
+			Components:      components,
+			Flags:           discordgo.MessageFlagsEphemeral,
+			AllowedMentions: allowedMentions,
+		})
+		return message, true, err
+	default:
+		if err := b.Session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content:         prompt,
+				Components:      components,
+				Flags:           discordgo.MessageFlagsEphemeral,
+				AllowedMentions: allowedMentions,
+			},
+		}); err != nil {
+			return nil, false, err
+		}
+		ctx.State.MarkResponded(true)
+
+		// The initial response doesn't return the message, which is needed
+		// to match the clicks on its buttons
+		message, err := b.Session.InteractionResponse(interaction.Interaction)
+		return message, false, err
+	}
+}
+
+// answerPrompt responds to a click by replacing the buttons with the answer.
+func (b *ConfirmBinding) answerPrompt(click *discordgo.InteractionCreate, prompt string, confirmed bool) {
+	answer := "Cancelled."
+	if confirmed {
+		answer = "Confirmed."
+	}
+
+	if err := b.Session.InteractionRespond(click.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    prompt + "\n-# " + answer,
+			Components: []discordgo.MessageComponent{},
+		},
+	}); err != nil {
+		slog.Error("Failed to update confirm prompt", "error", err)
+	}
+}
+
+// closePrompt removes the buttons from a prompt nobody answered.
+func (b *ConfirmBinding) closePrompt(interaction *discordgo.InteractionCreate, messageID string, followup bool, content string) {
+	components := []discordgo.MessageComponent{}
+	edit := &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	}
+
+	var err error
+	if followup {
+		_, err = b.Session.FollowupMessageEdit(interaction.Interaction, messageID, edit)
+	} else {
+		_, err = b.Session.InteractionResponseEdit(interaction.Interaction, edit)
+	}
+	if err != nil {
+		slog.Error("Failed to close confirm prompt", "message_id", messageID, "error", err)
+	}
+}
+
+// confirmComponents returns the Yes and No buttons of a prompt.
+func confirmComponents() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Yes", Style: discordgo.SuccessButton, CustomID: confirmYesID},
+				discordgo.Button{Label: "No", Style: discordgo.DangerButton, CustomID: confirmNoID},
+			},
+		},
+	}
+}
+
+// HandleInteraction is not applicable for this binding, clicks on the prompt
+// are handled by the await_component binding.
+func (b *ConfirmBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ConfirmBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
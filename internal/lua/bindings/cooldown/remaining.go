@@ -0,0 +1,54 @@
+package cooldown
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+	"math"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// CooldownBindingRemaining provides Lua bindings for checking how long until
+// a cooldown expires.
+type CooldownBindingRemaining struct {
+	Cooldowns *utils.Cooldowns
+}
+
+// NewCooldownBindingRemaining initializes a new cooldown remaining instance.
+func NewCooldownBindingRemaining(cooldowns *utils.Cooldowns) *CooldownBindingRemaining {
+	slog.Debug("Creating new CooldownBindingRemaining")
+	return &CooldownBindingRemaining{
+		Cooldowns: cooldowns,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *CooldownBindingRemaining) Name() string {
+	return "remaining"
+}
+
+func (b *CooldownBindingRemaining) SetSession(session *discordgo.Session) {}
+
+// Register registers the cooldown-related functions in the Lua state.
+func (b *CooldownBindingRemaining) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.CheckString(1)
+		scopeID := L.CheckString(2)
+
+		// Rounded up, so a cooldown only reads 0 once it has really expired
+		remaining := b.Cooldowns.Remaining(name, scopeID)
+		L.Push(lua.LNumber(math.Ceil(remaining.Seconds())))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *CooldownBindingRemaining) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *CooldownBindingRemaining) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
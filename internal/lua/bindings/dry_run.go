@@ -0,0 +1,51 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DryRunBinding implements the `dry_run` Lua function, which reads or toggles
+// dry-run mode for destructive bindings.
+type DryRunBinding struct{}
+
+// NewDryRunBinding creates a new DryRunBinding.
+func NewDryRunBinding() *DryRunBinding {
+	slog.Debug("Creating new DryRunBinding")
+	return &DryRunBinding{}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *DryRunBinding) Name() string {
+	return "dry_run"
+}
+
+func (b *DryRunBinding) SetSession(session *discordgo.Session) {}
+
+// Register creates the `dry_run` Lua function. Called with a boolean it
+// switches the mode, and it always returns whether the mode is enabled.
+func (b *DryRunBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		if L.GetTop() >= 1 {
+			enabled := L.CheckBool(1)
+			utils.SetDryRun(enabled)
+			slog.Info("Dry run mode changed", "enabled", enabled)
+		}
+
+		L.Push(lua.LBool(utils.DryRun()))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *DryRunBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *DryRunBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,64 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// EntitlementsBinding implements the `entitlements` Lua function, which lists
+// the premium SKUs a user currently has access to.
+type EntitlementsBinding struct {
+	Session *discordgo.Session
+}
+
+// NewEntitlementsBinding creates a new EntitlementsBinding.
+func NewEntitlementsBinding() *EntitlementsBinding {
+	slog.Debug("Creating new EntitlementsBinding")
+	return &EntitlementsBinding{}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *EntitlementsBinding) Name() string {
+	return "entitlements"
+}
+
+func (b *EntitlementsBinding) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register creates the `entitlements` Lua function. It asks Discord for the
+// user's entitlements, so inside a handler the `entitlements` field of the
+// interaction is cheaper for the user who triggered it.
+func (b *EntitlementsBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		userID := L.CheckString(1)
+
+		entitlements, err := b.Session.Entitlements(b.Session.State.User.ID, &discordgo.EntitlementFilterOptions{
+			UserID:       userID,
+			ExcludeEnded: true,
+		})
+		if err != nil {
+			slog.Error("Failed to fetch entitlements", "user_id", userID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to fetch entitlements: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(utils.PrepareSKUTable(L, utils.ActiveSKUs(entitlements)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *EntitlementsBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *EntitlementsBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
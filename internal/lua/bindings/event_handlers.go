@@ -0,0 +1,72 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// EventHandlers holds the Lua functions subscribed to one event, such as
+// on_ready or on_message, so several modules can each watch the same event.
+// Handlers run in the order they were registered, and one raising an error
+// is logged without skipping the handlers after it.
+type EventHandlers struct {
+	event string   // Names the handler globals and log lines, e.g. "message"
+	names []string // Lua global handler names, in registration order
+}
+
+// NewEventHandlers initializes an empty handler list for an event.
+func NewEventHandlers(event string) *EventHandlers {
+	return &EventHandlers{
+		event: event,
+		names: []string{},
+	}
+}
+
+// Add stores a handler under a numbered global and subscribes it to the
+// event, after the handlers registered before it.
+func (h *EventHandlers) Add(L *lua.LState, handler *lua.LFunction) string {
+	globalName := fmt.Sprintf("%s_handler_%d", h.event, len(h.names))
+	L.SetGlobal(globalName, handler)
+	h.names = append(h.names, globalName)
+	return globalName
+}
+
+// Len returns how many handlers are subscribed.
+func (h *EventHandlers) Len() int {
+	return len(h.names)
+}
+
+// Reset unsubscribes every handler.
+func (h *EventHandlers) Reset(L *lua.LState) {
+	for _, globalName := range h.names {
+		L.SetGlobal(globalName, lua.LNil)
+	}
+	h.names = []string{}
+}
+
+// Call runs every handler in registration order. The arguments are built
+// afresh for each handler, so one handler changing its tables can't affect
+// the next. It must be called on the Lua runner.
+func (h *EventHandlers) Call(L *lua.LState, args func() []lua.LValue) {
+	for _, globalName := range h.names {
+		fn := L.GetGlobal(globalName)
+		if fn == lua.LNil {
+			slog.Error("Lua event handler not found", "event", h.event, "handler", globalName)
+			continue
+		}
+
+		var values []lua.LValue
+		if args != nil {
+			values = args()
+		}
+		if err := L.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    0,
+			Protect: true,
+		}, values...); err != nil {
+			slog.Error("Error executing Lua event handler", "event", h.event, "handler", globalName, "error", err)
+		}
+	}
+}
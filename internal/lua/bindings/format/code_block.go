@@ -0,0 +1,54 @@
+package format
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// FormatBindingCodeBlock provides a Lua helper that wraps text in a code block.
+type FormatBindingCodeBlock struct{}
+
+// NewFormatBindingCodeBlock initializes a new code block instance.
+func NewFormatBindingCodeBlock() *FormatBindingCodeBlock {
+	slog.Debug("Creating new FormatBindingCodeBlock")
+	return &FormatBindingCodeBlock{}
+}
+
+// Name returns the name of the binding.
+func (b *FormatBindingCodeBlock) Name() string {
+	return "code_block"
+}
+
+func (b *FormatBindingCodeBlock) SetSession(session *discordgo.Session) {}
+
+// Register registers the format-related functions in the Lua state. Fences
+// inside the text are broken up with a zero-width space, so they can't end
+// the block early.
+func (b *FormatBindingCodeBlock) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		text := L.CheckString(1)
+		lang := L.OptString(2, "")
+
+		if strings.ContainsAny(lang, " \n`") {
+			L.ArgError(2, "language must be a single word")
+			return 0
+		}
+
+		text = strings.ReplaceAll(text, "```", "`\u200b``")
+		L.Push(lua.LString("```" + lang + "\n" + text + "\n```"))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *FormatBindingCodeBlock) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *FormatBindingCodeBlock) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
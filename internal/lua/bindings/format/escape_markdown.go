@@ -0,0 +1,64 @@
+package format
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// markdownEscaper backslash-escapes the characters Discord treats as
+// markdown: emphasis, code, spoilers, quotes, headings, lists and masked links.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	"`", "\\`",
+	`~`, `\~`,
+	`|`, `\|`,
+	`>`, `\>`,
+	`#`, `\#`,
+	`-`, `\-`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`(`, `\(`,
+	`)`, `\)`,
+)
+
+// FormatBindingEscapeMarkdown provides a Lua helper for echoing user input
+// without it being rendered as markdown.
+type FormatBindingEscapeMarkdown struct{}
+
+// NewFormatBindingEscapeMarkdown initializes a new escape markdown instance.
+func NewFormatBindingEscapeMarkdown() *FormatBindingEscapeMarkdown {
+	slog.Debug("Creating new FormatBindingEscapeMarkdown")
+	return &FormatBindingEscapeMarkdown{}
+}
+
+// Name returns the name of the binding.
+func (b *FormatBindingEscapeMarkdown) Name() string {
+	return "escape_markdown"
+}
+
+func (b *FormatBindingEscapeMarkdown) SetSession(session *discordgo.Session) {}
+
+// Register registers the format-related functions in the Lua state.
+func (b *FormatBindingEscapeMarkdown) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		text := L.CheckString(1)
+
+		L.Push(lua.LString(markdownEscaper.Replace(text)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *FormatBindingEscapeMarkdown) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *FormatBindingEscapeMarkdown) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
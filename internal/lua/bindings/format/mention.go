@@ -0,0 +1,68 @@
+package format
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// FormatBindingMention provides Lua helpers that render an ID as mention
+// markup, such as `<@id>` for users and `<#id>` for channels.
+type FormatBindingMention struct {
+	name   string // Lua function name
+	prefix string // Markup before the ID
+}
+
+// NewFormatBindingMentionUser initializes a helper for `<@id>` user mentions.
+func NewFormatBindingMentionUser() *FormatBindingMention {
+	slog.Debug("Creating new FormatBindingMention", "kind", "user")
+	return &FormatBindingMention{name: "mention_user", prefix: "@"}
+}
+
+// NewFormatBindingMentionChannel initializes a helper for `<#id>` channel mentions.
+func NewFormatBindingMentionChannel() *FormatBindingMention {
+	slog.Debug("Creating new FormatBindingMention", "kind", "channel")
+	return &FormatBindingMention{name: "mention_channel", prefix: "#"}
+}
+
+// NewFormatBindingMentionRole initializes a helper for `<@&id>` role mentions.
+func NewFormatBindingMentionRole() *FormatBindingMention {
+	slog.Debug("Creating new FormatBindingMention", "kind", "role")
+	return &FormatBindingMention{name: "mention_role", prefix: "@&"}
+}
+
+// Name returns the name of the binding.
+func (b *FormatBindingMention) Name() string {
+	return b.name
+}
+
+func (b *FormatBindingMention) SetSession(session *discordgo.Session) {}
+
+// Register registers the format-related functions in the Lua state. IDs are
+// checked to be snowflakes, so a name passed by mistake is caught early.
+func (b *FormatBindingMention) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		id := L.CheckString(1)
+
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			L.ArgError(1, "invalid ID, expected a numeric snowflake string")
+			return 0
+		}
+
+		L.Push(lua.LString(fmt.Sprintf("<%s%s>", b.prefix, id)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *FormatBindingMention) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *FormatBindingMention) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,170 @@
+package forum
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// maxPostNameLength is the longest post title Discord allows.
+	maxPostNameLength = 100
+
+	// maxAppliedTags is the most tags Discord allows on a single post.
+	maxAppliedTags = 5
+)
+
+// ForumBindingCreatePost provides Lua bindings for opening posts in forum
+// channels.
+type ForumBindingCreatePost struct {
+	Session *discordgo.Session
+}
+
+// NewForumBindingCreatePost initializes a new forum post instance.
+func NewForumBindingCreatePost() *ForumBindingCreatePost {
+	slog.Debug("Creating new ForumBindingCreatePost")
+	return &ForumBindingCreatePost{}
+}
+
+// Name returns the name of the binding.
+func (b *ForumBindingCreatePost) Name() string {
+	return "create_post"
+}
+
+func (b *ForumBindingCreatePost) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the forum-related functions in the Lua state.
+func (b *ForumBindingCreatePost) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID := L.CheckString(1)
+		opts := L.CheckTable(2)
+
+		name, ok := opts.RawGetString("name").(lua.LString)
+		if !ok || name == "" {
+			L.ArgError(2, "options.name must be a non-empty string")
+			return 0
+		}
+		if utf8.RuneCountInString(string(name)) > maxPostNameLength {
+			L.ArgError(2, fmt.Sprintf("options.name must be at most %d characters", maxPostNameLength))
+			return 0
+		}
+
+		message := &discordgo.MessageSend{}
+		if content := opts.RawGetString("content"); content != lua.LNil {
+			if content.Type() != lua.LTString {
+				L.ArgError(2, "options.content must be a string")
+				return 0
+			}
+			message.Content = content.String()
+		}
+
+		if em := opts.RawGetString("embed"); em != lua.LNil {
+			embedTable, ok := em.(*lua.LTable)
+			if !ok {
+				L.ArgError(2, "options.embed must be a table")
+				return 0
+			}
+			embed, err := utils.ParseEmbed(L, embedTable)
+			if err != nil {
+				L.ArgError(2, fmt.Sprintf("invalid embed: %s", err.Error()))
+				return 0
+			}
+			message.Embeds = []*discordgo.MessageEmbed{embed}
+		}
+
+		// A post always starts with a message, so it needs something to say
+		if message.Content == "" && len(message.Embeds) == 0 {
+			L.ArgError(2, "options.content or options.embed must be set")
+			return 0
+		}
+
+		var tagIDs []string
+		if tags := opts.RawGetString("tags"); tags != lua.LNil {
+			tagsTable, ok := tags.(*lua.LTable)
+			if !ok {
+				L.ArgError(2, "options.tags must be an array of tag IDs")
+				return 0
+			}
+			for i := 1; i <= tagsTable.Len(); i++ {
+				id := tagsTable.RawGetInt(i)
+				if id.Type() != lua.LTString {
+					L.ArgError(2, fmt.Sprintf("options.tags[%d] must be a tag ID string", i))
+					return 0
+				}
+				tagIDs = append(tagIDs, id.String())
+			}
+			if len(tagIDs) > maxAppliedTags {
+				L.ArgError(2, fmt.Sprintf("options.tags has %d tags, the maximum is %d", len(tagIDs), maxAppliedTags))
+				return 0
+			}
+		}
+
+		channel, err := b.Session.State.Channel(channelID)
+		if err != nil {
+			channel, err = b.Session.Channel(channelID)
+		}
+		if err != nil {
+			slog.Error("Failed to get forum channel", "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to get forum channel: %s", err.Error())))
+			return 2
+		}
+		if channel.Type != discordgo.ChannelTypeGuildForum && channel.Type != discordgo.ChannelTypeGuildMedia {
+			L.Push(lua.LNil)
+			L.Push(lua.LString("channel is not a forum channel"))
+			return 2
+		}
+
+		// Discord only says a tag is invalid, so name the one that isn't
+		// available in this forum
+		for _, id := range tagIDs {
+			if !hasTag(channel, id) {
+				L.Push(lua.LNil)
+				L.Push(lua.LString(fmt.Sprintf("tag '%s' is not available in this forum", id)))
+				return 2
+			}
+		}
+
+		slog.Info("Creating forum post", "channel_id", channelID, "name", string(name), "tags", tagIDs)
+
+		thread, err := b.Session.ForumThreadStartComplex(channelID, &discordgo.ThreadStart{
+			Name:        string(name),
+			AppliedTags: tagIDs,
+		}, message)
+		if err != nil {
+			slog.Error("Failed to create forum post", "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to create forum post: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(lua.LString(thread.ID))
+		return 1
+	}
+}
+
+// hasTag reports whether the forum channel offers the tag with the given ID.
+func hasTag(channel *discordgo.Channel, id string) bool {
+	for _, tag := range channel.AvailableTags {
+		if tag.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ForumBindingCreatePost) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ForumBindingCreatePost) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
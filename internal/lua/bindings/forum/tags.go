@@ -0,0 +1,123 @@
+package forum
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// tagsCacheTTL is how long the tags of a forum are reused before they are
+// fetched again. Tags are rarely edited, so a few minutes is plenty fresh.
+const tagsCacheTTL = 5 * time.Minute
+
+// errNotForum is returned when the channel asked for is not a forum.
+var errNotForum = errors.New("channel is not a forum channel")
+
+// cachedTags holds the available tags of one forum channel.
+type cachedTags struct {
+	tags      []discordgo.ForumTag
+	fetchedAt time.Time
+}
+
+// ForumBindingTags provides Lua bindings for listing the tags available in a
+// forum channel.
+type ForumBindingTags struct {
+	Session *discordgo.Session
+
+	mu    sync.Mutex
+	cache map[string]cachedTags
+}
+
+// NewForumBindingTags initializes a new forum tags instance.
+func NewForumBindingTags() *ForumBindingTags {
+	slog.Debug("Creating new ForumBindingTags")
+	return &ForumBindingTags{
+		cache: make(map[string]cachedTags),
+	}
+}
+
+// Name returns the name of the binding.
+func (b *ForumBindingTags) Name() string {
+	return "tags"
+}
+
+func (b *ForumBindingTags) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the forum-related functions in the Lua state.
+func (b *ForumBindingTags) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID := L.CheckString(1)
+
+		tags, err := b.tags(channelID)
+		if errors.Is(err, errNotForum) {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		if err != nil {
+			slog.Error("Failed to get forum tags", "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to get forum tags: %s", err.Error())))
+			return 2
+		}
+
+		tagsTable := L.NewTable()
+		for _, tag := range tags {
+			tagTable := L.NewTable()
+			tagTable.RawSetString("id", lua.LString(tag.ID))
+			tagTable.RawSetString("name", lua.LString(tag.Name))
+			tagTable.RawSetString("moderated", lua.LBool(tag.Moderated))
+			if tag.EmojiName != "" {
+				tagTable.RawSetString("emoji", lua.LString(tag.EmojiName))
+			}
+			if tag.EmojiID != "" {
+				tagTable.RawSetString("emoji_id", lua.LString(tag.EmojiID))
+			}
+			tagsTable.Append(tagTable)
+		}
+		L.Push(tagsTable)
+		return 1
+	}
+}
+
+// tags returns the available tags of a forum channel, fetched from Discord
+// at most once per tagsCacheTTL for each channel.
+func (b *ForumBindingTags) tags(channelID string) ([]discordgo.ForumTag, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if cached, ok := b.cache[channelID]; ok && time.Since(cached.fetchedAt) < tagsCacheTTL {
+		return cached.tags, nil
+	}
+
+	channel, err := b.Session.Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Type != discordgo.ChannelTypeGuildForum && channel.Type != discordgo.ChannelTypeGuildMedia {
+		return nil, errNotForum
+	}
+
+	b.cache[channelID] = cachedTags{
+		tags:      channel.AvailableTags,
+		fetchedAt: time.Now(),
+	}
+	return channel.AvailableTags, nil
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ForumBindingTags) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ForumBindingTags) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
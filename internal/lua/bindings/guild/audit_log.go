@@ -0,0 +1,199 @@
+package guild
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// auditLogActions maps readable names, following Discord's own names for
+// audit log events, to their action types.
+var auditLogActions = map[string]discordgo.AuditLogAction{
+	"guild_update":                          discordgo.AuditLogActionGuildUpdate,
+	"channel_create":                        discordgo.AuditLogActionChannelCreate,
+	"channel_update":                        discordgo.AuditLogActionChannelUpdate,
+	"channel_delete":                        discordgo.AuditLogActionChannelDelete,
+	"channel_overwrite_create":              discordgo.AuditLogActionChannelOverwriteCreate,
+	"channel_overwrite_update":              discordgo.AuditLogActionChannelOverwriteUpdate,
+	"channel_overwrite_delete":              discordgo.AuditLogActionChannelOverwriteDelete,
+	"member_kick":                           discordgo.AuditLogActionMemberKick,
+	"member_prune":                          discordgo.AuditLogActionMemberPrune,
+	"member_ban_add":                        discordgo.AuditLogActionMemberBanAdd,
+	"member_ban_remove":                     discordgo.AuditLogActionMemberBanRemove,
+	"member_update":                         discordgo.AuditLogActionMemberUpdate,
+	"member_role_update":                    discordgo.AuditLogActionMemberRoleUpdate,
+	"member_move":                           discordgo.AuditLogActionMemberMove,
+	"member_disconnect":                     discordgo.AuditLogActionMemberDisconnect,
+	"bot_add":                               discordgo.AuditLogActionBotAdd,
+	"role_create":                           discordgo.AuditLogActionRoleCreate,
+	"role_update":                           discordgo.AuditLogActionRoleUpdate,
+	"role_delete":                           discordgo.AuditLogActionRoleDelete,
+	"invite_create":                         discordgo.AuditLogActionInviteCreate,
+	"invite_update":                         discordgo.AuditLogActionInviteUpdate,
+	"invite_delete":                         discordgo.AuditLogActionInviteDelete,
+	"webhook_create":                        discordgo.AuditLogActionWebhookCreate,
+	"webhook_update":                        discordgo.AuditLogActionWebhookUpdate,
+	"webhook_delete":                        discordgo.AuditLogActionWebhookDelete,
+	"emoji_create":                          discordgo.AuditLogActionEmojiCreate,
+	"emoji_update":                          discordgo.AuditLogActionEmojiUpdate,
+	"emoji_delete":                          discordgo.AuditLogActionEmojiDelete,
+	"message_delete":                        discordgo.AuditLogActionMessageDelete,
+	"message_bulk_delete":                   discordgo.AuditLogActionMessageBulkDelete,
+	"message_pin":                           discordgo.AuditLogActionMessagePin,
+	"message_unpin":                         discordgo.AuditLogActionMessageUnpin,
+	"integration_create":                    discordgo.AuditLogActionIntegrationCreate,
+	"integration_update":                    discordgo.AuditLogActionIntegrationUpdate,
+	"integration_delete":                    discordgo.AuditLogActionIntegrationDelete,
+	"stage_instance_create":                 discordgo.AuditLogActionStageInstanceCreate,
+	"stage_instance_update":                 discordgo.AuditLogActionStageInstanceUpdate,
+	"stage_instance_delete":                 discordgo.AuditLogActionStageInstanceDelete,
+	"sticker_create":                        discordgo.AuditLogActionStickerCreate,
+	"sticker_update":                        discordgo.AuditLogActionStickerUpdate,
+	"sticker_delete":                        discordgo.AuditLogActionStickerDelete,
+	"thread_create":                         discordgo.AuditLogActionThreadCreate,
+	"thread_update":                         discordgo.AuditLogActionThreadUpdate,
+	"thread_delete":                         discordgo.AuditLogActionThreadDelete,
+	"application_command_permission_update": discordgo.AuditLogActionApplicationCommandPermissionUpdate,
+	"auto_moderation_rule_create":           discordgo.AuditLogActionAutoModerationRuleCreate,
+	"auto_moderation_rule_update":           discordgo.AuditLogActionAutoModerationRuleUpdate,
+	"auto_moderation_rule_delete":           discordgo.AuditLogActionAutoModerationRuleDelete,
+	"auto_moderation_block_message":         discordgo.AuditLogActionAutoModerationBlockMessage,
+	"auto_moderation_flag_to_channel":       discordgo.AuditLogActionAutoModerationFlagToChannel,
+	"auto_moderation_user_communication_disabled": discordgo.AuditLogActionAutoModerationUserCommunicationDisabled,
+}
+
+// maxAuditLogEntries is the most entries Discord returns per request.
+const maxAuditLogEntries = 100
+
+// GuildBindingAuditLog provides Lua bindings for reading the guild's audit log.
+type GuildBindingAuditLog struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewGuildBindingAuditLog initializes a new guild audit log instance.
+func NewGuildBindingAuditLog(guildID string) *GuildBindingAuditLog {
+	slog.Debug("Creating new GuildBindingAuditLog")
+	return &GuildBindingAuditLog{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *GuildBindingAuditLog) Name() string {
+	return "audit_log"
+}
+
+func (b *GuildBindingAuditLog) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the guild-related functions in the Lua state. Entries
+// are returned newest first, and may be filtered by action and moderator.
+func (b *GuildBindingAuditLog) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		opts := L.OptTable(1, L.NewTable())
+
+		actionType := 0
+		if action := opts.RawGetString("action_type"); action != lua.LNil {
+			actionValue, ok := auditLogActions[action.String()]
+			if !ok {
+				L.ArgError(1, fmt.Sprintf("unknown action_type '%s'", action.String()))
+				return 0
+			}
+			actionType = int(actionValue)
+		}
+
+		userID := ""
+		if user := opts.RawGetString("user_id"); user.Type() == lua.LTString {
+			userID = user.String()
+		}
+
+		limit := 50
+		if l := opts.RawGetString("limit"); l != lua.LNil {
+			if l.Type() != lua.LTNumber {
+				L.ArgError(1, "options.limit must be a number")
+				return 0
+			}
+			limit = int(l.(lua.LNumber))
+			if limit < 1 || limit > maxAuditLogEntries {
+				L.ArgError(1, fmt.Sprintf("options.limit must be between 1 and %d", maxAuditLogEntries))
+				return 0
+			}
+		}
+
+		auditLog, err := b.Session.GuildAuditLog(b.GuildID, userID, "", actionType, limit)
+		if err != nil {
+			slog.Error("Failed to read audit log", "guild_id", b.GuildID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(describeAuditLogError(err)))
+			return 2
+		}
+
+		usernames := make(map[string]string, len(auditLog.Users))
+		for _, user := range auditLog.Users {
+			usernames[user.ID] = user.Username
+		}
+
+		entriesTable := L.NewTable()
+		for _, entry := range auditLog.AuditLogEntries {
+			entriesTable.Append(prepareAuditLogEntryTable(L, entry, usernames))
+		}
+
+		L.Push(entriesTable)
+		return 1
+	}
+}
+
+// prepareAuditLogEntryTable converts an audit log entry into a Lua table.
+func prepareAuditLogEntryTable(L *lua.LState, entry *discordgo.AuditLogEntry, usernames map[string]string) *lua.LTable {
+	entryTable := L.NewTable()
+	entryTable.RawSetString("id", lua.LString(entry.ID))
+	entryTable.RawSetString("user_id", lua.LString(entry.UserID))
+	entryTable.RawSetString("username", lua.LString(usernames[entry.UserID]))
+	entryTable.RawSetString("target_id", lua.LString(entry.TargetID))
+	entryTable.RawSetString("reason", lua.LString(entry.Reason))
+
+	action := "unknown"
+	if entry.ActionType != nil {
+		for name, value := range auditLogActions {
+			if value == *entry.ActionType {
+				action = name
+				break
+			}
+		}
+	}
+	entryTable.RawSetString("action", lua.LString(action))
+
+	if created, err := discordgo.SnowflakeTimestamp(entry.ID); err == nil {
+		entryTable.RawSetString("timestamp", lua.LNumber(created.Unix()))
+	}
+
+	return entryTable
+}
+
+// describeAuditLogError converts a Discord API error into a readable message,
+// calling out the missing permission explicitly.
+func describeAuditLogError(err error) string {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		switch restErr.Message.Code {
+		case discordgo.ErrCodeMissingPermissions, discordgo.ErrCodeMissingAccess:
+			return "Failed to read audit log: the bot needs the View Audit Log permission"
+		}
+	}
+	return fmt.Sprintf("Failed to read audit log: %s", err.Error())
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *GuildBindingAuditLog) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *GuildBindingAuditLog) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,93 @@
+package guild
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// countsCacheTTL is how long fetched counts are reused. Discord only updates
+// the approximate counts every few minutes, so refetching sooner gains nothing.
+const countsCacheTTL = time.Minute
+
+// GuildBindingCounts provides Lua bindings for reading the member and online
+// counts of the guild.
+type GuildBindingCounts struct {
+	Session *discordgo.Session
+	GuildID string
+
+	mu        sync.Mutex
+	members   int
+	presences int
+	fetchedAt time.Time
+}
+
+// NewGuildBindingCounts initializes a new guild counts instance.
+func NewGuildBindingCounts(guildID string) *GuildBindingCounts {
+	slog.Debug("Creating new GuildBindingCounts")
+	return &GuildBindingCounts{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *GuildBindingCounts) Name() string {
+	return "counts"
+}
+
+func (b *GuildBindingCounts) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the guild-related functions in the Lua state.
+func (b *GuildBindingCounts) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		members, presences, err := b.counts()
+		if err != nil {
+			slog.Error("Failed to fetch guild counts", "guild_id", b.GuildID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to fetch guild counts: %s", err.Error())))
+			return 2
+		}
+
+		countsTable := L.NewTable()
+		countsTable.RawSetString("member_count", lua.LNumber(members))
+		countsTable.RawSetString("presence_count", lua.LNumber(presences))
+		L.Push(countsTable)
+		return 1
+	}
+}
+
+// counts returns the approximate member and online counts, fetched from
+// Discord at most once per countsCacheTTL.
+func (b *GuildBindingCounts) counts() (int, int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.fetchedAt.IsZero() && time.Since(b.fetchedAt) < countsCacheTTL {
+		return b.members, b.presences, nil
+	}
+
+	guild, err := b.Session.GuildWithCounts(b.GuildID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	b.members, b.presences = guild.ApproximateMemberCount, guild.ApproximatePresenceCount
+	b.fetchedAt = time.Now()
+	return b.members, b.presences, nil
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *GuildBindingCounts) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *GuildBindingCounts) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
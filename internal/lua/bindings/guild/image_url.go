@@ -0,0 +1,112 @@
+package guild
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// GuildBindingImageURL provides Lua bindings for building the CDN URL of one
+// of the guild's images. Animated images, whose hashes start with `a_`, are
+// linked as gifs and the rest as pngs.
+type GuildBindingImageURL struct {
+	Session *discordgo.Session
+	GuildID string
+
+	name  string
+	hash  func(guild *discordgo.Guild) string
+	build func(guild *discordgo.Guild, size string) string
+}
+
+// NewGuildBindingIconURL initializes a helper building the guild icon URL.
+func NewGuildBindingIconURL(guildID string) *GuildBindingImageURL {
+	slog.Debug("Creating new GuildBindingImageURL", "name", "icon_url")
+	return &GuildBindingImageURL{
+		GuildID: guildID,
+		name:    "icon_url",
+		hash:    func(guild *discordgo.Guild) string { return guild.Icon },
+		build:   (*discordgo.Guild).IconURL,
+	}
+}
+
+// NewGuildBindingBannerURL initializes a helper building the guild banner URL.
+func NewGuildBindingBannerURL(guildID string) *GuildBindingImageURL {
+	slog.Debug("Creating new GuildBindingImageURL", "name", "banner_url")
+	return &GuildBindingImageURL{
+		GuildID: guildID,
+		name:    "banner_url",
+		hash:    func(guild *discordgo.Guild) string { return guild.Banner },
+		build:   (*discordgo.Guild).BannerURL,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *GuildBindingImageURL) Name() string {
+	return b.name
+}
+
+func (b *GuildBindingImageURL) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the guild-related functions in the Lua state. Returns
+// nil when the guild has no such image.
+func (b *GuildBindingImageURL) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		opts := L.OptTable(1, nil)
+
+		size := ""
+		if opts != nil {
+			if raw := opts.RawGetString("size"); raw != lua.LNil {
+				n, ok := raw.(lua.LNumber)
+				if !ok || !validImageSize(int(n)) || float64(n) != float64(int(n)) {
+					L.ArgError(1, "options.size must be a power of two between 16 and 4096")
+					return 0
+				}
+				size = strconv.Itoa(int(n))
+			}
+		}
+
+		guild, err := b.guild()
+		if err != nil {
+			slog.Error("Failed to fetch guild", "guild_id", b.GuildID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to fetch guild: %s", err.Error())))
+			return 2
+		}
+
+		if b.hash(guild) == "" {
+			L.Push(lua.LNil)
+			return 1
+		}
+
+		L.Push(lua.LString(b.build(guild, size)))
+		return 1
+	}
+}
+
+// guild returns the guild from the state cache, fetching it when it isn't cached.
+func (b *GuildBindingImageURL) guild() (*discordgo.Guild, error) {
+	if guild, err := b.Session.State.Guild(b.GuildID); err == nil {
+		return guild, nil
+	}
+	return b.Session.Guild(b.GuildID)
+}
+
+// validImageSize reports whether the CDN serves images at the given size.
+func validImageSize(size int) bool {
+	return size >= 16 && size <= 4096 && size&(size-1) == 0
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *GuildBindingImageURL) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *GuildBindingImageURL) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,44 @@
+package id
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// IDBindingNew provides Lua bindings for generating unique IDs.
+type IDBindingNew struct{}
+
+// NewIDBindingNew initializes a new ID generator instance.
+func NewIDBindingNew() *IDBindingNew {
+	slog.Debug("Creating new IDBindingNew")
+	return &IDBindingNew{}
+}
+
+// Name returns the name of the binding.
+func (b *IDBindingNew) Name() string {
+	return "new"
+}
+
+func (b *IDBindingNew) SetSession(session *discordgo.Session) {}
+
+// Register registers the id-related functions in the Lua state. The IDs are
+// UUIDv7s, which fit in a component custom_id with room for a prefix.
+func (b *IDBindingNew) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		L.Push(lua.LString(utils.NewID()))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *IDBindingNew) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *IDBindingNew) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
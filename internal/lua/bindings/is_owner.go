@@ -0,0 +1,51 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// IsOwnerBinding implements the `is_owner` Lua function, which checks whether
+// a user owns the bot's application, to gate owner-only commands.
+type IsOwnerBinding struct {
+	Owners *utils.Owners
+}
+
+// NewIsOwnerBinding creates a new IsOwnerBinding.
+func NewIsOwnerBinding(owners *utils.Owners) *IsOwnerBinding {
+	slog.Debug("Creating new IsOwnerBinding")
+	return &IsOwnerBinding{
+		Owners: owners,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *IsOwnerBinding) Name() string {
+	return "is_owner"
+}
+
+func (b *IsOwnerBinding) SetSession(session *discordgo.Session) {}
+
+// Register creates the `is_owner` Lua function. The owners are fetched when
+// the bot connects, so checking doesn't ask Discord.
+func (b *IsOwnerBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		userID := L.CheckString(1)
+
+		L.Push(lua.LBool(b.Owners.IsOwner(userID)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *IsOwnerBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *IsOwnerBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,60 @@
+package member
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MemberBindingAddRole provides Lua bindings for adding a role to a guild member.
+type MemberBindingAddRole struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewMemberBindingAddRole initializes a new member add role instance.
+func NewMemberBindingAddRole(guildID string) *MemberBindingAddRole {
+	slog.Debug("Creating new MemberBindingAddRole")
+	return &MemberBindingAddRole{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *MemberBindingAddRole) Name() string {
+	return "add_role"
+}
+
+func (b *MemberBindingAddRole) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the member-related functions in the Lua state.
+func (b *MemberBindingAddRole) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		userID := L.CheckString(1)
+		roleID := L.CheckString(2)
+
+		err := b.Session.GuildMemberRoleAdd(b.GuildID, userID, roleID)
+		if err != nil {
+			slog.Error("Failed to add role", "guild_id", b.GuildID, "user_id", userID, "role_id", roleID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(describeMemberError("Failed to add role", err)))
+			return 2
+		}
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MemberBindingAddRole) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MemberBindingAddRole) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
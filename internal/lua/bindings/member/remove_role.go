@@ -0,0 +1,69 @@
+package member
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MemberBindingRemoveRole provides Lua bindings for removing a role from a guild member.
+type MemberBindingRemoveRole struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewMemberBindingRemoveRole initializes a new member remove role instance.
+func NewMemberBindingRemoveRole(guildID string) *MemberBindingRemoveRole {
+	slog.Debug("Creating new MemberBindingRemoveRole")
+	return &MemberBindingRemoveRole{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *MemberBindingRemoveRole) Name() string {
+	return "remove_role"
+}
+
+func (b *MemberBindingRemoveRole) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the member-related functions in the Lua state. In
+// dry-run mode the role is kept and a preview is returned alongside true.
+func (b *MemberBindingRemoveRole) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		userID := L.CheckString(1)
+		roleID := L.CheckString(2)
+
+		if utils.DryRun() {
+			slog.Info("Dry run: would remove role", "guild_id", b.GuildID, "user_id", userID, "role_id", roleID)
+			L.Push(lua.LTrue)
+			L.Push(utils.PrepareDryRunPreview(L, "remove_role", []string{userID}))
+			return 2
+		}
+
+		err := b.Session.GuildMemberRoleRemove(b.GuildID, userID, roleID)
+		if err != nil {
+			slog.Error("Failed to remove role", "guild_id", b.GuildID, "user_id", userID, "role_id", roleID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(describeMemberError("Failed to remove role", err)))
+			return 2
+		}
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MemberBindingRemoveRole) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MemberBindingRemoveRole) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
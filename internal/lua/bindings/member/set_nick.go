@@ -0,0 +1,86 @@
+package member
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxNicknameLength is the longest nickname Discord allows, in characters.
+const maxNicknameLength = 32
+
+// MemberBindingSetNick provides Lua bindings for changing guild member nicknames.
+type MemberBindingSetNick struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewMemberBindingSetNick initializes a new member nickname instance.
+func NewMemberBindingSetNick(guildID string) *MemberBindingSetNick {
+	slog.Debug("Creating new MemberBindingSetNick")
+	return &MemberBindingSetNick{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *MemberBindingSetNick) Name() string {
+	return "set_nick"
+}
+
+func (b *MemberBindingSetNick) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the member-related functions in the Lua state.
+func (b *MemberBindingSetNick) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		userID := L.CheckString(1)
+		nickname := L.OptString(2, "") // An empty nickname resets it to the username
+
+		if utf8.RuneCountInString(nickname) > maxNicknameLength {
+			L.ArgError(2, fmt.Sprintf("nickname must be %d characters or fewer", maxNicknameLength))
+			return 0
+		}
+
+		err := b.Session.GuildMemberNickname(b.GuildID, userID, nickname)
+		if err != nil {
+			slog.Error("Failed to set member nickname", "guild_id", b.GuildID, "user_id", userID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(describeMemberError("Failed to set nickname", err)))
+			return 2
+		}
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// describeMemberError converts a Discord API error into a readable message,
+// calling out permission and role hierarchy failures explicitly.
+func describeMemberError(prefix string, err error) string {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		switch restErr.Message.Code {
+		case discordgo.ErrCodeMissingPermissions:
+			return fmt.Sprintf("%s: missing permissions, the bot's highest role must be above the member's highest role and the member cannot be the server owner", prefix)
+		case discordgo.ErrCodeUnknownMember:
+			return fmt.Sprintf("%s: member not found", prefix)
+		}
+	}
+	return fmt.Sprintf("%s: %s", prefix, err.Error())
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MemberBindingSetNick) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MemberBindingSetNick) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -9,15 +9,21 @@ import (
 	lua "github.com/yuin/gopher-lua"
 )
 
+// maxStickers is the most stickers Discord allows on a single message.
+const maxStickers = 3
+
 // MessageBindingAdd provides Lua bindings for managing Discord messages.
 type MessageBindingAdd struct {
 	Session *discordgo.Session
+	GuildID string
 }
 
 // NewMessageBindingAdd initializes a new message management instance.
-func NewMessageBindingAdd() *MessageBindingAdd {
+func NewMessageBindingAdd(guildID string) *MessageBindingAdd {
 	slog.Debug("Creating new MessageBindingAdd")
-	return &MessageBindingAdd{}
+	return &MessageBindingAdd{
+		GuildID: guildID,
+	}
 }
 
 // Name returns the name of the binding.
@@ -32,13 +38,21 @@ func (b *MessageBindingAdd) SetSession(session *discordgo.Session) {
 // Register registers the message-related functions in the Lua state.
 func (b *MessageBindingAdd) Register() lua.LGFunction {
 	return func(L *lua.LState) int {
-		channelID := L.CheckString(1)
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, L.CheckString(1))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
 		content := L.CheckString(2)
 		opts := L.OptTable(3, nil)
 
-		// Options table may have "components" and "embed" keys
+		// Options table may have "components", "embed", "poll", "stickers" and "tts" keys
 		var components *lua.LTable = nil
 		var embedTable *lua.LTable = nil
+		var pollTable *lua.LTable = nil
+		var stickerIDs []string
+		tts := false
 
 		if opts != nil {
 			comp := opts.RawGetString("components")
@@ -60,6 +74,46 @@ func (b *MessageBindingAdd) Register() lua.LGFunction {
 					return 0
 				}
 			}
+
+			p := opts.RawGetString("poll")
+			if p != lua.LNil {
+				if pollT, ok := p.(*lua.LTable); ok {
+					pollTable = pollT
+				} else {
+					L.ArgError(3, "options.poll must be a table")
+					return 0
+				}
+			}
+
+			st := opts.RawGetString("stickers")
+			if st != lua.LNil {
+				stickersTable, ok := st.(*lua.LTable)
+				if !ok {
+					L.ArgError(3, "options.stickers must be an array of sticker IDs")
+					return 0
+				}
+				for i := 1; i <= stickersTable.Len(); i++ {
+					id := stickersTable.RawGetInt(i)
+					if id.Type() != lua.LTString {
+						L.ArgError(3, fmt.Sprintf("options.stickers[%d] must be a sticker ID string", i))
+						return 0
+					}
+					stickerIDs = append(stickerIDs, id.String())
+				}
+				if len(stickerIDs) > maxStickers {
+					L.ArgError(3, fmt.Sprintf("options.stickers has %d stickers, the maximum is %d", len(stickerIDs), maxStickers))
+					return 0
+				}
+			}
+
+			t := opts.RawGetString("tts")
+			if t != lua.LNil {
+				if t.Type() != lua.LTBool {
+					L.ArgError(3, "options.tts must be a boolean")
+					return 0
+				}
+				tts = lua.LVAsBool(t)
+			}
 		}
 
 		// Parse the embed table if provided
@@ -84,12 +138,45 @@ func (b *MessageBindingAdd) Register() lua.LGFunction {
 			}
 		}
 
-		slog.Info("Sending complex message", "channel_id", channelID, "content", content, "components", parsedComponents, "embed", embed)
+		// Parse poll if provided.
+		var poll *discordgo.Poll
+		if pollTable != nil {
+			var err error
+			poll, err = utils.ParsePoll(L, pollTable)
+			if err != nil {
+				L.ArgError(3, err.Error())
+				return 0
+			}
+		}
+
+		// Layout components replace the content and embed of a message
+		var flags discordgo.MessageFlags
+		if utils.UsesComponentsV2(parsedComponents) {
+			if content != "" || embed != nil {
+				L.ArgError(2, "content and embed must be empty when using layout components, use a text_display instead")
+				return 0
+			}
+			if len(stickerIDs) > 0 {
+				L.ArgError(3, "options.stickers cannot be used with layout components")
+				return 0
+			}
+			flags = discordgo.MessageFlagsIsComponentsV2
+		}
 
-		message, err := b.Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-			Content:    content,
-			Components: parsedComponents,
-			Embed:      embed,
+		slog.Info("Sending complex message", "channel_id", channelID, "content", content, "components", parsedComponents, "embed", embed, "poll", poll != nil, "stickers", stickerIDs)
+
+		var message *discordgo.Message
+		err = utils.RetryRateLimited(func(options ...discordgo.RequestOption) (err error) {
+			message, err = b.Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+				Content:    content,
+				Components: parsedComponents,
+				Embed:      embed,
+				Poll:       poll,
+				StickerIDs: stickerIDs,
+				TTS:        tts,
+				Flags:      flags,
+			}, options...)
+			return err
 		})
 		if err != nil {
 			slog.Error("Failed to send message", "channel_id", channelID, "error", err)
@@ -98,8 +185,11 @@ func (b *MessageBindingAdd) Register() lua.LGFunction {
 			return 2
 		}
 
-		L.Push(lua.LString(message.ID)) // Return the message ID
-		return 1
+		// The ID comes first so existing scripts keep working, followed by
+		// the message itself for its jump link
+		L.Push(lua.LString(message.ID))
+		L.Push(utils.PrepareMessageTable(L, message, b.GuildID))
+		return 2
 	}
 }
 
@@ -1,6 +1,7 @@
 package message
 
 import (
+	"driftwood/internal/lua/utils"
 	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
@@ -10,12 +11,15 @@ import (
 // MessageBindingDelete provides Lua bindings for managing Discord messages.
 type MessageBindingDelete struct {
 	Session *discordgo.Session
+	GuildID string
 }
 
 // NewMessageBindingDelete initializes a new message management instance.
-func NewMessageBindingDelete() *MessageBindingDelete {
+func NewMessageBindingDelete(guildID string) *MessageBindingDelete {
 	slog.Debug("Creating new MessageBindingDel")
-	return &MessageBindingDelete{}
+	return &MessageBindingDelete{
+		GuildID: guildID,
+	}
 }
 
 // Name returns the name of the binding.
@@ -27,13 +31,28 @@ func (b *MessageBindingDelete) SetSession(session *discordgo.Session) {
 	b.Session = session
 }
 
-// Register registers the message-related functions in the Lua state.
+// Register registers the message-related functions in the Lua state. In
+// dry-run mode the message is kept and a preview is returned alongside true.
 func (b *MessageBindingDelete) Register() lua.LGFunction {
 	return func(L *lua.LState) int {
 		messageID := L.CheckString(1)
-		channelID := L.CheckString(2)
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, L.CheckString(2))
+		if err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		if utils.DryRun() {
+			slog.Info("Dry run: would delete message", "message_id", messageID, "channel_id", channelID)
+			L.Push(lua.LTrue)
+			L.Push(utils.PrepareDryRunPreview(L, "delete_message", []string{messageID}))
+			return 2
+		}
 
-		err := b.Session.ChannelMessageDelete(channelID, messageID)
+		err = utils.RetryRateLimited(func(options ...discordgo.RequestOption) error {
+			return b.Session.ChannelMessageDelete(channelID, messageID, options...)
+		})
 		if err != nil {
 			slog.Error("Failed to delete message", "message_id", messageID, "channel_id", channelID, "error", err)
 			L.Push(lua.LFalse)
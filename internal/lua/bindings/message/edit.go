@@ -11,12 +11,15 @@ import (
 // MessageBindingEdit provides Lua bindings for managing Discord messages.
 type MessageBindingEdit struct {
 	Session *discordgo.Session
+	GuildID string
 }
 
 // NewMessageBindingEdit initializes a new message management instance.
-func NewMessageBindingEdit() *MessageBindingEdit {
+func NewMessageBindingEdit(guildID string) *MessageBindingEdit {
 	slog.Debug("Creating new MessageBindingEdit")
-	return &MessageBindingEdit{}
+	return &MessageBindingEdit{
+		GuildID: guildID,
+	}
 }
 
 // Name returns the name of the binding.
@@ -28,11 +31,17 @@ func (b *MessageBindingEdit) SetSession(session *discordgo.Session) {
 	b.Session = session
 }
 
-// Register registers the message-related functions in the Lua state.
+// Register registers the message-related functions in the Lua state. The
+// message keeps its components unless the options replace or clear them.
 func (b *MessageBindingEdit) Register() lua.LGFunction {
 	return func(L *lua.LState) int {
 		messageID := L.CheckString(1)
-		channelID := L.CheckString(2)
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, L.CheckString(2))
+		if err != nil {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
 		content := L.CheckString(3)
 		opts := L.OptTable(4, nil)
 
@@ -62,14 +71,19 @@ func (b *MessageBindingEdit) Register() lua.LGFunction {
 			}
 		}
 
-		var parsedComponents []discordgo.MessageComponent
+		// Components are only touched when given, and an empty table removes them
+		var parsedComponents *[]discordgo.MessageComponent
 		if components != nil {
-			var err error
-			parsedComponents, err = utils.ParseComponents(L, components)
-			if err != nil {
-				L.ArgError(4, err.Error())
-				return 0
+			replaced := []discordgo.MessageComponent{}
+			if key, _ := components.Next(lua.LNil); key != lua.LNil {
+				var err error
+				replaced, err = utils.ParseComponents(L, components)
+				if err != nil {
+					L.ArgError(4, err.Error())
+					return 0
+				}
 			}
+			parsedComponents = &replaced
 		}
 
 		// Parse embed if provided.
@@ -83,12 +97,27 @@ func (b *MessageBindingEdit) Register() lua.LGFunction {
 			}
 		}
 
-		_, err := b.Session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		edit := &discordgo.MessageEdit{
 			ID:         messageID,
 			Channel:    channelID,
 			Content:    &content,
-			Components: &parsedComponents,
+			Components: parsedComponents,
 			Embed:      embed,
+		}
+
+		// Layout components replace the content and embed of a message
+		if parsedComponents != nil && utils.UsesComponentsV2(*parsedComponents) {
+			if content != "" || embed != nil {
+				L.ArgError(3, "content and embed must be empty when using layout components, use a text_display instead")
+				return 0
+			}
+			edit.Content = nil
+			edit.Flags = discordgo.MessageFlagsIsComponentsV2
+		}
+
+		err = utils.RetryRateLimited(func(options ...discordgo.RequestOption) error {
+			_, err := b.Session.ChannelMessageEditComplex(edit, options...)
+			return err
 		})
 		if err != nil {
 			slog.Error("Failed to edit message", "message_id", messageID, "channel_id", channelID, "error", err)
@@ -0,0 +1,67 @@
+package message
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MessageBindingGet provides Lua bindings for fetching a single Discord message.
+type MessageBindingGet struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewMessageBindingGet initializes a new message get instance.
+func NewMessageBindingGet(guildID string) *MessageBindingGet {
+	slog.Debug("Creating new MessageBindingGet")
+	return &MessageBindingGet{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *MessageBindingGet) Name() string {
+	return "get"
+}
+
+func (b *MessageBindingGet) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the message-related functions in the Lua state.
+func (b *MessageBindingGet) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		messageID := L.CheckString(1)
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, L.CheckString(2))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		message, err := b.Session.ChannelMessage(channelID, messageID)
+		if err != nil {
+			slog.Error("Failed to get message", "message_id", messageID, "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to get message: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(utils.PrepareMessageTable(L, message, b.GuildID))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MessageBindingGet) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MessageBindingGet) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
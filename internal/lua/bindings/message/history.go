@@ -0,0 +1,203 @@
+package message
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// historyPageSize is the most messages Discord returns per request.
+	historyPageSize = 100
+
+	// defaultHistoryScan and maxHistoryScan bound how many messages a
+	// filtered fetch looks through while searching for matches.
+	defaultHistoryScan = 500
+	maxHistoryScan     = 1000
+)
+
+// historyFilter selects the messages a history fetch returns.
+type historyFilter struct {
+	authorID       string
+	contains       string // Lowercased, matched case-insensitively
+	hasAttachments bool
+}
+
+// active reports whether any filter was given.
+func (f historyFilter) active() bool {
+	return f.authorID != "" || f.contains != "" || f.hasAttachments
+}
+
+// matches reports whether a message is selected by the filter.
+func (f historyFilter) matches(message *discordgo.Message) bool {
+	if f.authorID != "" && (message.Author == nil || message.Author.ID != f.authorID) {
+		return false
+	}
+	if f.contains != "" && !strings.Contains(strings.ToLower(message.Content), f.contains) {
+		return false
+	}
+	if f.hasAttachments && len(message.Attachments) == 0 {
+		return false
+	}
+	return true
+}
+
+// MessageBindingHistory provides Lua bindings for fetching recent channel messages.
+type MessageBindingHistory struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewMessageBindingHistory initializes a new message history instance.
+func NewMessageBindingHistory(guildID string) *MessageBindingHistory {
+	slog.Debug("Creating new MessageBindingHistory")
+	return &MessageBindingHistory{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *MessageBindingHistory) Name() string {
+	return "history"
+}
+
+func (b *MessageBindingHistory) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the message-related functions in the Lua state. When
+// filters are given, pages of history are scanned until `limit` matches are
+// found or `scan` messages have been looked through.
+func (b *MessageBindingHistory) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, L.CheckString(1))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		opts := L.OptTable(2, nil)
+
+		limit := 50
+		scan := defaultHistoryScan
+		beforeID, afterID := "", ""
+		var filter historyFilter
+		if opts != nil {
+			if l := opts.RawGetString("limit"); l != lua.LNil {
+				if l.Type() != lua.LTNumber {
+					L.ArgError(2, "options.limit must be a number")
+					return 0
+				}
+				limit = int(l.(lua.LNumber))
+				if limit < 1 || limit > 100 {
+					L.ArgError(2, "options.limit must be between 1 and 100")
+					return 0
+				}
+			}
+			if before := opts.RawGetString("before"); before.Type() == lua.LTString {
+				beforeID = before.String()
+			}
+			if after := opts.RawGetString("after"); after.Type() == lua.LTString {
+				afterID = after.String()
+			}
+			if authorID := opts.RawGetString("author_id"); authorID.Type() == lua.LTString {
+				filter.authorID = authorID.String()
+			}
+			if contains := opts.RawGetString("contains"); contains.Type() == lua.LTString {
+				filter.contains = strings.ToLower(contains.String())
+			}
+			filter.hasAttachments = lua.LVAsBool(opts.RawGetString("has_attachments"))
+			if s := opts.RawGetString("scan"); s != lua.LNil {
+				if s.Type() != lua.LTNumber {
+					L.ArgError(2, "options.scan must be a number")
+					return 0
+				}
+				scan = int(s.(lua.LNumber))
+				if scan < 1 || scan > maxHistoryScan {
+					L.ArgError(2, fmt.Sprintf("options.scan must be between 1 and %d", maxHistoryScan))
+					return 0
+				}
+			}
+		}
+
+		var messages []*discordgo.Message
+		if filter.active() {
+			messages, err = b.search(channelID, limit, scan, beforeID, afterID, filter)
+		} else {
+			messages, err = b.Session.ChannelMessages(channelID, limit, beforeID, afterID, "")
+		}
+		if err != nil {
+			slog.Error("Failed to get message history", "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to get message history: %s", err.Error())))
+			return 2
+		}
+
+		messagesTable := L.NewTable()
+		for _, message := range messages {
+			messagesTable.Append(utils.PrepareMessageTable(L, message, b.GuildID))
+		}
+
+		L.Push(messagesTable)
+		return 1
+	}
+}
+
+// search pages through the channel history collecting messages that match
+// the filter. It pages towards older messages, or towards newer ones when
+// only `after` is given, and stops as soon as enough are found. Matches are
+// returned newest first, like an unfiltered fetch.
+func (b *MessageBindingHistory) search(channelID string, limit, scan int, beforeID, afterID string, filter historyFilter) ([]*discordgo.Message, error) {
+	forward := afterID != "" && beforeID == ""
+
+	var matches []*discordgo.Message
+	for scanned := 0; scanned < scan && len(matches) < limit; {
+		page, err := b.Session.ChannelMessages(channelID, min(historyPageSize, scan-scanned), beforeID, afterID, "")
+		if err != nil {
+			return nil, err
+		}
+		scanned += len(page)
+
+		// Pages are ordered newest first, so walk them in the scan direction
+		if forward {
+			slices.Reverse(page)
+		}
+		for _, message := range page {
+			if len(matches) == limit {
+				break
+			}
+			if filter.matches(message) {
+				matches = append(matches, message)
+			}
+		}
+
+		if len(page) < historyPageSize {
+			break
+		}
+		if forward {
+			afterID = page[len(page)-1].ID
+		} else {
+			beforeID = page[len(page)-1].ID
+		}
+	}
+
+	if forward {
+		slices.Reverse(matches)
+	}
+	return matches, nil
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MessageBindingHistory) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MessageBindingHistory) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
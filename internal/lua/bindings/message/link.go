@@ -0,0 +1,47 @@
+package message
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MessageBindingLink provides a Lua helper for building message jump links.
+type MessageBindingLink struct{}
+
+// NewMessageBindingLink initializes a new message link instance.
+func NewMessageBindingLink() *MessageBindingLink {
+	slog.Debug("Creating new MessageBindingLink")
+	return &MessageBindingLink{}
+}
+
+// Name returns the name of the binding.
+func (b *MessageBindingLink) Name() string {
+	return "link"
+}
+
+func (b *MessageBindingLink) SetSession(session *discordgo.Session) {}
+
+// Register registers the message-related functions in the Lua state.
+func (b *MessageBindingLink) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		guildID := L.CheckString(1)
+		channelID := L.CheckString(2)
+		messageID := L.CheckString(3)
+
+		L.Push(lua.LString(utils.MessageLink(guildID, channelID, messageID)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MessageBindingLink) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MessageBindingLink) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,106 @@
+package message
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MessageBindingPollResults provides Lua bindings for reading native poll results.
+type MessageBindingPollResults struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewMessageBindingPollResults initializes a new poll results instance.
+func NewMessageBindingPollResults(guildID string) *MessageBindingPollResults {
+	slog.Debug("Creating new MessageBindingPollResults")
+	return &MessageBindingPollResults{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *MessageBindingPollResults) Name() string {
+	return "poll_results"
+}
+
+func (b *MessageBindingPollResults) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the message-related functions in the Lua state.
+func (b *MessageBindingPollResults) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		messageID := L.CheckString(1)
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, L.CheckString(2))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		message, err := b.Session.ChannelMessage(channelID, messageID)
+		if err != nil {
+			slog.Error("Failed to fetch poll message", "message_id", messageID, "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to fetch message: %s", err.Error())))
+			return 2
+		}
+
+		if message.Poll == nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString("message does not contain a poll"))
+			return 2
+		}
+
+		L.Push(b.buildPollTable(L, message.Poll))
+		return 1
+	}
+}
+
+// buildPollTable converts a Discord poll and its results into a Lua table.
+func (b *MessageBindingPollResults) buildPollTable(L *lua.LState, poll *discordgo.Poll) *lua.LTable {
+	counts := make(map[int]int)
+	finalized := false
+	if poll.Results != nil {
+		finalized = poll.Results.Finalized
+		for _, count := range poll.Results.AnswerCounts {
+			counts[count.ID] = count.Count
+		}
+	}
+
+	answersTable := L.NewTable()
+	for _, answer := range poll.Answers {
+		answerTable := L.NewTable()
+		answerTable.RawSetString("id", lua.LNumber(answer.AnswerID))
+		if answer.Media != nil {
+			answerTable.RawSetString("text", lua.LString(answer.Media.Text))
+		}
+		answerTable.RawSetString("count", lua.LNumber(counts[answer.AnswerID]))
+		answersTable.Append(answerTable)
+	}
+
+	pollTable := L.NewTable()
+	pollTable.RawSetString("question", lua.LString(poll.Question.Text))
+	pollTable.RawSetString("allow_multiselect", lua.LBool(poll.AllowMultiselect))
+	pollTable.RawSetString("finalized", lua.LBool(finalized))
+	pollTable.RawSetString("answers", answersTable)
+	if poll.Expiry != nil {
+		pollTable.RawSetString("expiry", lua.LNumber(poll.Expiry.Unix()))
+	}
+	return pollTable
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MessageBindingPollResults) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MessageBindingPollResults) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,159 @@
+package message
+
+import (
+	"driftwood/internal/lua/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxQuoteLength is how much of the referenced message is quoted before it
+// is cut off.
+const maxQuoteLength = 300
+
+// MessageBindingReply provides Lua bindings for replying to Discord messages.
+type MessageBindingReply struct {
+	Session *discordgo.Session
+	GuildID string
+}
+
+// NewMessageBindingReply initializes a new message reply instance.
+func NewMessageBindingReply(guildID string) *MessageBindingReply {
+	slog.Debug("Creating new MessageBindingReply")
+	return &MessageBindingReply{
+		GuildID: guildID,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *MessageBindingReply) Name() string {
+	return "reply"
+}
+
+func (b *MessageBindingReply) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the message-related functions in the Lua state. With
+// `quote`, the referenced message is copied into an embed so the reply keeps
+// its context even if the original is deleted later. When the original is
+// already gone, the reply is sent as a plain message instead of failing.
+func (b *MessageBindingReply) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID, err := utils.ResolveChannel(b.Session, b.GuildID, L.CheckString(1))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		messageID := L.CheckString(2)
+		content := L.CheckString(3)
+		opts := L.OptTable(4, nil)
+
+		quote := false
+		mention := false
+		if opts != nil {
+			if raw := opts.RawGetString("quote"); raw != lua.LNil {
+				if raw.Type() != lua.LTBool {
+					L.ArgError(4, "options.quote must be a boolean")
+					return 0
+				}
+				quote = lua.LVAsBool(raw)
+			}
+			if raw := opts.RawGetString("mention"); raw != lua.LNil {
+				if raw.Type() != lua.LTBool {
+					L.ArgError(4, "options.mention must be a boolean")
+					return 0
+				}
+				mention = lua.LVAsBool(raw)
+			}
+		}
+
+		send := &discordgo.MessageSend{
+			Content: content,
+			Reference: &discordgo.MessageReference{
+				MessageID:       messageID,
+				ChannelID:       channelID,
+				FailIfNotExists: new(bool), // Send without the reference if the original is gone
+			},
+			AllowedMentions: &discordgo.MessageAllowedMentions{
+				Parse:       []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeUsers, discordgo.AllowedMentionTypeRoles},
+				RepliedUser: mention,
+			},
+		}
+
+		if quote {
+			original, err := b.Session.ChannelMessage(channelID, messageID)
+			var restErr *discordgo.RESTError
+			switch {
+			case err == nil:
+				send.Embeds = []*discordgo.MessageEmbed{quoteEmbed(original, b.GuildID)}
+			case errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeUnknownMessage:
+				slog.Info("Replied to message was deleted, sending without a quote", "message_id", messageID, "channel_id", channelID)
+				send.Reference = nil
+			default:
+				slog.Error("Failed to fetch message to quote", "message_id", messageID, "channel_id", channelID, "error", err)
+				L.Push(lua.LNil)
+				L.Push(lua.LString(fmt.Sprintf("Failed to fetch message to quote: %s", err.Error())))
+				return 2
+			}
+		}
+
+		var message *discordgo.Message
+		err = utils.RetryRateLimited(func(options ...discordgo.RequestOption) (err error) {
+			message, err = b.Session.ChannelMessageSendComplex(channelID, send, options...)
+			return err
+		})
+		if err != nil {
+			slog.Error("Failed to reply to message", "message_id", messageID, "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to reply to message: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(lua.LString(message.ID))
+		L.Push(utils.PrepareMessageTable(L, message, b.GuildID))
+		return 2
+	}
+}
+
+// quoteEmbed builds an embed quoting a message, credited to its author and
+// linking back to it.
+func quoteEmbed(message *discordgo.Message, guildID string) *discordgo.MessageEmbed {
+	snippet := []rune(message.Content)
+	description := string(snippet)
+	if len(snippet) > maxQuoteLength {
+		description = string(snippet[:maxQuoteLength-1]) + "…"
+	}
+	if description == "" {
+		description = "*No text content*"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Description: description,
+		URL:         utils.MessageLink(guildID, message.ChannelID, message.ID),
+		Timestamp:   message.Timestamp.Format(time.RFC3339),
+	}
+	if message.Author != nil {
+		embed.Author = &discordgo.MessageEmbedAuthor{
+			Name:    message.Author.DisplayName(),
+			IconURL: message.Author.AvatarURL("64"),
+			URL:     utils.MessageLink(guildID, message.ChannelID, message.ID),
+		}
+	}
+	return embed
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MessageBindingReply) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MessageBindingReply) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
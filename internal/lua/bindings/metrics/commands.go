@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MetricsBindingCommands provides Lua bindings for reading command metrics.
+type MetricsBindingCommands struct {
+	Metrics *utils.Metrics
+}
+
+// NewMetricsBindingCommands initializes a new command metrics instance.
+func NewMetricsBindingCommands(m *utils.Metrics) *MetricsBindingCommands {
+	slog.Debug("Creating new MetricsBindingCommands")
+	return &MetricsBindingCommands{
+		Metrics: m,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *MetricsBindingCommands) Name() string {
+	return "commands"
+}
+
+func (b *MetricsBindingCommands) SetSession(session *discordgo.Session) {}
+
+// Register adds the metrics-related functions to the Lua state. The returned
+// table maps each command name to its counters and timings in milliseconds.
+func (b *MetricsBindingCommands) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		commandsTable := L.NewTable()
+
+		for name, stats := range b.Metrics.Commands() {
+			statsTable := L.NewTable()
+			statsTable.RawSetString("count", lua.LNumber(stats.Count))
+			statsTable.RawSetString("errors", lua.LNumber(stats.Errors))
+			statsTable.RawSetString("error_rate", lua.LNumber(stats.ErrorRate()))
+			statsTable.RawSetString("avg_ms", lua.LNumber(stats.Average().Milliseconds()))
+			statsTable.RawSetString("max_ms", lua.LNumber(stats.Max.Milliseconds()))
+			statsTable.RawSetString("total_ms", lua.LNumber(stats.Total.Milliseconds()))
+			statsTable.RawSetString("last_used", lua.LNumber(stats.LastUsed.Unix()))
+			commandsTable.RawSetString(name, statsTable)
+		}
+
+		L.Push(commandsTable)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MetricsBindingCommands) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MetricsBindingCommands) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MetricsBindingReset provides Lua bindings for clearing command metrics.
+type MetricsBindingReset struct {
+	Metrics *utils.Metrics
+}
+
+// NewMetricsBindingReset initializes a new metrics reset instance.
+func NewMetricsBindingReset(m *utils.Metrics) *MetricsBindingReset {
+	slog.Debug("Creating new MetricsBindingReset")
+	return &MetricsBindingReset{
+		Metrics: m,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *MetricsBindingReset) Name() string {
+	return "reset"
+}
+
+func (b *MetricsBindingReset) SetSession(session *discordgo.Session) {}
+
+// Register adds the metrics-related functions to the Lua state.
+func (b *MetricsBindingReset) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		b.Metrics.Reset()
+		return 0
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MetricsBindingReset) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MetricsBindingReset) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
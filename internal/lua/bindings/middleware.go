@@ -0,0 +1,98 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MiddlewareBinding manages the `use` Lua function, which registers hooks that
+// run before every command handler.
+//
+// Middleware runs in registration order: the first registered middleware is
+// called first and receives a `next` function that calls the second one, and
+// so on until the last `next` calls the command handler. A middleware that
+// returns without calling `next` stops the chain, so the handler never runs.
+type MiddlewareBinding struct {
+	Handlers []string // Lua global handler names, in registration order
+}
+
+// NewMiddlewareBinding initializes a new MiddlewareBinding.
+func NewMiddlewareBinding() *MiddlewareBinding {
+	slog.Debug("Creating new MiddlewareBinding")
+	return &MiddlewareBinding{
+		Handlers: []string{},
+	}
+}
+
+// Name returns the name of the Lua function for this binding.
+func (b *MiddlewareBinding) Name() string {
+	return "use"
+}
+
+func (b *MiddlewareBinding) SetSession(session *discordgo.Session) {}
+
+// Register adds the `use` function to Lua.
+func (b *MiddlewareBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		handler := L.CheckFunction(1) // First argument is the middleware function
+
+		globalName := fmt.Sprintf("middleware_handler_%d", len(b.Handlers))
+		L.SetGlobal(globalName, handler)
+		b.Handlers = append(b.Handlers, globalName)
+
+		slog.Info("Registered middleware", "handler", globalName)
+		return 0
+	}
+}
+
+// Reset removes every registered middleware.
+func (b *MiddlewareBinding) Reset(L *lua.LState) {
+	for _, handler := range b.Handlers {
+		L.SetGlobal(handler, lua.LNil)
+	}
+	b.Handlers = []string{}
+}
+
+// Wrap builds a function that runs the middleware chain and finally the
+// handler with the interaction table. Calls inside the chain are unprotected,
+// so an error anywhere surfaces from the protected call of the returned function.
+// Middleware calls next without passing on what it returns, so the value the
+// handler returns is stored in returned instead.
+func (b *MiddlewareBinding) Wrap(L *lua.LState, handler lua.LValue, interactionTable *lua.LTable, returned *lua.LValue) *lua.LFunction {
+	next := L.NewFunction(func(L *lua.LState) int {
+		// Unprotected calls raise Lua errors instead of returning them.
+		_ = L.CallByParam(lua.P{Fn: handler, NRet: 1, Protect: false}, interactionTable)
+		*returned = L.Get(-1)
+		L.Pop(1)
+		return 0
+	})
+
+	for idx := len(b.Handlers) - 1; idx >= 0; idx-- {
+		middleware := L.GetGlobal(b.Handlers[idx])
+		if middleware == lua.LNil {
+			slog.Error("Lua middleware not found", "handler", b.Handlers[idx])
+			continue
+		}
+
+		inner := next
+		next = L.NewFunction(func(L *lua.LState) int {
+			_ = L.CallByParam(lua.P{Fn: middleware, NRet: 0, Protect: false}, interactionTable, inner)
+			return 0
+		})
+	}
+
+	return next
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MiddlewareBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MiddlewareBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
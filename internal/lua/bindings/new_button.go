@@ -1,6 +1,7 @@
 package bindings
 
 import (
+	"driftwood/internal/lua/utils"
 	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
@@ -40,6 +41,15 @@ func (b *NewButtonBinding) Register() lua.LGFunction {
 			L.ArgError(1, "invalid arguments, expected (name, custom_id [, disabled])")
 		}
 
+		if err := utils.CheckComponentLength("label", buttonTable.RawGetString("label").String(), utils.MaxButtonLabelLength); err != nil {
+			L.ArgError(1, err.Error())
+			return 0
+		}
+		if err := utils.CheckComponentLength("custom_id", buttonTable.RawGetString("custom_id").String(), utils.MaxCustomIDLength); err != nil {
+			L.ArgError(2, err.Error())
+			return 0
+		}
+
 		// Create a Table for the button
 		buttonTable.RawSetString("type", lua.LString("button"))
 
@@ -1,6 +1,7 @@
 package bindings
 
 import (
+	"driftwood/internal/lua/utils"
 	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
@@ -42,6 +43,15 @@ func (b *NewSelectMenuBinding) Register() lua.LGFunction {
 			L.ArgError(1, "invalid arguments, expected (name, custom_id [, disabled])")
 		}
 
+		if err := utils.CheckComponentLength("placeholder", selectTable.RawGetString("placeholder").String(), utils.MaxPlaceholderLength); err != nil {
+			L.ArgError(1, err.Error())
+			return 0
+		}
+		if err := utils.CheckComponentLength("custom_id", selectTable.RawGetString("custom_id").String(), utils.MaxCustomIDLength); err != nil {
+			L.ArgError(2, err.Error())
+			return 0
+		}
+
 		// Create a Table for the button
 		selectTable.RawSetString("type", lua.LString("select"))
 
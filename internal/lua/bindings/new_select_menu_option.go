@@ -1,6 +1,7 @@
 package bindings
 
 import (
+	"driftwood/internal/lua/utils"
 	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
@@ -24,9 +25,47 @@ func (b *NewSelectMenuOptionBinding) SetSession(session *discordgo.Session) {}
 func (b *NewSelectMenuOptionBinding) Register() lua.LGFunction {
 	slog.Info("Registering new select menu command Lua function")
 	return func(L *lua.LState) int {
+		label := L.CheckString(1)
+		value := L.CheckString(2)
+
+		if err := utils.CheckComponentLength("label", label, utils.MaxSelectOptionLabelLength); err != nil {
+			L.ArgError(1, err.Error())
+			return 0
+		}
+		if err := utils.CheckComponentLength("value", value, utils.MaxSelectOptionValueLength); err != nil {
+			L.ArgError(2, err.Error())
+			return 0
+		}
+
 		optTable := L.NewTable()
-		optTable.RawSetString("label", lua.LString(L.CheckString(1)))
-		optTable.RawSetString("value", lua.LString(L.CheckString(2)))
+		optTable.RawSetString("label", lua.LString(label))
+		optTable.RawSetString("value", lua.LString(value))
+
+		// The third argument is either the default flag or a table of extras
+		switch extra := L.Get(3).(type) {
+		case *lua.LTable:
+			optTable.RawSetString("default", lua.LBool(lua.LVAsBool(extra.RawGetString("default"))))
+			if description := extra.RawGetString("description"); description != lua.LNil {
+				if description.Type() != lua.LTString {
+					L.ArgError(3, "description must be a string")
+					return 0
+				}
+				if err := utils.CheckComponentLength("description", description.String(), utils.MaxSelectOptionDescLength); err != nil {
+					L.ArgError(3, err.Error())
+					return 0
+				}
+				optTable.RawSetString("description", description)
+			}
+			if emoji := extra.RawGetString("emoji"); emoji != lua.LNil {
+				if emoji.Type() != lua.LTString {
+					L.ArgError(3, "emoji must be a string")
+					return 0
+				}
+				optTable.RawSetString("emoji", emoji)
+			}
+		default:
+			optTable.RawSetString("default", lua.LBool(L.OptBool(3, false)))
+		}
 		L.Push(optTable)
 		return 1
 	}
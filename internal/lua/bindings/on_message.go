@@ -0,0 +1,79 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MessageEventBinding manages the `on_message` Lua function, which registers
+// handlers called with every message sent where the bot can see it, other
+// than the bot's own.
+type MessageEventBinding struct {
+	Handlers *EventHandlers
+}
+
+// NewMessageEventBinding initializes a new MessageEventBinding.
+func NewMessageEventBinding() *MessageEventBinding {
+	slog.Debug("Creating new MessageEventBinding")
+	return &MessageEventBinding{
+		Handlers: NewEventHandlers("message"),
+	}
+}
+
+// Name returns the name of the Lua function for this binding.
+func (b *MessageEventBinding) Name() string {
+	return "on_message"
+}
+
+func (b *MessageEventBinding) SetSession(session *discordgo.Session) {}
+
+// Register adds the `on_message` function to Lua.
+func (b *MessageEventBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		handler := L.CheckFunction(1) // First argument is the handler function
+
+		globalName := b.Handlers.Add(L, handler)
+		slog.Info("Registered message handler", "handler", globalName)
+		return 0
+	}
+}
+
+// Reset removes every registered message handler.
+func (b *MessageEventBinding) Reset(L *lua.LState) {
+	b.Handlers.Reset(L)
+}
+
+// RequiredIntents requests message events, including their content,
+// attachments and embeds, once a script registers a message handler.
+func (b *MessageEventBinding) RequiredIntents() discordgo.Intent {
+	if b.Handlers.Len() == 0 {
+		return discordgo.IntentsNone
+	}
+	return discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
+}
+
+// HandleMessage calls every registered handler with the message table.
+func (b *MessageEventBinding) HandleMessage(message *discordgo.MessageCreate) {
+	if b.Handlers.Len() == 0 {
+		return
+	}
+
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		b.Handlers.Call(L, func() []lua.LValue {
+			return []lua.LValue{utils.PrepareMessageTable(L, message.Message, message.GuildID)}
+		})
+	})
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *MessageEventBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *MessageEventBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
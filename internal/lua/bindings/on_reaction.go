@@ -0,0 +1,96 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ReactionEventBinding manages the `on_reaction_add` or `on_reaction_remove`
+// Lua function, which registers handlers called when a reaction is added to
+// or removed from a message, other than by the bot itself.
+type ReactionEventBinding struct {
+	Handlers *EventHandlers
+	added    bool // Whether this binding handles added rather than removed reactions
+}
+
+// NewReactionEventBinding initializes a new ReactionEventBinding for added
+// or removed reactions.
+func NewReactionEventBinding(added bool) *ReactionEventBinding {
+	slog.Debug("Creating new ReactionEventBinding", "added", added)
+	event := "reaction_remove"
+	if added {
+		event = "reaction_add"
+	}
+	return &ReactionEventBinding{
+		Handlers: NewEventHandlers(event),
+		added:    added,
+	}
+}
+
+// Name returns the name of the Lua function for this binding.
+func (b *ReactionEventBinding) Name() string {
+	if b.added {
+		return "on_reaction_add"
+	}
+	return "on_reaction_remove"
+}
+
+func (b *ReactionEventBinding) SetSession(session *discordgo.Session) {}
+
+// Register adds the `on_reaction_add` or `on_reaction_remove` function to Lua.
+func (b *ReactionEventBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		handler := L.CheckFunction(1) // First argument is the handler function
+
+		globalName := b.Handlers.Add(L, handler)
+		slog.Info("Registered reaction handler", "handler", globalName)
+		return 0
+	}
+}
+
+// Reset removes every registered reaction handler.
+func (b *ReactionEventBinding) Reset(L *lua.LState) {
+	b.Handlers.Reset(L)
+}
+
+// RequiredIntents requests reaction events once a script registers a
+// reaction handler.
+func (b *ReactionEventBinding) RequiredIntents() discordgo.Intent {
+	if b.Handlers.Len() == 0 {
+		return discordgo.IntentsNone
+	}
+	return discordgo.IntentsGuildMessageReactions | discordgo.IntentsDirectMessageReactions
+}
+
+// HandleReaction calls every registered handler with the reaction table, if
+// the binding handles reactions being added or removed as given.
+func (b *ReactionEventBinding) HandleReaction(reaction *discordgo.MessageReaction, added bool) {
+	if added != b.added || b.Handlers.Len() == 0 {
+		return
+	}
+
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		b.Handlers.Call(L, func() []lua.LValue {
+			reactionTable := L.NewTable()
+			reactionTable.RawSetString("message_id", lua.LString(reaction.MessageID))
+			reactionTable.RawSetString("channel_id", lua.LString(reaction.ChannelID))
+			reactionTable.RawSetString("guild_id", lua.LString(reaction.GuildID))
+			reactionTable.RawSetString("user_id", lua.LString(reaction.UserID))
+			reactionTable.RawSetString("emoji", utils.PrepareEmojiTable(L, &reaction.Emoji))
+			return []lua.LValue{reactionTable}
+		})
+	})
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ReactionEventBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ReactionEventBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,144 @@
+package bindings
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// optionRequirement makes an option required only when other options of the
+// same command were given, or given certain values. Discord can't express
+// this, so it is checked when the command is handled.
+type optionRequirement struct {
+	command string         // Command or subcommand, as `command_subcommand`
+	option  string         // The conditionally required option
+	when    map[string]any // Option names to the values they must have, nil for any value
+	root    string         // Top-level command that declared it
+}
+
+// bindRequirement parses the "required_if" of an option. It is either the
+// name of an option whose presence makes this one required, or a table of
+// option names to the values that all must be given.
+func (b *ApplicationCommandBinding) bindRequirement(L *lua.LState, root, commandName string, option *discordgo.ApplicationCommandOption, value lua.LValue) {
+	if option.Required {
+		L.ArgError(1, fmt.Sprintf("option '%s/%s' cannot be both required and 'required_if'", commandName, option.Name))
+		return
+	}
+	if option.Type == discordgo.ApplicationCommandOptionSubCommand || option.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+		L.ArgError(1, fmt.Sprintf("subcommand '%s/%s' cannot have 'required_if'", commandName, option.Name))
+		return
+	}
+
+	when := make(map[string]any)
+	switch v := value.(type) {
+	case lua.LString:
+		when[string(v)] = nil
+	case *lua.LTable:
+		v.ForEach(func(key, expected lua.LValue) {
+			switch e := expected.(type) {
+			case lua.LString:
+				when[key.String()] = string(e)
+			case lua.LNumber:
+				when[key.String()] = float64(e)
+			case lua.LBool:
+				when[key.String()] = bool(e)
+			default:
+				L.ArgError(1, fmt.Sprintf("'required_if' of option '%s/%s' must compare against strings, numbers or booleans", commandName, option.Name))
+			}
+		})
+	default:
+		L.ArgError(1, fmt.Sprintf("'required_if' of option '%s/%s' must be an option name or a table of option values", commandName, option.Name))
+		return
+	}
+	if len(when) == 0 {
+		L.ArgError(1, fmt.Sprintf("'required_if' of option '%s/%s' names no options", commandName, option.Name))
+		return
+	}
+
+	b.requirements[commandName+"/"+option.Name] = optionRequirement{
+		command: commandName,
+		option:  option.Name,
+		when:    when,
+		root:    root,
+	}
+}
+
+// checkRequirementTargets verifies the conditions of a command's options only
+// name options the command has, once all of them are parsed.
+func (b *ApplicationCommandBinding) checkRequirementTargets(L *lua.LState, commandName string, options []*discordgo.ApplicationCommandOption) {
+	for _, requirement := range b.requirements {
+		if requirement.command != commandName {
+			continue
+		}
+		for name := range requirement.when {
+			known := slices.ContainsFunc(options, func(option *discordgo.ApplicationCommandOption) bool {
+				return option.Name == name && option.Name != requirement.option
+			})
+			if !known {
+				L.ArgError(1, fmt.Sprintf("'required_if' of option '%s/%s' refers to unknown option '%s'", commandName, requirement.option, name))
+				return
+			}
+		}
+	}
+}
+
+// checkRequirements verifies the conditionally required options of a command
+// were given. Returns a message for the user when one is missing, or an
+// empty string.
+func (b *ApplicationCommandBinding) checkRequirements(commandName string, options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	given := make(map[string]any, len(options))
+	for _, opt := range options {
+		given[opt.Name] = opt.Value
+	}
+
+	// Sorted, so the same option is reported first every time
+	keys := make([]string, 0, len(b.requirements))
+	for key, requirement := range b.requirements {
+		if requirement.command == commandName {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		requirement := b.requirements[key]
+		if _, ok := given[requirement.option]; ok || !requirement.applies(given) {
+			continue
+		}
+		return fmt.Sprintf("`%s` is required when %s.", requirement.option, requirement.describe())
+	}
+	return ""
+}
+
+// applies reports whether the given options meet every condition.
+func (r optionRequirement) applies(given map[string]any) bool {
+	for name, expected := range r.when {
+		value, ok := given[name]
+		if !ok || (expected != nil && value != expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe words the conditions for the user, e.g. "`recurring` is true".
+func (r optionRequirement) describe() string {
+	names := make([]string, 0, len(r.when))
+	for name := range r.when {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	conditions := make([]string, 0, len(names))
+	for _, name := range names {
+		if expected := r.when[name]; expected != nil {
+			conditions = append(conditions, fmt.Sprintf("`%s` is %v", name, expected))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("`%s` is given", name))
+		}
+	}
+	return strings.Join(conditions, " and ")
+}
@@ -0,0 +1,75 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/bindings/options"
+	"driftwood/internal/lua/utils"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// newTestCommandBinding returns a command binding without a session, so
+// registered commands are only declared and never synced with Discord.
+func newTestCommandBinding() *ApplicationCommandBinding {
+	state := utils.NewStateManager()
+	return NewApplicationCommandBinding("guild", NewMiddlewareBinding(), NewUnknownCommandBinding(), utils.NewMetrics(), utils.NewAuditLog(), utils.NewCooldowns(), state)
+}
+
+func TestOptionSharedBetweenCommands(t *testing.T) {
+	commands := newTestCommandBinding()
+
+	L := lua.NewState()
+	defer L.Close()
+	L.SetGlobal("new_string", L.NewFunction(options.NewNewOptionStringBinding().Register()))
+	L.SetGlobal("register", L.NewFunction(commands.Register()))
+
+	err := L.DoString(`
+		local choices = { { name = "Red", value = "red" }, { name = "Blue", value = "blue" } }
+		local color = new_string("color", "The color to use", true, choices)
+
+		-- Builders return a fresh descriptor every call
+		local again = new_string("color", "The color to use", true, choices)
+		assert(not rawequal(color, again), "builder returned the same table twice")
+		assert(not rawequal(color.choices, again.choices), "builder shared its choices table")
+		assert(not rawequal(color.choices, choices), "builder kept the caller's choices table")
+
+		register({ name = "paint", description = "Paint something", options = { color } })
+		register({ name = "dye", description = "Dye something", options = { color } })
+
+		-- Registering doesn't change the shared descriptor
+		assert(color.name == "color" and color.description == "The color to use" and color.required == true)
+		assert(#color.choices == 2 and color.choices[1].name == "Red")
+
+		-- Nor does a later change to the caller's choices
+		choices[1].name = "Changed"
+		assert(color.choices[1].name == "Red", "option followed a change to the caller's choices")
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(commands.definitions) != 2 {
+		t.Fatalf("got %d commands, want 2", len(commands.definitions))
+	}
+	paint, dye := commands.definitions[0], commands.definitions[1]
+	if len(paint.Options) != 1 || len(dye.Options) != 1 {
+		t.Fatalf("got %d and %d options, want 1 each", len(paint.Options), len(dye.Options))
+	}
+	if paint.Options[0] == dye.Options[0] {
+		t.Fatal("both commands hold the same option descriptor")
+	}
+
+	// Changing one command's option leaves the other's as declared
+	paint.Options[0].Description = "Changed"
+	paint.Options[0].Required = false
+	paint.Options[0].Choices[0].Name = "Changed"
+	paint.Options[0].Choices = paint.Options[0].Choices[:1]
+
+	option := dye.Options[0]
+	if option.Name != "color" || option.Description != "The color to use" || !option.Required {
+		t.Errorf("dye option changed along with paint's: %+v", option)
+	}
+	if len(option.Choices) != 2 || option.Choices[0].Name != "Red" || option.Choices[1].Value != "blue" {
+		t.Errorf("dye choices changed along with paint's: %+v", option.Choices)
+	}
+}
@@ -0,0 +1,111 @@
+package parse
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ParseBindingCSV provides Lua bindings for parsing CSV text, such as a
+// downloaded attachment.
+type ParseBindingCSV struct{}
+
+// NewParseBindingCSV initializes a new CSV parsing instance.
+func NewParseBindingCSV() *ParseBindingCSV {
+	slog.Debug("Creating new ParseBindingCSV")
+	return &ParseBindingCSV{}
+}
+
+// Name returns the name of the binding.
+func (b *ParseBindingCSV) Name() string {
+	return "csv"
+}
+
+func (b *ParseBindingCSV) SetSession(session *discordgo.Session) {}
+
+// Register registers the parse-related functions in the Lua state. Rows are
+// returned as arrays of fields, or keyed by the column names when the first
+// row is a header.
+func (b *ParseBindingCSV) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		content := L.CheckString(1)
+		opts := L.OptTable(2, nil)
+
+		header := false
+		delimiter := ','
+		if opts != nil {
+			if raw := opts.RawGetString("header"); raw != lua.LNil {
+				if raw.Type() != lua.LTBool {
+					L.ArgError(2, "options.header must be a boolean")
+					return 0
+				}
+				header = lua.LVAsBool(raw)
+			}
+			if raw := opts.RawGetString("delimiter"); raw != lua.LNil {
+				if raw.Type() != lua.LTString || utf8.RuneCountInString(raw.String()) != 1 {
+					L.ArgError(2, "options.delimiter must be a single character")
+					return 0
+				}
+				delimiter, _ = utf8.DecodeRuneInString(raw.String())
+				if delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+					L.ArgError(2, "options.delimiter cannot be a quote or line break")
+					return 0
+				}
+			}
+		}
+
+		// Spreadsheet exports often start with a byte order mark, which would
+		// otherwise end up in the first column name
+		reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(content, "\ufeff")))
+		reader.Comma = delimiter
+
+		var columns []string
+		rowsTable := L.NewTable()
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				L.Push(lua.LNil)
+				L.Push(lua.LString(fmt.Sprintf("Failed to parse CSV: %s", err.Error())))
+				return 2
+			}
+
+			if header && columns == nil {
+				columns = record
+				continue
+			}
+
+			rowTable := L.NewTable()
+			for idx, field := range record {
+				if header {
+					rowTable.RawSetString(columns[idx], lua.LString(field))
+				} else {
+					rowTable.Append(lua.LString(field))
+				}
+			}
+			rowsTable.Append(rowTable)
+		}
+
+		L.Push(rowsTable)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ParseBindingCSV) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ParseBindingCSV) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
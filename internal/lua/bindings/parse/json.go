@@ -0,0 +1,56 @@
+package parse
+
+import (
+	"driftwood/internal/lua/utils"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ParseBindingJSON provides Lua bindings for decoding JSON text, such as a
+// downloaded attachment.
+type ParseBindingJSON struct{}
+
+// NewParseBindingJSON initializes a new JSON parsing instance.
+func NewParseBindingJSON() *ParseBindingJSON {
+	slog.Debug("Creating new ParseBindingJSON")
+	return &ParseBindingJSON{}
+}
+
+// Name returns the name of the binding.
+func (b *ParseBindingJSON) Name() string {
+	return "json"
+}
+
+func (b *ParseBindingJSON) SetSession(session *discordgo.Session) {}
+
+// Register registers the parse-related functions in the Lua state. Objects
+// and arrays become tables, and JSON null becomes nil.
+func (b *ParseBindingJSON) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		content := L.CheckString(1)
+
+		var value any
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to parse JSON: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(utils.GoToLua(L, value))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ParseBindingJSON) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ParseBindingJSON) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,206 @@
+package presence
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// activityTypes maps the activity names accepted from Lua to Discord activity types.
+var activityTypes = map[string]discordgo.ActivityType{
+	"playing":   discordgo.ActivityTypeGame,
+	"listening": discordgo.ActivityTypeListening,
+	"watching":  discordgo.ActivityTypeWatching,
+	"competing": discordgo.ActivityTypeCompeting,
+	"custom":    discordgo.ActivityTypeCustom,
+}
+
+// onlineStatuses lists the statuses Discord accepts for a bot.
+var onlineStatuses = map[string]bool{
+	"online":    true,
+	"idle":      true,
+	"dnd":       true,
+	"invisible": true,
+}
+
+// Status is a single presence: an activity line and the online status.
+type Status struct {
+	Text   string
+	Type   discordgo.ActivityType
+	Online string
+}
+
+// Presence updates the bot's presence and owns the rotation started by
+// `presence.rotate`, so a later `presence.set` or `presence.rotate` can stop it.
+type Presence struct {
+	GuildID string
+
+	mu      sync.Mutex
+	session *discordgo.Session
+	stop    chan struct{} // Closed to stop the current rotation, nil when not rotating
+}
+
+// NewPresence initializes the presence of the bot.
+func NewPresence(guildID string) *Presence {
+	slog.Debug("Creating new Presence")
+	return &Presence{
+		GuildID: guildID,
+	}
+}
+
+// SetSession records the session presence updates are sent over.
+func (p *Presence) SetSession(session *discordgo.Session) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.session = session
+}
+
+// Update sends a presence to Discord after filling in its template.
+func (p *Presence) Update(status Status) error {
+	p.mu.Lock()
+	session := p.session
+	p.mu.Unlock()
+
+	if session == nil {
+		return fmt.Errorf("bot is not connected yet")
+	}
+
+	text := p.expand(session, status.Text)
+	activity := &discordgo.Activity{Name: text, Type: status.Type}
+	if status.Type == discordgo.ActivityTypeCustom {
+		activity = &discordgo.Activity{Name: "Custom Status", Type: status.Type, State: text}
+	}
+
+	return session.UpdateStatusComplex(discordgo.UpdateStatusData{
+		Activities: []*discordgo.Activity{activity},
+		Status:     status.Online,
+	})
+}
+
+// Rotate stops any current rotation and cycles through the statuses, one per
+// interval, starting with the first one right away.
+func (p *Presence) Rotate(statuses []Status, interval time.Duration) {
+	stop := make(chan struct{})
+
+	p.mu.Lock()
+	if p.stop != nil {
+		close(p.stop)
+	}
+	p.stop = stop
+	p.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for idx := 0; ; idx = (idx + 1) % len(statuses) {
+			if err := p.Update(statuses[idx]); err != nil {
+				slog.Warn("Failed to rotate presence", "error", err)
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// StopRotation stops the current rotation, if any.
+func (p *Presence) StopRotation() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+// expand replaces the placeholders supported in presence text with live values.
+func (p *Presence) expand(session *discordgo.Session, text string) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+
+	memberCount, guildName := 0, ""
+	if guild, err := session.State.Guild(p.GuildID); err == nil {
+		memberCount, guildName = guild.MemberCount, guild.Name
+	}
+
+	return strings.NewReplacer(
+		"{member_count}", formatCount(memberCount),
+		"{guild_count}", formatCount(len(session.State.Guilds)),
+		"{guild_name}", guildName,
+	).Replace(text)
+}
+
+// formatCount renders a number with thousands separators, e.g. 1,234.
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	var out strings.Builder
+	for idx, digit := range digits {
+		if idx > 0 && (len(digits)-idx)%3 == 0 {
+			out.WriteByte(',')
+		}
+		out.WriteRune(digit)
+	}
+	return out.String()
+}
+
+// parseStatus reads a presence from a Lua value: either the activity text,
+// or a table with "text" and optional "type" and "status" fields. Fields
+// that are not set come from the defaults.
+func parseStatus(value lua.LValue, defaults Status) (Status, error) {
+	status := defaults
+
+	switch v := value.(type) {
+	case lua.LString:
+		status.Text = string(v)
+	case *lua.LTable:
+		text := v.RawGetString("text")
+		if text.Type() != lua.LTString {
+			return status, fmt.Errorf("'text' must be a string")
+		}
+		status.Text = text.String()
+
+		var err error
+		if status, err = parseStatusOptions(v, status); err != nil {
+			return status, err
+		}
+	default:
+		return status, fmt.Errorf("status must be a string or a table")
+	}
+
+	if status.Text == "" || len(status.Text) > 128 {
+		return status, fmt.Errorf("status text must be between 1 and 128 characters")
+	}
+	return status, nil
+}
+
+// parseStatusOptions applies the "type" and "status" fields of a table.
+func parseStatusOptions(table *lua.LTable, status Status) (Status, error) {
+	if raw := table.RawGetString("type"); raw != lua.LNil {
+		activityType, ok := activityTypes[raw.String()]
+		if !ok {
+			return status, fmt.Errorf("invalid type '%s', expected playing, listening, watching, competing or custom", raw.String())
+		}
+		status.Type = activityType
+	}
+
+	if raw := table.RawGetString("status"); raw != lua.LNil {
+		if !onlineStatuses[raw.String()] {
+			return status, fmt.Errorf("invalid status '%s', expected online, idle, dnd or invisible", raw.String())
+		}
+		status.Online = raw.String()
+	}
+
+	return status, nil
+}
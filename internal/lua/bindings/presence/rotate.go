@@ -0,0 +1,87 @@
+package presence
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// minRotateInterval keeps rotations well within Discord's presence update rate limit.
+const minRotateInterval = 15 * time.Second
+
+// PresenceBindingRotate provides Lua bindings for cycling through presences.
+type PresenceBindingRotate struct {
+	Presence *Presence
+}
+
+// NewPresenceBindingRotate initializes a new presence rotate instance.
+func NewPresenceBindingRotate(presence *Presence) *PresenceBindingRotate {
+	slog.Debug("Creating new PresenceBindingRotate")
+	return &PresenceBindingRotate{
+		Presence: presence,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *PresenceBindingRotate) Name() string {
+	return "rotate"
+}
+
+func (b *PresenceBindingRotate) SetSession(session *discordgo.Session) {
+	b.Presence.SetSession(session)
+}
+
+// Register registers the presence-related functions in the Lua state. A new
+// rotation replaces the previous one.
+func (b *PresenceBindingRotate) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		list := L.CheckTable(1)
+		interval := time.Duration(float64(L.CheckNumber(2)) * float64(time.Second))
+		options := L.OptTable(3, nil)
+
+		if interval < minRotateInterval {
+			L.ArgError(2, fmt.Sprintf("interval must be at least %d seconds", int(minRotateInterval.Seconds())))
+			return 0
+		}
+
+		defaults := Status{Type: discordgo.ActivityTypeGame, Online: "online"}
+		if options != nil {
+			var err error
+			if defaults, err = parseStatusOptions(options, defaults); err != nil {
+				L.ArgError(3, err.Error())
+				return 0
+			}
+		}
+
+		var statuses []Status
+		for idx := 1; idx <= list.Len(); idx++ {
+			status, err := parseStatus(list.RawGetInt(idx), defaults)
+			if err != nil {
+				L.ArgError(1, fmt.Sprintf("entry %d: %s", idx, err.Error()))
+				return 0
+			}
+			statuses = append(statuses, status)
+		}
+		if len(statuses) == 0 {
+			L.ArgError(1, "list must contain at least one status")
+			return 0
+		}
+
+		b.Presence.Rotate(statuses, interval)
+		slog.Info("Started presence rotation", "statuses", len(statuses), "interval", interval)
+		return 0
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *PresenceBindingRotate) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *PresenceBindingRotate) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
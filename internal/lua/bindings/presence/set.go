@@ -0,0 +1,76 @@
+package presence
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PresenceBindingSet provides Lua bindings for setting the bot's presence.
+type PresenceBindingSet struct {
+	Presence *Presence
+}
+
+// NewPresenceBindingSet initializes a new presence set instance.
+func NewPresenceBindingSet(presence *Presence) *PresenceBindingSet {
+	slog.Debug("Creating new PresenceBindingSet")
+	return &PresenceBindingSet{
+		Presence: presence,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *PresenceBindingSet) Name() string {
+	return "set"
+}
+
+func (b *PresenceBindingSet) SetSession(session *discordgo.Session) {
+	b.Presence.SetSession(session)
+}
+
+// Register registers the presence-related functions in the Lua state.
+// Setting a presence stops any rotation.
+func (b *PresenceBindingSet) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		text := L.CheckString(1)
+		options := L.OptTable(2, nil)
+
+		defaults := Status{Type: discordgo.ActivityTypeGame, Online: "online"}
+		if options != nil {
+			var err error
+			if defaults, err = parseStatusOptions(options, defaults); err != nil {
+				L.ArgError(2, err.Error())
+				return 0
+			}
+		}
+
+		status, err := parseStatus(lua.LString(text), defaults)
+		if err != nil {
+			L.ArgError(1, err.Error())
+			return 0
+		}
+
+		b.Presence.StopRotation()
+		if err := b.Presence.Update(status); err != nil {
+			slog.Error("Failed to set presence", "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to set presence: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *PresenceBindingSet) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *PresenceBindingSet) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
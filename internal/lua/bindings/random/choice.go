@@ -0,0 +1,55 @@
+package random
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// RandomBindingChoice provides Lua bindings for picking a random element of an array.
+type RandomBindingChoice struct {
+	Random *Random
+}
+
+// NewRandomBindingChoice initializes a new random choice instance.
+func NewRandomBindingChoice(random *Random) *RandomBindingChoice {
+	slog.Debug("Creating new RandomBindingChoice")
+	return &RandomBindingChoice{
+		Random: random,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *RandomBindingChoice) Name() string {
+	return "choice"
+}
+
+func (b *RandomBindingChoice) SetSession(session *discordgo.Session) {}
+
+// Register registers the random-related functions in the Lua state. An
+// empty array has nothing to pick, so nil is returned.
+func (b *RandomBindingChoice) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		array := L.CheckTable(1)
+
+		length := array.Len()
+		if length == 0 {
+			L.Push(lua.LNil)
+			return 1
+		}
+
+		L.Push(array.RawGetInt(b.Random.Intn(length) + 1))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *RandomBindingChoice) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *RandomBindingChoice) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
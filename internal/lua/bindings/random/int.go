@@ -0,0 +1,59 @@
+package random
+
+import (
+	"log/slog"
+	"math"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// RandomBindingInt provides Lua bindings for picking a random integer.
+type RandomBindingInt struct {
+	Random *Random
+}
+
+// NewRandomBindingInt initializes a new random int instance.
+func NewRandomBindingInt(random *Random) *RandomBindingInt {
+	slog.Debug("Creating new RandomBindingInt")
+	return &RandomBindingInt{
+		Random: random,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *RandomBindingInt) Name() string {
+	return "int"
+}
+
+func (b *RandomBindingInt) SetSession(session *discordgo.Session) {}
+
+// Register registers the random-related functions in the Lua state. Both
+// bounds are inclusive, so `int(1, 6)` rolls a die.
+func (b *RandomBindingInt) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		min := L.CheckInt64(1)
+		max := L.CheckInt64(2)
+		if min > max {
+			L.ArgError(2, "max must be greater than or equal to min")
+			return 0
+		}
+		if max-min < 0 || max-min == math.MaxInt64 {
+			L.ArgError(2, "range between min and max is too large")
+			return 0
+		}
+
+		L.Push(lua.LNumber(min + b.Random.Int63n(max-min+1)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *RandomBindingInt) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *RandomBindingInt) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,57 @@
+package random
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Random is the generator shared by the random bindings. It is seeded from
+// crypto/rand at startup, unlike Lua's math.random which is never seeded.
+type Random struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRandom initializes a generator seeded from crypto/rand, falling back to
+// the current time if the system source fails.
+func NewRandom() *Random {
+	slog.Debug("Creating new Random")
+
+	var seed int64
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		slog.Warn("Failed to seed random generator from crypto/rand, using the time instead", "error", err)
+		seed = time.Now().UnixNano()
+	} else {
+		seed = int64(binary.LittleEndian.Uint64(buf[:]))
+	}
+
+	return &Random{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Intn returns a number in [0, n).
+func (r *Random) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
+
+// Int63n returns a number in [0, n).
+func (r *Random) Int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Int63n(n)
+}
+
+// Shuffle randomises the order of n elements using swap.
+func (r *Random) Shuffle(n int, swap func(i, j int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rng.Shuffle(n, swap)
+}
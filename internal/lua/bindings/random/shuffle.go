@@ -0,0 +1,62 @@
+package random
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// RandomBindingShuffle provides Lua bindings for shuffling an array.
+type RandomBindingShuffle struct {
+	Random *Random
+}
+
+// NewRandomBindingShuffle initializes a new random shuffle instance.
+func NewRandomBindingShuffle(random *Random) *RandomBindingShuffle {
+	slog.Debug("Creating new RandomBindingShuffle")
+	return &RandomBindingShuffle{
+		Random: random,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *RandomBindingShuffle) Name() string {
+	return "shuffle"
+}
+
+func (b *RandomBindingShuffle) SetSession(session *discordgo.Session) {}
+
+// Register registers the random-related functions in the Lua state. The
+// shuffled elements are returned as a new array, leaving the input as is.
+func (b *RandomBindingShuffle) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		array := L.CheckTable(1)
+
+		elements := make([]lua.LValue, array.Len())
+		for idx := range elements {
+			elements[idx] = array.RawGetInt(idx + 1)
+		}
+		b.Random.Shuffle(len(elements), func(i, j int) {
+			elements[i], elements[j] = elements[j], elements[i]
+		})
+
+		shuffled := L.CreateTable(len(elements), 0)
+		for _, element := range elements {
+			shuffled.Append(element)
+		}
+
+		L.Push(shuffled)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *RandomBindingShuffle) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *RandomBindingShuffle) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -1,6 +1,7 @@
 package reaction
 
 import (
+	"driftwood/internal/lua/utils"
 	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
@@ -27,13 +28,21 @@ func (b *ReactionBindingRemove) SetSession(session *discordgo.Session) {
 	b.Session = session
 }
 
-// Register registers the reaction-related functions in the Lua state.
+// Register registers the reaction-related functions in the Lua state. In
+// dry-run mode the reactions are kept and a preview is returned alongside true.
 func (b *ReactionBindingRemove) Register() lua.LGFunction {
 	return func(L *lua.LState) int {
 		messageID := L.CheckString(1)
 		channelID := L.CheckString(2)
 		content := L.CheckString(3)
 
+		if utils.DryRun() {
+			slog.Info("Dry run: would remove reactions", "message_id", messageID, "channel_id", channelID, "emoji", content)
+			L.Push(lua.LTrue)
+			L.Push(utils.PrepareDryRunPreview(L, "remove_reaction", []string{messageID}))
+			return 2
+		}
+
 		err := b.Session.MessageReactionsRemoveEmoji(channelID, messageID, content)
 		if err != nil {
 			slog.Error("Failed to react to message", "message_id", messageID, "channel_id", channelID, "error", err)
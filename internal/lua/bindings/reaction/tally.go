@@ -0,0 +1,89 @@
+package reaction
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ReactionBindingTally provides Lua bindings for counting the reactions on a message.
+type ReactionBindingTally struct {
+	Session *discordgo.Session
+}
+
+// NewReactionBindingTally initializes a new reaction tally instance.
+func NewReactionBindingTally() *ReactionBindingTally {
+	slog.Debug("Creating new ReactionBindingTally")
+	return &ReactionBindingTally{}
+}
+
+// Name returns the name of the binding.
+func (b *ReactionBindingTally) Name() string {
+	return "tally"
+}
+
+func (b *ReactionBindingTally) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the reaction-related functions in the Lua state. The
+// counts come with the message itself, so no reactor lists are fetched.
+// Emojis are keyed the way `reaction.add` accepts them, `name:id` for
+// custom emojis.
+func (b *ReactionBindingTally) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID := L.CheckString(1)
+		messageID := L.CheckString(2)
+		opts := L.OptTable(3, nil)
+
+		excludeSelf := false
+		if opts != nil {
+			if raw := opts.RawGetString("exclude_self"); raw != lua.LNil {
+				if raw.Type() != lua.LTBool {
+					L.ArgError(3, "options.exclude_self must be a boolean")
+					return 0
+				}
+				excludeSelf = lua.LVAsBool(raw)
+			}
+		}
+
+		message, err := b.Session.ChannelMessage(channelID, messageID)
+		if err != nil {
+			slog.Error("Failed to fetch message for reaction tally", "message_id", messageID, "channel_id", channelID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to fetch message: %s", err.Error())))
+			return 2
+		}
+
+		tallyTable := L.NewTable()
+		for _, reaction := range message.Reactions {
+			if reaction.Emoji == nil {
+				continue
+			}
+
+			count := reaction.Count
+			if excludeSelf && reaction.Me {
+				count--
+			}
+			if count <= 0 {
+				continue
+			}
+			tallyTable.RawSetString(reaction.Emoji.APIName(), lua.LNumber(count))
+		}
+
+		L.Push(tallyTable)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ReactionBindingTally) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ReactionBindingTally) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
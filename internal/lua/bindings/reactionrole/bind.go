@@ -0,0 +1,68 @@
+package reactionrole
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ReactionRoleBindingBind provides Lua bindings for binding a reaction to a role.
+type ReactionRoleBindingBind struct {
+	Roles *ReactionRoles
+
+	used bool // Set once a script binds a reaction role
+}
+
+// NewReactionRoleBindingBind initializes a new reaction role bind instance.
+func NewReactionRoleBindingBind(roles *ReactionRoles) *ReactionRoleBindingBind {
+	slog.Debug("Creating new ReactionRoleBindingBind")
+	return &ReactionRoleBindingBind{
+		Roles: roles,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *ReactionRoleBindingBind) Name() string {
+	return "bind"
+}
+
+func (b *ReactionRoleBindingBind) SetSession(session *discordgo.Session) {}
+
+// Register registers the reaction role functions in the Lua state. Binding
+// an emoji that is already bound on the message replaces its role.
+func (b *ReactionRoleBindingBind) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		messageID := L.CheckString(1)
+		emoji := L.CheckString(2)
+		roleID := L.CheckString(3)
+
+		b.Roles.Bind(messageID, emoji, roleID)
+		b.used = true
+		slog.Info("Bound reaction role", "message_id", messageID, "emoji", emoji, "role_id", roleID)
+		return 0
+	}
+}
+
+// RequiredIntents requests reaction events once a script binds a reaction
+// role, or while mappings saved before a restart are kept, as those keep
+// granting roles without being bound again.
+func (b *ReactionRoleBindingBind) RequiredIntents() discordgo.Intent {
+	if !b.used && b.Roles.Any() {
+		b.used = true
+	}
+	if !b.used {
+		return discordgo.IntentsNone
+	}
+	return discordgo.IntentsGuildMessageReactions
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ReactionRoleBindingBind) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ReactionRoleBindingBind) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
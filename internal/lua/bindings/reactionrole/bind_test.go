@@ -0,0 +1,56 @@
+package reactionrole
+
+import (
+	"driftwood/internal/lua/utils"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestBindRequiredIntents(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored map[string]string // State present before the scripts run
+		script string
+		want   discordgo.Intent
+	}{
+		{name: "unused", want: discordgo.IntentsNone},
+		{
+			name:   "bound by a script",
+			script: `bind("1", "👍", "3")`,
+			want:   discordgo.IntentsGuildMessageReactions,
+		},
+		{
+			name:   "saved before a restart",
+			stored: map[string]string{"__reaction_role:1:👍": "3"},
+			want:   discordgo.IntentsGuildMessageReactions,
+		},
+		{
+			name:   "other state only",
+			stored: map[string]string{"reaction_role:1:👍": "3", "score": "1"},
+			want:   discordgo.IntentsNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := utils.NewStateManager()
+			for key, value := range tt.stored {
+				sm.Set(key, lua.LString(value), 0)
+			}
+			binding := NewReactionRoleBindingBind(NewReactionRoles(sm))
+
+			L := lua.NewState()
+			defer L.Close()
+			L.SetGlobal("bind", L.NewFunction(binding.Register()))
+			if err := L.DoString(tt.script); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := binding.RequiredIntents(); got != tt.want {
+				t.Errorf("RequiredIntents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package reactionrole
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"driftwood/internal/lua/utils"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ReactionRoles maps reactions on messages to roles. Each mapping is kept in
+// the StateManager, so it is saved along with the rest of the state when
+// persistence is enabled and keeps working after a restart.
+type ReactionRoles struct {
+	StateManager *utils.StateManager
+}
+
+// NewReactionRoles initializes the reaction role mappings.
+func NewReactionRoles(sm *utils.StateManager) *ReactionRoles {
+	slog.Debug("Creating new ReactionRoles")
+	return &ReactionRoles{
+		StateManager: sm,
+	}
+}
+
+// Bind grants roleID to members who react to the message with the emoji.
+func (rr *ReactionRoles) Bind(messageID, emoji, roleID string) {
+	rr.StateManager.Set(stateKey(messageID, emoji), lua.LString(roleID), 0)
+}
+
+// Unbind removes the mapping of the emoji on the message.
+func (rr *ReactionRoles) Unbind(messageID, emoji string) {
+	rr.StateManager.Clear(stateKey(messageID, emoji))
+}
+
+// Lookup returns the role bound to the emoji on the message, or an empty string.
+func (rr *ReactionRoles) Lookup(messageID, emoji string) string {
+	role, ok := rr.StateManager.Get(stateKey(messageID, emoji)).(lua.LString)
+	if !ok {
+		return ""
+	}
+	return string(role)
+}
+
+// Any reports whether any mapping is kept, including those saved before a
+// restart that no script has bound again.
+func (rr *ReactionRoles) Any() bool {
+	return len(rr.StateManager.Keys(statePrefix)) > 0
+}
+
+// HandleReactionAdd grants the bound role, if any, to the member who reacted.
+func (rr *ReactionRoles) HandleReactionAdd(s *discordgo.Session, r *discordgo.MessageReaction) {
+	roleID := rr.match(s, r)
+	if roleID == "" {
+		return
+	}
+
+	if err := s.GuildMemberRoleAdd(r.GuildID, r.UserID, roleID); err != nil {
+		slog.Error("Failed to grant reaction role", "guild_id", r.GuildID, "user_id", r.UserID, "role_id", roleID, "error", err)
+		return
+	}
+	slog.Info("Granted reaction role", "user_id", r.UserID, "role_id", roleID, "message_id", r.MessageID)
+}
+
+// HandleReactionRemove removes the bound role, if any, from the member who
+// took their reaction back.
+func (rr *ReactionRoles) HandleReactionRemove(s *discordgo.Session, r *discordgo.MessageReaction) {
+	roleID := rr.match(s, r)
+	if roleID == "" {
+		return
+	}
+
+	if err := s.GuildMemberRoleRemove(r.GuildID, r.UserID, roleID); err != nil {
+		slog.Error("Failed to remove reaction role", "guild_id", r.GuildID, "user_id", r.UserID, "role_id", roleID, "error", err)
+		return
+	}
+	slog.Info("Removed reaction role", "user_id", r.UserID, "role_id", roleID, "message_id", r.MessageID)
+}
+
+// match returns the role bound to a reaction event, ignoring the bot's own
+// reactions and reactions outside of guilds.
+func (rr *ReactionRoles) match(s *discordgo.Session, r *discordgo.MessageReaction) string {
+	if r.GuildID == "" || (s.State.User != nil && r.UserID == s.State.User.ID) {
+		return ""
+	}
+
+	emoji := r.Emoji.Name
+	if r.Emoji.ID != "" {
+		emoji = r.Emoji.ID
+	}
+	return rr.Lookup(r.MessageID, emoji)
+}
+
+// statePrefix starts the state key of every mapping.
+const statePrefix = "__reaction_role:"
+
+// stateKey builds the state key of a mapping. Custom emoji are keyed by ID.
+func stateKey(messageID, emoji string) string {
+	return fmt.Sprintf("%s%s:%s", statePrefix, messageID, normalizeEmoji(emoji))
+}
+
+// normalizeEmoji reduces the accepted emoji forms to how reaction events
+// identify them: the emoji itself for unicode emoji, or the ID for custom
+// emoji written as `<:name:id>`, `<a:name:id>` or `name:id`.
+func normalizeEmoji(emoji string) string {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(emoji, "<"), ">")
+	parts := strings.Split(trimmed, ":")
+	if len(parts) < 2 {
+		return emoji
+	}
+
+	id := parts[len(parts)-1]
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return emoji
+	}
+	return id
+}
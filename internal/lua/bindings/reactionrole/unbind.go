@@ -0,0 +1,61 @@
+package reactionrole
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ReactionRoleBindingUnbind provides Lua bindings for removing a reaction role.
+type ReactionRoleBindingUnbind struct {
+	Roles *ReactionRoles
+}
+
+// NewReactionRoleBindingUnbind initializes a new reaction role unbind instance.
+func NewReactionRoleBindingUnbind(roles *ReactionRoles) *ReactionRoleBindingUnbind {
+	slog.Debug("Creating new ReactionRoleBindingUnbind")
+	return &ReactionRoleBindingUnbind{
+		Roles: roles,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *ReactionRoleBindingUnbind) Name() string {
+	return "unbind"
+}
+
+func (b *ReactionRoleBindingUnbind) SetSession(session *discordgo.Session) {}
+
+// Register registers the reaction role functions in the Lua state. Roles
+// already granted through the mapping are left in place. In dry-run mode the
+// mapping is kept and a preview is returned alongside true.
+func (b *ReactionRoleBindingUnbind) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		messageID := L.CheckString(1)
+		emoji := L.CheckString(2)
+
+		if utils.DryRun() {
+			slog.Info("Dry run: would unbind reaction role", "message_id", messageID, "emoji", emoji)
+			L.Push(lua.LTrue)
+			L.Push(utils.PrepareDryRunPreview(L, "unbind_reaction_role", []string{messageID}))
+			return 2
+		}
+
+		b.Roles.Unbind(messageID, emoji)
+		slog.Info("Unbound reaction role", "message_id", messageID, "emoji", emoji)
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ReactionRoleBindingUnbind) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ReactionRoleBindingUnbind) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,56 @@
+package reactionrole
+
+import (
+	"driftwood/internal/lua/utils"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestUnbindReturns(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		bound       bool // Whether the emoji is bound before unbinding
+		wantPreview bool
+		wantRole    string // Role still bound afterwards
+	}{
+		{name: "bound", bound: true},
+		{name: "not bound"},
+		{name: "dry run", dryRun: true, bound: true, wantPreview: true, wantRole: "3"},
+		{name: "dry run not bound", dryRun: true, wantPreview: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utils.SetDryRun(tt.dryRun)
+			defer utils.SetDryRun(false)
+
+			roles := NewReactionRoles(utils.NewStateManager())
+			if tt.bound {
+				roles.Bind("1", "👍", "3")
+			}
+
+			L := lua.NewState()
+			defer L.Close()
+			L.SetGlobal("unbind", L.NewFunction(NewReactionRoleBindingUnbind(roles).Register()))
+			if err := L.DoString(`success, preview = unbind("1", "👍")`); err != nil {
+				t.Fatal(err)
+			}
+
+			if success := L.GetGlobal("success"); success != lua.LTrue {
+				t.Errorf("success = %v, want true", success)
+			}
+			preview, isPreview := L.GetGlobal("preview").(*lua.LTable)
+			if isPreview != tt.wantPreview {
+				t.Fatalf("preview = %v, want a preview: %v", L.GetGlobal("preview"), tt.wantPreview)
+			}
+			if isPreview && preview.RawGetString("action").String() != "unbind_reaction_role" {
+				t.Errorf("preview action = %v, want unbind_reaction_role", preview.RawGetString("action"))
+			}
+			if role := roles.Lookup("1", "👍"); role != tt.wantRole {
+				t.Errorf("bound role = %q, want %q", role, tt.wantRole)
+			}
+		})
+	}
+}
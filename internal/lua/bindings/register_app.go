@@ -4,6 +4,10 @@ import (
 	"driftwood/internal/lua/utils"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	lua "github.com/yuin/gopher-lua"
@@ -15,16 +19,65 @@ type ApplicationCommandBinding struct {
 	GuildID  string
 	Commands map[string]string // Maps command names to Lua global handler names
 
-	waitRegister []func(*discordgo.Session)
+	owners        map[string]string            // Maps command names to the top-level command that declared them
+	autocompletes map[string]commandHandler    // Maps `command/option` to the function suggesting its choices
+	suggestions   *autocompleteCache           // Choices recently suggested by autocomplete handlers
+	channelTypes  map[string]channelTypes      // Maps `command/option` to the channel types it accepts
+	requirements  map[string]optionRequirement // Maps `command/option` to the options that make it required
+	cooldownTimes map[string]time.Duration     // Maps top-level command names to the wait between uses by a user
+	guildOnly     map[string]bool              // Top-level commands refused outside of guilds
+	nextHandler   int                          // Numbers handler globals so they never collide
+	global        bool                         // Whether commands are registered globally instead of in the guild
+	everyGuild    bool                         // Whether commands are registered in every guild the bot is in
+	guilds        map[string]bool              // Guilds the bot is in, when registering in every guild
+	guildsMu      sync.Mutex                   // Guards guilds, which are announced on the gateway goroutines
+	holdSync      bool                         // Whether late registrations wait for FinishReload to sync
+	syncer        *commandSyncer               // Sends registrations to Discord off the Lua runner
+
+	middleware  *MiddlewareBinding              // Hooks run before every command handler
+	fallback    *UnknownCommandBinding          // Handler for commands without a registered handler
+	metrics     *utils.Metrics                  // Invocation counts and timings per command
+	audit       *utils.AuditLog                 // History of recent invocations
+	cooldowns   *utils.Cooldowns                // When each user can use a command again
+	state       *utils.StateManager             // Holds the commands disabled at runtime
+	definitions []*discordgo.ApplicationCommand // Every declared command, flushed in one bulk overwrite; only touched on the Lua runner
+}
+
+// commandHandler is a Lua function stored on behalf of a top-level command.
+type commandHandler struct {
+	globalName string        // Lua global holding the function
+	root       string        // Top-level command that declared it
+	cacheTTL   time.Duration // How long autocomplete suggestions are reused, 0 to always call it
+}
+
+// channelTypes is the restriction of a channel option declared by a top-level command.
+type channelTypes struct {
+	types []discordgo.ChannelType
+	root  string // Top-level command that declared it
 }
 
 // NewApplicationCommandBinding initializes a new ApplicationCommandBinding.
-func NewApplicationCommandBinding(guildID string) *ApplicationCommandBinding {
+func NewApplicationCommandBinding(guildID string, middleware *MiddlewareBinding, fallback *UnknownCommandBinding, metrics *utils.Metrics, audit *utils.AuditLog, cooldowns *utils.Cooldowns, state *utils.StateManager) *ApplicationCommandBinding {
 	slog.Debug("Creating new ApplicationCommandBinding")
 	return &ApplicationCommandBinding{
-		GuildID:      guildID,
-		Commands:     make(map[string]string),
-		waitRegister: []func(*discordgo.Session){},
+		GuildID:       guildID,
+		Commands:      make(map[string]string),
+		owners:        make(map[string]string),
+		autocompletes: make(map[string]commandHandler),
+		suggestions:   newAutocompleteCache(),
+		guilds:        make(map[string]bool),
+		channelTypes:  make(map[string]channelTypes),
+		requirements:  make(map[string]optionRequirement),
+		cooldownTimes: make(map[string]time.Duration),
+		guildOnly:     make(map[string]bool),
+		syncer:        newCommandSyncer(),
+		middleware:    middleware,
+		fallback:      fallback,
+		metrics:       metrics,
+		audit:         audit,
+		cooldowns:     cooldowns,
+		state:         state,
+		definitions:   []*discordgo.ApplicationCommand{},
 	}
 }
 
@@ -37,94 +90,450 @@ func (b *ApplicationCommandBinding) SetSession(session *discordgo.Session) {
 	slog.Info("Setting session for ApplicationCommandBinding")
 	b.Session = session
 
-	for _, f := range b.waitRegister {
-		f(session)
+	// The definitions are declared by the scripts on the Lua runner, so the
+	// flush runs there too: after every script queued before it, and never
+	// while a script is still adding commands
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		if b.global {
+			b.clearGuildCommands(session)
+		}
+		b.syncCommands(session)
+	})
+}
+
+// clearGuildCommands removes commands left in the guild from registering
+// there during development, which would otherwise be listed alongside their
+// global counterparts.
+func (b *ApplicationCommandBinding) clearGuildCommands(session *discordgo.Session) {
+	appID := session.State.User.ID
+	b.syncer.submit(b.GuildID, func() {
+		b.removeCommands(session, appID, b.GuildID)
+	})
+}
+
+// removeCommands removes every command registered in a guild.
+func (b *ApplicationCommandBinding) removeCommands(session *discordgo.Session, appID, guildID string) {
+	var existing []*discordgo.ApplicationCommand
+	err := retryTransient("fetch", func() (err error) {
+		existing, err = session.ApplicationCommands(appID, guildID)
+		return err
+	})
+	if err != nil {
+		slog.Warn("Failed to fetch guild commands", "guild_id", guildID, "error", err)
+		return
+	}
+	if len(existing) == 0 {
+		return
+	}
+
+	err = retryTransient("overwrite", func() error {
+		_, err := session.ApplicationCommandBulkOverwrite(appID, guildID, []*discordgo.ApplicationCommand{})
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to remove guild commands", "guild_id", guildID, "error", err)
+		return
+	}
+
+	slog.Info("Removed guild commands in favour of global commands", "guild_id", guildID, "removed", len(existing))
+}
+
+// SetGlobal chooses where commands are registered. Guild commands update
+// instantly, which suits a test guild during development, while global
+// commands are available in every guild but can take up to an hour to
+// propagate after a change.
+func (b *ApplicationCommandBinding) SetGlobal(global bool) {
+	b.global = global
+}
+
+// SetEveryGuild registers commands in every guild the bot is in, rather than
+// in the configured guild only. Guild commands update instantly, and guilds
+// the bot joins get the commands as soon as Discord announces them.
+func (b *ApplicationCommandBinding) SetEveryGuild(everyGuild bool) {
+	b.everyGuild = everyGuild
+}
+
+// RequiredIntents asks for guild events when registering in every guild,
+// which announce the guilds the commands are registered in.
+func (b *ApplicationCommandBinding) RequiredIntents() discordgo.Intent {
+	if b.everyGuild {
+		return discordgo.IntentsGuilds
+	}
+	return discordgo.IntentsNone
+}
+
+// AddGuild registers the commands in a guild the bot is in, when registering
+// in every guild. Discord announces every guild after connecting and each
+// guild the bot joins later, so guilds already known are skipped.
+func (b *ApplicationCommandBinding) AddGuild(guildID string) {
+	if !b.everyGuild {
+		return
+	}
+	b.guildsMu.Lock()
+	known := b.guilds[guildID]
+	b.guilds[guildID] = true
+	b.guildsMu.Unlock()
+	if known {
+		return
+	}
+
+	slog.Info("Registering commands in guild", "guild_id", guildID)
+	if b.Session == nil || b.holdSync {
+		return // Synced along with the other guilds once ready
+	}
+	b.syncCommandsIn(b.Session, guildID)
+}
+
+// RemoveGuild stops syncing commands to a guild the bot has left.
+func (b *ApplicationCommandBinding) RemoveGuild(guildID string) {
+	b.guildsMu.Lock()
+	defer b.guildsMu.Unlock()
+	delete(b.guilds, guildID)
+}
+
+// registrationGuildID returns the guild commands are registered in, empty
+// when they are registered globally.
+func (b *ApplicationCommandBinding) registrationGuildID() string {
+	if b.global {
+		return ""
+	}
+	return b.GuildID
+}
+
+// syncCommands submits the declared commands wherever they are registered:
+// globally, in the configured guild, or in every guild the bot is in.
+func (b *ApplicationCommandBinding) syncCommands(session *discordgo.Session) {
+	if !b.everyGuild {
+		b.syncCommandsIn(session, b.registrationGuildID())
+		return
+	}
+
+	b.guildsMu.Lock()
+	guildIDs := make([]string, 0, len(b.guilds))
+	for guildID := range b.guilds {
+		guildIDs = append(guildIDs, guildID)
+	}
+	b.guildsMu.Unlock()
+
+	for _, guildID := range guildIDs {
+		b.syncCommandsIn(session, guildID)
+	}
+}
+
+// syncCommandsIn submits every declared command to Discord in a single bulk
+// overwrite, which also removes stale commands that are no longer declared.
+// An empty guild ID registers them globally. The requests are sent in the
+// background with the commands declared now; they are replaced rather than
+// changed when declared again, so a copy of the list is enough.
+func (b *ApplicationCommandBinding) syncCommandsIn(session *discordgo.Session, guildID string) {
+	appID := session.State.User.ID
+	definitions := slices.Clone(b.definitions)
+	b.syncer.submit(guildID, func() {
+		b.submitCommands(session, appID, guildID, definitions)
+	})
+}
+
+// submitCommands overwrites the commands registered in a guild. The current
+// registration is fetched first so the differences can be logged and the
+// overwrite skipped entirely when nothing changed. Both requests are retried
+// with backoff on transient errors, such as Discord being briefly unavailable
+// during a deploy.
+func (b *ApplicationCommandBinding) submitCommands(session *discordgo.Session, appID, guildID string, definitions []*discordgo.ApplicationCommand) {
+	var existing []*discordgo.ApplicationCommand
+	err := retryTransient("fetch", func() (err error) {
+		existing, err = session.ApplicationCommands(appID, guildID)
+		return err
+	})
+	if err != nil {
+		slog.Warn("Failed to fetch existing commands, overwriting all", "error", err)
+		existing = nil
+	}
+
+	registered := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, cmd := range existing {
+		registered[cmd.Name] = cmd
+	}
+
+	changed := err != nil
+	created, updated, unchanged := 0, 0, 0
+	for _, appCmd := range definitions {
+		current, exists := registered[appCmd.Name]
+		delete(registered, appCmd.Name)
+		if !exists {
+			slog.Info("Command created", "name", appCmd.Name)
+			created++
+			changed = true
+			continue
+		}
+
+		if diffs := commandDiff(current, appCmd); len(diffs) > 0 {
+			slog.Info("Command updated", "name", appCmd.Name, "diff", diffs)
+			updated++
+			changed = true
+			continue
+		}
+
+		slog.Debug("Command unchanged", "name", appCmd.Name)
+		unchanged++
+	}
+
+	for name := range registered {
+		slog.Info("Command removed", "name", name)
+		changed = true
+	}
+
+	if !changed {
+		slog.Info("Commands already up to date", "guild_id", guildID, "unchanged", unchanged)
+		return
+	}
+
+	err = retryTransient("overwrite", func() error {
+		_, err := session.ApplicationCommandBulkOverwrite(appID, guildID, definitions)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to register commands with Discord", "guild_id", guildID, "count", len(definitions), "transient", isTransientError(err), "error", err)
+		return
+	}
+
+	slog.Info("Commands synchronised", "global", b.global, "guild_id", guildID, "created", created, "updated", updated, "removed", len(registered), "unchanged", unchanged)
+}
+
+// Reset removes every declared command and its handlers ahead of reloading
+// the scripts. Nothing is synced with Discord until FinishReload, so commands
+// the scripts declare again are never removed in between.
+func (b *ApplicationCommandBinding) Reset(L *lua.LState) {
+	roots := make(map[string]bool)
+	for _, root := range b.owners {
+		roots[root] = true
+	}
+	for _, autocomplete := range b.autocompletes {
+		roots[autocomplete.root] = true
+	}
+	for root := range roots {
+		b.releaseHandlers(L, root)
+	}
+
+	b.definitions = []*discordgo.ApplicationCommand{}
+	b.cooldownTimes = make(map[string]time.Duration)
+	b.guildOnly = make(map[string]bool)
+	b.holdSync = true
+}
+
+// FinishReload syncs the commands declared by the reloaded scripts, removing
+// the commands of scripts that no longer declare them.
+func (b *ApplicationCommandBinding) FinishReload() {
+	b.holdSync = false
+	if b.Session != nil {
+		b.syncCommands(b.Session)
 	}
 }
 
+// addDefinition buffers a command definition, replacing any earlier
+// definition with the same name.
+func (b *ApplicationCommandBinding) addDefinition(appCmd *discordgo.ApplicationCommand) {
+	for idx, cmd := range b.definitions {
+		if cmd.Name == appCmd.Name {
+			b.definitions[idx] = appCmd
+			return
+		}
+	}
+	b.definitions = append(b.definitions, appCmd)
+}
+
 // Register adds the `register_application_command` function to a Lua table.
 func (b *ApplicationCommandBinding) Register() lua.LGFunction {
 	slog.Info("Registering application command Lua function")
 	return func(L *lua.LState) int {
-		command := L.CheckTable(1)
+		b.registerCommand(L, L.CheckTable(1))
+		return 0
+	}
+}
 
-		// Validate required fields
-		name := command.RawGetString("name")
-		if name.Type() != lua.LTString {
-			L.ArgError(1, "'name' must be a string")
-		}
+// registerCommand declares a command from its Lua table and, once the
+// session is ready, syncs it with Discord.
+func (b *ApplicationCommandBinding) registerCommand(L *lua.LState, command *lua.LTable) {
+	// Check the whole table up front, so every mistake is reported at once
+	name := command.RawGetString("name")
+	if problems := validateCommandTable(command); len(problems) > 0 {
+		L.ArgError(1, formatCommandProblems(name, problems))
+		return
+	}
 
-		description := command.RawGetString("description")
-		if description.Type() != lua.LTString {
-			L.ArgError(1, "'description' must be a string")
-		}
+	description := command.RawGetString("description")
+	handler := command.RawGetString("handler")
+	options := command.RawGetString("options")
+	cooldown := command.RawGetString("cooldown")
+
+	if cooldown != lua.LNil {
+		b.cooldownTimes[name.String()] = time.Duration(float64(cooldown.(lua.LNumber)) * float64(time.Second))
+	} else {
+		delete(b.cooldownTimes, name.String())
+	}
+	if lua.LVAsBool(command.RawGetString("guild_only")) {
+		b.guildOnly[name.String()] = true
+	} else {
+		delete(b.guildOnly, name.String())
+	}
+
+	// Registering a command again replaces all of its handlers
+	b.releaseHandlers(L, name.String())
+	if handler != lua.LNil {
+		b.bindHandler(L, name.String(), name.String(), handler)
+	}
+
+	commandOptions := []*discordgo.ApplicationCommandOption{}
+	if options != lua.LNil {
+		commandOptions = b.parseOptions(L, name.String(), name.String(), options.(*lua.LTable))
+	}
 
-		handler := command.RawGetString("handler")
-		if handler != lua.LNil && handler.Type() != lua.LTFunction {
-			L.ArgError(1, "'handler' must be a function if provided")
+	appCmd := &discordgo.ApplicationCommand{
+		Name:        name.String(),
+		Description: description.String(),
+		Options:     commandOptions,
+	}
+
+	b.addDefinition(appCmd)
+
+	// Commands declared before the session is ready are flushed together
+	// in SetSession, and commands declared while reloading in FinishReload.
+	// Late registrations resubmit the whole set so that the bulk overwrite
+	// does not drop the other commands.
+	if b.Session == nil || b.holdSync {
+		return
+	}
+
+	b.syncCommands(b.Session)
+}
+
+// bindHandler stores a handler under a numbered global and routes the command
+// name to it. Subcommands are named `command_subcommand`, so a top-level
+// command can spell the same name as another command's subcommand; that is
+// raised as an error rather than letting one silently replace the other.
+func (b *ApplicationCommandBinding) bindHandler(L *lua.LState, name, root string, handler lua.LValue) {
+	if owner, exists := b.owners[name]; exists && owner != root {
+		L.ArgError(1, fmt.Sprintf("handler '%s' of command '%s' collides with a handler of command '%s'", name, root, owner))
+		return
+	}
+
+	globalName := fmt.Sprintf("command_handler_%d", b.nextHandler)
+	b.nextHandler++
+	L.SetGlobal(globalName, handler)
+	b.Commands[name] = globalName
+	b.owners[name] = root
+}
+
+// bindAutocomplete marks an option as autocompleted and stores the function
+// that suggests its choices, keyed by the command and option names.
+func (b *ApplicationCommandBinding) bindAutocomplete(L *lua.LState, root, commandName string, option *discordgo.ApplicationCommandOption, handler lua.LValue, cache lua.LValue) {
+	var cacheTTL time.Duration
+	if seconds, ok := cache.(lua.LNumber); ok {
+		cacheTTL = time.Duration(float64(seconds) * float64(time.Second))
+	}
+
+	globalName := fmt.Sprintf("autocomplete_handler_%d", b.nextHandler)
+	b.nextHandler++
+	L.SetGlobal(globalName, handler)
+	key := commandName + "/" + option.Name
+	b.suggestions.clear(key)
+	b.autocompletes[key] = commandHandler{globalName: globalName, root: root, cacheTTL: cacheTTL}
+	option.Autocomplete = true
+}
+
+// bindChannelTypes restricts a channel option to the named channel types,
+// such as "text" or "forum", both in Discord's picker and when the command
+// is handled.
+func (b *ApplicationCommandBinding) bindChannelTypes(root, commandName string, option *discordgo.ApplicationCommandOption, names *lua.LTable) {
+	var types []discordgo.ChannelType
+	for i := 1; i <= names.Len(); i++ {
+		channelType, _ := channelTypeByName(names.RawGetInt(i).String())
+		types = append(types, channelType)
+	}
+
+	option.ChannelTypes = types
+	b.channelTypes[commandName+"/"+option.Name] = channelTypes{types: types, root: root}
+}
+
+// channelTypeByName looks up a channel type by the name exposed to Lua.
+func channelTypeByName(name string) (discordgo.ChannelType, bool) {
+	for channelType, typeName := range utils.ChannelTypeNames {
+		if typeName == name {
+			return channelType, true
 		}
+	}
+	return 0, false
+}
 
-		options := command.RawGetString("options")
-		if options != lua.LNil && options.Type() != lua.LTTable {
-			L.ArgError(1, "'options' must be a table if provided")
+// checkChannelTypes verifies the channels chosen for restricted options are
+// of an accepted type, in case a client lets another type through. Returns a
+// message for the user when one isn't, or an empty string.
+func (b *ApplicationCommandBinding) checkChannelTypes(commandName string, options []*discordgo.ApplicationCommandInteractionDataOption, resolved *discordgo.ApplicationCommandInteractionDataResolved) string {
+	for _, opt := range options {
+		restriction, exists := b.channelTypes[commandName+"/"+opt.Name]
+		if !exists || opt.Type != discordgo.ApplicationCommandOptionChannel || resolved == nil {
+			continue
 		}
 
-		globalName := fmt.Sprintf("handler_%s", name)
-		if handler != lua.LNil {
-			L.SetGlobal(globalName, handler)
-			b.Commands[name.String()] = globalName
+		id, _ := opt.Value.(string)
+		channel, ok := resolved.Channels[id]
+		if !ok || channel == nil || slices.Contains(restriction.types, channel.Type) {
+			continue
 		}
 
-		commandOptions := []*discordgo.ApplicationCommandOption{}
-		if options != lua.LNil {
-			commandOptions = b.parseOptions(L, name.String(), options.(*lua.LTable))
+		names := make([]string, 0, len(restriction.types))
+		for _, channelType := range restriction.types {
+			names = append(names, utils.ChannelTypeNames[channelType])
 		}
+		return fmt.Sprintf("<#%s> can't be used for `%s`, pick a %s channel.", id, opt.Name, strings.Join(names, " or "))
+	}
+	return ""
+}
 
-		appCmd := &discordgo.ApplicationCommand{
-			Name:        name.String(),
-			Description: description.String(),
-			Options:     commandOptions,
+// releaseHandlers removes the handlers, autocomplete handlers, channel type
+// restrictions and option requirements declared by a top-level command.
+func (b *ApplicationCommandBinding) releaseHandlers(L *lua.LState, root string) {
+	for name, owner := range b.owners {
+		if owner != root {
+			continue
 		}
+		L.SetGlobal(b.Commands[name], lua.LNil)
+		delete(b.Commands, name)
+		delete(b.owners, name)
+	}
 
-		if b.Session == nil {
-			b.waitRegister = append(b.waitRegister, func(session *discordgo.Session) {
-				if _, err := session.ApplicationCommandCreate(session.State.User.ID, b.GuildID, appCmd); err != nil {
-					L.RaiseError("failed to register command '%s' with Discord: %s", name, err.Error())
-				}
-			})
-			return 0
+	for key, autocomplete := range b.autocompletes {
+		if autocomplete.root != root {
+			continue
 		}
+		L.SetGlobal(autocomplete.globalName, lua.LNil)
+		delete(b.autocompletes, key)
+		b.suggestions.clear(key)
+	}
 
-		if _, err := b.Session.ApplicationCommandCreate(b.Session.State.User.ID, b.GuildID, appCmd); err != nil {
-			L.RaiseError("failed to register command '%s' with Discord: %s", name, err.Error())
+	for key, restriction := range b.channelTypes {
+		if restriction.root == root {
+			delete(b.channelTypes, key)
 		}
+	}
 
-		slog.Info("Registered command successfully", "name", name, "description", description)
-		return 0
+	for key, requirement := range b.requirements {
+		if requirement.root == root {
+			delete(b.requirements, key)
+		}
 	}
 }
 
 // parseOptions parses Lua options tables recursively to support subcommands.
-func (b *ApplicationCommandBinding) parseOptions(L *lua.LState, parentName string, options *lua.LTable) []*discordgo.ApplicationCommandOption {
+// Handlers of the subcommands are bound on behalf of the root command.
+func (b *ApplicationCommandBinding) parseOptions(L *lua.LState, root, parentName string, options *lua.LTable) []*discordgo.ApplicationCommandOption {
 	var commandOptions []*discordgo.ApplicationCommandOption
 
 	options.ForEach(func(_, value lua.LValue) {
 		if optTable, ok := value.(*lua.LTable); ok {
-			// Validate option fields
+			// The fields were checked by validateCommandTable
 			name := optTable.RawGetString("name")
-			if name.Type() != lua.LTString {
-				L.ArgError(1, "'name' in options must be a string")
-			}
-
 			description := optTable.RawGetString("description")
-			if description.Type() != lua.LTString {
-				L.ArgError(1, "'description' in options must be a string")
-			}
-
 			typeField := optTable.RawGetString("type")
-			if typeField.Type() != lua.LTNumber {
-				L.ArgError(1, "'type' in options must be a number")
-			}
 
 			option := &discordgo.ApplicationCommandOption{
 				Name:        name.String(),
@@ -133,19 +542,27 @@ func (b *ApplicationCommandBinding) parseOptions(L *lua.LState, parentName strin
 				Required:    lua.LVAsBool((optTable.RawGetString("required"))),
 			}
 
-			if option.Type == discordgo.ApplicationCommandOptionSubCommand {
-				handler := optTable.RawGetString("handler")
-				if handler.Type() != lua.LTFunction {
-					L.ArgError(1, "Subcommand '%s' must have a 'handler' function")
-					return
-				}
+			if choices, ok := optTable.RawGetString("choices").(*lua.LTable); ok {
+				option.Choices = b.parseChoices(option, choices)
+			}
+
+			if types, ok := optTable.RawGetString("channel_types").(*lua.LTable); ok {
+				b.bindChannelTypes(root, parentName, option, types)
+			}
+
+			if autocomplete := optTable.RawGetString("autocomplete"); autocomplete != lua.LNil {
+				b.bindAutocomplete(L, root, parentName, option, autocomplete, optTable.RawGetString("autocomplete_cache"))
+			}
+
+			if requiredIf := optTable.RawGetString("required_if"); requiredIf != lua.LNil {
+				b.bindRequirement(L, root, parentName, option, requiredIf)
+			}
 
-				handlerName := fmt.Sprintf("handler_%s_%s", parentName, option.Name)
-				L.SetGlobal(handlerName, handler)
-				b.Commands[parentName+"_"+option.Name] = handlerName
+			if option.Type == discordgo.ApplicationCommandOptionSubCommand {
+				b.bindHandler(L, parentName+"_"+option.Name, root, optTable.RawGetString("handler"))
 
 				if subOptions := optTable.RawGetString("options"); subOptions.Type() == lua.LTTable {
-					option.Options = b.parseOptions(L, parentName+"_"+option.Name, subOptions.(*lua.LTable))
+					option.Options = b.parseOptions(L, root, parentName+"_"+option.Name, subOptions.(*lua.LTable))
 				}
 			}
 
@@ -154,89 +571,426 @@ func (b *ApplicationCommandBinding) parseOptions(L *lua.LState, parentName strin
 		}
 	})
 
+	b.checkRequirementTargets(L, parentName, commandOptions)
 	return commandOptions
 }
 
+// parseChoices parses the predefined choices of an option. Each choice is a
+// table with a "name", a "value" matching the option type, and optional
+// "name_localizations" keyed by Discord locale code.
+func (b *ApplicationCommandBinding) parseChoices(option *discordgo.ApplicationCommandOption, choices *lua.LTable) []*discordgo.ApplicationCommandOptionChoice {
+	var parsed []*discordgo.ApplicationCommandOptionChoice
+
+	// The choices were checked by validateCommandTable
+	choices.ForEach(func(_, value lua.LValue) {
+		choiceTable := value.(*lua.LTable)
+		choice := &discordgo.ApplicationCommandOptionChoice{Name: choiceTable.RawGetString("name").String()}
+
+		rawValue := choiceTable.RawGetString("value")
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionString:
+			choice.Value = rawValue.String()
+		case discordgo.ApplicationCommandOptionInteger:
+			choice.Value = int64(rawValue.(lua.LNumber))
+		case discordgo.ApplicationCommandOptionNumber:
+			choice.Value = float64(rawValue.(lua.LNumber))
+		}
+
+		if localizations, ok := choiceTable.RawGetString("name_localizations").(*lua.LTable); ok {
+			choice.NameLocalizations = b.parseLocalizations(choice.Name, localizations)
+		}
+
+		parsed = append(parsed, choice)
+	})
+
+	return parsed
+}
+
+// parseLocalizations converts a table of locale codes to names. Unknown
+// locale codes are skipped with a warning, so Discord falls back to the base
+// name for those users.
+func (b *ApplicationCommandBinding) parseLocalizations(baseName string, table *lua.LTable) map[discordgo.Locale]string {
+	localizations := make(map[discordgo.Locale]string)
+
+	table.ForEach(func(key, value lua.LValue) {
+		locale := discordgo.Locale(key.String())
+		if _, known := discordgo.Locales[locale]; !known || locale == discordgo.Unknown {
+			slog.Warn("Ignoring unknown locale", "name", baseName, "locale", key.String())
+			return
+		}
+		if value.Type() != lua.LTString || value.String() == "" {
+			slog.Warn("Ignoring empty localization", "name", baseName, "locale", key.String())
+			return
+		}
+		localizations[locale] = value.String()
+	})
+
+	if len(localizations) == 0 {
+		return nil
+	}
+	return localizations
+}
+
 func (b *ApplicationCommandBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
-	return interaction.Type == discordgo.InteractionApplicationCommand
+	return interaction.Type == discordgo.InteractionApplicationCommand ||
+		interaction.Type == discordgo.InteractionApplicationCommandAutocomplete
+}
+
+// invokedCommandName returns the name of the invoked command, with the
+// subcommand as `command_subcommand`, and the options passed to it.
+func invokedCommandName(data discordgo.ApplicationCommandInteractionData) (string, []*discordgo.ApplicationCommandInteractionDataOption) {
+	for _, opt := range data.Options {
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
+			return data.Name + "_" + opt.Name, opt.Options
+		}
+	}
+	return data.Name, data.Options
 }
 
 // HandleInteraction executes the Lua handler for a command or subcommand.
 func (b *ApplicationCommandBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	if interaction.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		return b.handleAutocomplete(interaction)
+	}
+
 	slog.Info("Handling command interaction", "interaction_id", interaction.ID)
 	data := interaction.ApplicationCommandData()
-	commandName := data.Name
+	commandName, options := invokedCommandName(data)
 
-	for _, opt := range data.Options {
-		if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
-			commandName += "_" + opt.Name
-			break
+	// The commands and their checks are read on the Lua runner, where the
+	// scripts declare them, so a reload never changes them mid-lookup
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		b.runCommand(L, interaction, data, commandName, options)
+	})
+
+	return nil
+}
+
+// runCommand checks a command may run and calls its Lua handler. It must be
+// called on the Lua runner.
+func (b *ApplicationCommandBinding) runCommand(L *lua.LState, interaction *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, commandName string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	globalName, exists := b.Commands[commandName]
+	if !exists {
+		if b.fallback.Handler == "" {
+			slog.Warn("Command not registered", "command", commandName)
+			b.respondEphemeral(interaction, b.fallback.Message)
+			return
 		}
+		slog.Debug("Routing unregistered command to fallback handler", "command", commandName)
+		globalName = b.fallback.Handler
 	}
 
-	globalName, exists := b.Commands[commandName]
+	if exists && (b.Disabled(data.Name) || b.Disabled(commandName)) {
+		slog.Info("Command is disabled", "command", commandName)
+		b.respondEphemeral(interaction, DisabledCommandMessage)
+		return
+	}
+
+	if message := b.checkChannelTypes(commandName, options, data.Resolved); message != "" {
+		slog.Info("Command used with a channel of the wrong type", "command", commandName)
+		b.respondEphemeral(interaction, message)
+		return
+	}
+
+	if message := b.checkRequirements(commandName, options); message != "" {
+		slog.Info("Command used without a conditionally required option", "command", commandName)
+		b.respondEphemeral(interaction, message)
+		return
+	}
+
+	// Handlers of guild only commands can rely on a guild and member, even
+	// when Discord lets a DM invocation through
+	if exists && b.guildOnly[data.Name] && interaction.GuildID == "" {
+		slog.Info("Guild only command used outside of a guild", "command", commandName)
+		b.respondEphemeral(interaction, GuildOnlyMessage)
+		return
+	}
+
+	if exists && b.onCooldown(interaction, data.Name) {
+		return
+	}
+
+	slog.Debug("Executing Lua handler", "handler_name", globalName)
+	fn := L.GetGlobal(globalName)
+	if fn == lua.LNil {
+		slog.Error("Lua handler not implemented", "command", commandName)
+		return
+	}
+
+	interactionTable, state := b.prepareInteractionTable(L, interaction)
+	interactionTable.RawSetString("command", lua.LString(commandName))
+
+	var err error
+	returned := lua.LValue(lua.LNil)
+	started := time.Now()
+	utils.GetLuaRunner().WithInteraction(interaction, func() {
+		err = L.CallByParam(lua.P{
+			Fn:      b.middleware.Wrap(L, fn, interactionTable, &returned),
+			NRet:    0,
+			Protect: true,
+		})
+	})
+	if exists {
+		b.metrics.RecordCommand(commandName, time.Since(started), err != nil)
+		b.audit.Record(b.Session, utils.AuditEntry{
+			Command:   commandName,
+			UserID:    utils.InteractionUserID(interaction),
+			ChannelID: interaction.ChannelID,
+			At:        started,
+			Success:   err == nil,
+		})
+	}
+	if err == nil && returned != lua.LNil {
+		utils.ReplyWithReturned(L, interactionTable, returned, commandName)
+	}
+	utils.EnsureResponded(b.Session, interaction, state, commandName)
+	utils.ReleaseInteraction(interactionTable, state)
+	if err != nil {
+		slog.Error("Error executing Lua command handler", "error", err, "command", commandName)
+		return
+	}
+	slog.Info("Command handled successfully", "command", commandName)
+}
+
+// CooldownMessage is the reply to a command used again before its cooldown
+// expired, formatted with a relative timestamp of when it can be used.
+const CooldownMessage = "This command is on cooldown, try again %s."
+
+// onCooldown reports whether the user is still waiting out the cooldown of a
+// top-level command, letting them know when they can use it again. When the
+// command is ready the cooldown starts over, as the command is about to run.
+func (b *ApplicationCommandBinding) onCooldown(interaction *discordgo.InteractionCreate, rootName string) bool {
+	cooldown, exists := b.cooldownTimes[rootName]
 	if !exists {
-		slog.Warn("Command not registered", "command", commandName)
-		return fmt.Errorf("command '%s' not registered", commandName)
+		return false
+	}
+
+	userID := utils.InteractionUserID(interaction)
+	remaining := b.cooldowns.Remaining(rootName, userID)
+	if remaining <= 0 {
+		b.cooldowns.Start(rootName, userID, cooldown)
+		return false
+	}
+
+	slog.Info("Command is on cooldown", "command", rootName, "user_id", userID, "remaining", remaining)
+	readyAt := time.Now().Add(remaining)
+	b.respondEphemeral(interaction, fmt.Sprintf(CooldownMessage, fmt.Sprintf("<t:%d:R>", readyAt.Unix())))
+	return true
+}
+
+// handleAutocomplete calls the autocomplete handler of the focused option
+// and responds with the choices it returns. Disabled commands and options
+// without a handler get no suggestions.
+func (b *ApplicationCommandBinding) handleAutocomplete(interaction *discordgo.InteractionCreate) error {
+	data := interaction.ApplicationCommandData()
+	commandName, options := invokedCommandName(data)
+
+	var focused *discordgo.ApplicationCommandInteractionDataOption
+	for _, opt := range options {
+		if opt.Focused {
+			focused = opt
+			break
+		}
+	}
+	if focused == nil {
+		return fmt.Errorf("autocomplete for '%s' has no focused option", commandName)
+	}
+
+	optionKey := commandName + "/" + focused.Name
+	if b.Disabled(data.Name) || b.Disabled(commandName) {
+		slog.Debug("No autocomplete for option", "command", commandName, "option", focused.Name)
+		b.respondAutocomplete(interaction, commandName, []*discordgo.ApplicationCommandOptionChoice{})
+		return nil
+	}
+
+	// Only options with a cache TTL store choices, and reloading clears them,
+	// so a hit can be answered without waiting on the runner
+	input := fmt.Sprint(focused.Value)
+	if choices, ok := b.suggestions.get(optionKey, input); ok {
+		slog.Debug("Using cached autocomplete choices", "command", commandName, "option", focused.Name)
+		b.respondAutocomplete(interaction, commandName, choices)
+		return nil
 	}
 
 	utils.GetLuaRunner().Do(func(L *lua.LState) {
-		slog.Debug("Executing Lua handler", "handler_name", globalName)
-		fn := L.GetGlobal(globalName)
-		if fn == lua.LNil {
-			slog.Error("Lua handler not implemented", "command", commandName)
+		autocomplete, exists := b.autocompletes[optionKey]
+		if !exists {
+			slog.Debug("No autocomplete for option", "command", commandName, "option", focused.Name)
+			b.respondAutocomplete(interaction, commandName, []*discordgo.ApplicationCommandOptionChoice{})
 			return
 		}
 
-		interactionTable := b.prepareInteractionTable(L, interaction)
-
-		err := L.CallByParam(lua.P{
-			Fn:      fn,
-			NRet:    0,
-			Protect: true,
-		}, interactionTable)
+		autocompleteTable := L.NewTable()
+		autocompleteTable.RawSetString("command", lua.LString(commandName))
+		autocompleteTable.RawSetString("focused", lua.LString(focused.Name))
+		autocompleteTable.RawSetString("value", lua.LString(input))
+		optionsTable, argsTable := b.buildOptionsTable(L, nil, nil, data.Options, data.Resolved)
+		autocompleteTable.RawSetString("options", optionsTable)
+		autocompleteTable.RawSetString("args", argsTable)
+		autocompleteTable.RawSetString("channel_id", lua.LString(interaction.ChannelID))
+		autocompleteTable.RawSetString("user_id", lua.LString(utils.InteractionUserID(interaction)))
+
+		var err error
+		utils.GetLuaRunner().WithInteraction(interaction, func() {
+			err = L.CallByParam(lua.P{
+				Fn:      L.GetGlobal(autocomplete.globalName),
+				NRet:    1,
+				Protect: true,
+			}, autocompleteTable)
+		})
 		if err != nil {
-			slog.Error("Error executing Lua command handler", "error", err, "command", commandName)
+			slog.Error("Error executing Lua autocomplete handler", "command", commandName, "option", focused.Name, "error", err)
+			b.respondAutocomplete(interaction, commandName, []*discordgo.ApplicationCommandOptionChoice{})
 			return
 		}
-		slog.Info("Command handled successfully", "command", commandName)
+
+		result := L.Get(-1)
+		L.Pop(1)
+		choices := utils.ParseAutocompleteChoices(commandName, focused.Type, result)
+		if autocomplete.cacheTTL > 0 {
+			b.suggestions.set(optionKey, input, choices, autocomplete.cacheTTL)
+		}
+		b.respondAutocomplete(interaction, commandName, choices)
 	})
 
 	return nil
 }
 
+// respondEphemeral answers an interaction with a message only its user can
+// see, such as a check refusing to run the command.
+func (b *ApplicationCommandBinding) respondEphemeral(interaction *discordgo.InteractionCreate, content string) {
+	if err := b.Session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		slog.Error("Failed to respond to interaction", "interaction_id", interaction.ID, "content", content, "error", err)
+	}
+}
+
+// respondAutocomplete sends the suggested choices for an autocomplete interaction.
+func (b *ApplicationCommandBinding) respondAutocomplete(interaction *discordgo.InteractionCreate, commandName string, choices []*discordgo.ApplicationCommandOptionChoice) {
+	if err := b.Session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	}); err != nil {
+		slog.Error("Failed to respond to autocomplete", "command", commandName, "error", err)
+	}
+}
+
+// GuildOnlyMessage is the reply to a guild only command used in a DM.
+const GuildOnlyMessage = "This command can only be used in a server."
+
+// DisabledCommandMessage is the reply to a command that is disabled.
+const DisabledCommandMessage = "This command is disabled."
+
+// SetEnabled enables or disables the handler of a registered command, or of
+// a subcommand given as `command_subcommand`. The setting is kept in the
+// state, so it survives restarts when state persistence is enabled.
+func (b *ApplicationCommandBinding) SetEnabled(name string, enabled bool) error {
+	if _, exists := b.Commands[name]; !exists && !b.isGroup(name) {
+		return fmt.Errorf("command '%s' not registered", name)
+	}
+
+	key := fmt.Sprintf("__command_disabled:%s", name)
+	if enabled {
+		b.state.Clear(key)
+	} else {
+		b.state.Set(key, lua.LTrue, 0)
+	}
+	return nil
+}
+
+// Disabled reports whether a command or subcommand was disabled with SetEnabled.
+func (b *ApplicationCommandBinding) Disabled(name string) bool {
+	return lua.LVAsBool(b.state.Get(fmt.Sprintf("__command_disabled:%s", name)))
+}
+
+// isGroup reports whether name is a declared command, such as one made of
+// subcommands that has no handler of its own.
+func (b *ApplicationCommandBinding) isGroup(name string) bool {
+	for _, cmd := range b.definitions {
+		if cmd.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // prepareInteractionTable prepares a Lua table containing interaction details.
-func (b *ApplicationCommandBinding) prepareInteractionTable(L *lua.LState, interaction *discordgo.InteractionCreate) *lua.LTable {
-	interactionTable := utils.PrepareInteractionTable(L, b.Session, interaction)
-	interactionTable.RawSetString("options", b.buildOptionsTable(L, nil, interaction.ApplicationCommandData().Options))
-	return interactionTable
+func (b *ApplicationCommandBinding) prepareInteractionTable(L *lua.LState, interaction *discordgo.InteractionCreate) (*lua.LTable, *utils.ResponseState) {
+	interactionTable, state := utils.PrepareInteractionTable(L, b.Session, interaction)
+	data := interaction.ApplicationCommandData()
+	optionsTable, argsTable := b.buildOptionsTable(L, nil, nil, data.Options, data.Resolved)
+	interactionTable.RawSetString("options", optionsTable)
+	interactionTable.RawSetString("args", argsTable)
+	return interactionTable, state
 }
 
-// buildOptionsTable recursively builds a Lua table from Discord interaction options.
-func (b *ApplicationCommandBinding) buildOptionsTable(L *lua.LState, T *lua.LTable, options []*discordgo.ApplicationCommandInteractionDataOption) *lua.LTable {
+// buildOptionsTable recursively builds Lua tables from Discord interaction options:
+// one keyed by option name, and an array of {name, type, value} tables in the
+// order the options were given, for handlers that don't know their options
+// in advance. Resolved data is used to expand options that reference
+// objects, such as attachments.
+func (b *ApplicationCommandBinding) buildOptionsTable(L *lua.LState, T *lua.LTable, A *lua.LTable, options []*discordgo.ApplicationCommandInteractionDataOption, resolved *discordgo.ApplicationCommandInteractionDataResolved) (*lua.LTable, *lua.LTable) {
 	if T == nil {
 		T = L.NewTable()
 	}
+	if A == nil {
+		A = L.NewTable()
+	}
 
 	for _, opt := range options {
 		if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
 			if opt.Options != nil {
-				return b.buildOptionsTable(L, T, opt.Options)
+				return b.buildOptionsTable(L, T, A, opt.Options, resolved)
 			}
 		} else {
+			if opt.Value == nil {
+				slog.Warn("Skipping option without a value", "option", opt.Name, "type", opt.Type.String())
+				continue
+			}
+
+			var value lua.LValue
 			switch opt.Type {
 			case discordgo.ApplicationCommandOptionInteger:
-				T.RawSetString(opt.Name, lua.LNumber(opt.IntValue()))
+				value = lua.LNumber(opt.IntValue())
 			case discordgo.ApplicationCommandOptionBoolean:
-				T.RawSetString(opt.Name, lua.LBool(opt.BoolValue()))
+				value = lua.LBool(opt.BoolValue())
 			case discordgo.ApplicationCommandOptionString:
-				T.RawSetString(opt.Name, lua.LString(opt.StringValue()))
+				value = lua.LString(opt.StringValue())
 			case discordgo.ApplicationCommandOptionNumber:
-				T.RawSetString(opt.Name, lua.LNumber(opt.FloatValue()))
+				value = lua.LNumber(opt.FloatValue())
+			case discordgo.ApplicationCommandOptionUser,
+				discordgo.ApplicationCommandOptionChannel,
+				discordgo.ApplicationCommandOptionRole,
+				discordgo.ApplicationCommandOptionMentionable,
+				discordgo.ApplicationCommandOptionAttachment:
+				// These options carry the snowflake ID of the selected object,
+				// which is kept even when it could not be resolved.
+				id, ok := opt.Value.(string)
+				if !ok {
+					slog.Warn("Skipping option with unexpected value", "option", opt.Name, "type", opt.Type.String())
+					continue
+				}
+				value = utils.PrepareResolvedOptionTable(L, opt.Type, id, resolved)
 			default:
-				T.RawSetString(opt.Name, lua.LString(fmt.Sprintf("%v", opt.Value)))
+				slog.Warn("Skipping unsupported option type", "option", opt.Name, "type", opt.Type.String())
+				continue
 			}
+
+			T.RawSetString(opt.Name, value)
+
+			argTable := L.NewTable()
+			argTable.RawSetString("name", lua.LString(opt.Name))
+			argTable.RawSetString("type", lua.LNumber(opt.Type))
+			argTable.RawSetString("value", value)
+			A.Append(argTable)
 		}
 	}
-	return T
+	return T, A
 }
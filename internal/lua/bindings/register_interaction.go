@@ -65,6 +65,18 @@ func (b *InteractionEventBinding) Register() lua.LGFunction {
 	}
 }
 
+// Reset removes every registered interaction handler.
+func (b *InteractionEventBinding) Reset(L *lua.LState) {
+	for _, handler := range b.Interactions {
+		L.SetGlobal(handler, lua.LNil)
+	}
+	for _, handler := range b.RegexHandlers {
+		L.SetGlobal(handler, lua.LNil)
+	}
+	b.Interactions = make(map[string]string)
+	b.RegexHandlers = make(map[*regexp.Regexp]string)
+}
+
 func (b *InteractionEventBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
 	return interaction.Type == discordgo.InteractionMessageComponent ||
 		interaction.Type == discordgo.InteractionModalSubmit
@@ -74,12 +86,25 @@ func (b *InteractionEventBinding) CanHandleInteraction(interaction *discordgo.In
 func (b *InteractionEventBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
 	customID := interaction.MessageComponentData().CustomID
 
-	// Check for exact matches first
+	// The handlers are looked up on the Lua runner, where reloading replaces them
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		handlerName, groupMap, exists := b.match(customID)
+		if !exists {
+			slog.Warn("No handler found for interaction", "custom_id", customID)
+			return
+		}
+		b.executeHandler(L, interaction, handlerName, customID, groupMap)
+	})
+	return nil
+}
+
+// match finds the handler for a custom ID, checking exact matches before
+// regex patterns, and returns the named groups of a matched pattern.
+func (b *InteractionEventBinding) match(customID string) (string, map[string]string, bool) {
 	if handlerName, exists := b.Interactions[customID]; exists {
-		return b.executeHandler(interaction, handlerName, customID, nil)
+		return handlerName, nil, true
 	}
 
-	// Check regex-based handlers
 	for pattern, handlerName := range b.RegexHandlers {
 
 		slog.Debug("Checking regex pattern", "pattern", pattern.String(), "custom_id", customID)
@@ -90,69 +115,71 @@ func (b *InteractionEventBinding) HandleInteraction(interaction *discordgo.Inter
 					groupMap[name] = matches[i]
 				}
 			}
-			return b.executeHandler(interaction, handlerName, customID, groupMap)
+			return handlerName, groupMap, true
 		}
 	}
-
-	slog.Warn("No handler found for interaction", "custom_id", customID)
-	return fmt.Errorf("no handler registered for custom ID '%s'", customID)
+	return "", nil, false
 }
 
 // executeHandler executes the Lua handler for a given custom ID and attaches data from regex matches if available.
-func (b *InteractionEventBinding) executeHandler(interaction *discordgo.InteractionCreate, handlerName, matchedID string, groupMap map[string]string) error {
-
-	utils.GetLuaRunner().Do(func(L *lua.LState) {
-		fn := L.GetGlobal(handlerName)
-		if fn == lua.LNil {
-			slog.Error("Lua handler not implemented", "custom_id", matchedID)
-			return
-		}
+func (b *InteractionEventBinding) executeHandler(L *lua.LState, interaction *discordgo.InteractionCreate, handlerName, matchedID string, groupMap map[string]string) {
+	fn := L.GetGlobal(handlerName)
+	if fn == lua.LNil {
+		slog.Error("Lua handler not implemented", "custom_id", matchedID)
+		return
+	}
 
-		// Prepare the interaction table
-		interactionTable := b.prepareInteractionTable(L, interaction)
+	// Prepare the interaction table
+	interactionTable, state := b.prepareInteractionTable(L, interaction)
 
-		// Add the extracted data from regex as a subtable if available
-		if groupMap != nil {
-			dataTable := L.NewTable()
-			for key, value := range groupMap {
-				dataTable.RawSetString(key, lua.LString(value))
-			}
-			interactionTable.RawSetString("data", dataTable)
-		} else {
-			interactionTable.RawSetString("data", lua.LNil)
+	// Add the extracted data from regex as a subtable if available
+	if groupMap != nil {
+		dataTable := L.NewTable()
+		for key, value := range groupMap {
+			dataTable.RawSetString(key, lua.LString(value))
 		}
+		interactionTable.RawSetString("data", dataTable)
+	} else {
+		interactionTable.RawSetString("data", lua.LNil)
+	}
 
-		if interaction.MessageComponentData().Values != nil {
-			valuesTable := L.NewTable()
-			for _, value := range interaction.MessageComponentData().Values {
-				valuesTable.Append(lua.LString(value))
-			}
-			interactionTable.RawSetString("values", valuesTable)
-		} else {
-			interactionTable.RawSetString("values", lua.LNil)
+	if interaction.MessageComponentData().Values != nil {
+		valuesTable := L.NewTable()
+		for _, value := range interaction.MessageComponentData().Values {
+			valuesTable.Append(lua.LString(value))
 		}
+		interactionTable.RawSetString("values", valuesTable)
+	} else {
+		interactionTable.RawSetString("values", lua.LNil)
+	}
+	if selected := utils.PrepareSelectedTable(L, interaction.MessageComponentData()); selected != nil {
+		interactionTable.RawSetString("selected", selected)
+	}
 
-		// Call the Lua function
-		err := L.CallByParam(lua.P{
+	// Call the Lua function
+	var err error
+	utils.GetLuaRunner().WithInteraction(interaction, func() {
+		err = L.CallByParam(lua.P{
 			Fn:      fn,
 			NRet:    0,
 			Protect: true,
 		}, interactionTable)
-		if err != nil {
-			slog.Error("Error executing Lua interaction handler", "error", err, "custom_id", matchedID)
-			return
-		}
-
-		slog.Info("Interaction handled successfully", "custom_id", matchedID)
 	})
-	return nil
+	utils.EnsureResponded(b.Session, interaction, state, matchedID)
+	utils.ReleaseInteraction(interactionTable, state)
+	if err != nil {
+		slog.Error("Error executing Lua interaction handler", "error", err, "custom_id", matchedID)
+		return
+	}
+
+	slog.Info("Interaction handled successfully", "custom_id", matchedID)
 }
 
 // prepareInteractionTable prepares a Lua table containing interaction details.
-func (b *InteractionEventBinding) prepareInteractionTable(L *lua.LState, interaction *discordgo.InteractionCreate) *lua.LTable {
-	interactionTable := utils.PrepareInteractionTable(L, b.Session, interaction)
+func (b *InteractionEventBinding) prepareInteractionTable(L *lua.LState, interaction *discordgo.InteractionCreate) (*lua.LTable, *utils.ResponseState) {
+	interactionTable, state := utils.PrepareInteractionTable(L, b.Session, interaction)
 	interactionTable.RawSetString("custom_id", lua.LString(interaction.MessageComponentData().CustomID))
-	return interactionTable
+	return interactionTable, state
 }
 
 // isRegex checks if a string is a valid regex pattern.
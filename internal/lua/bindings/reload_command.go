@@ -0,0 +1,167 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxReloadReportLength keeps the reload report within a message.
+const maxReloadReportLength = 1900
+
+// ScriptResult is the outcome of loading a single script.
+type ScriptResult struct {
+	Path string // Path of the script, relative to the scripts directory
+	Err  error  // Why the script failed to load, nil if it loaded
+}
+
+// ReloadCommandBinding implements the `register_reload_command` Lua function,
+// which declares a built-in command that reloads every script from Discord.
+// Only administrators can see or use it, and it replies with the scripts
+// that loaded and the errors of those that didn't.
+type ReloadCommandBinding struct {
+	Session *discordgo.Session
+
+	mu      sync.Mutex
+	command string // Name of the declared command, empty until declared
+
+	commands *ApplicationCommandBinding
+	reload   func(done func(results []ScriptResult))
+}
+
+// NewReloadCommandBinding creates a new ReloadCommandBinding declaring its
+// command through the given command binding, and reloading with reload.
+func NewReloadCommandBinding(commands *ApplicationCommandBinding, reload func(done func(results []ScriptResult))) *ReloadCommandBinding {
+	slog.Debug("Creating new ReloadCommandBinding")
+	return &ReloadCommandBinding{
+		commands: commands,
+		reload:   reload,
+	}
+}
+
+// Name returns the name of the Lua function for this binding.
+func (b *ReloadCommandBinding) Name() string {
+	return "register_reload_command"
+}
+
+func (b *ReloadCommandBinding) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register adds the `register_reload_command` function to Lua. It takes an
+// optional name for the command, "reload" by default.
+func (b *ReloadCommandBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.OptString(1, "reload")
+		if err := validateCommandName(name); err != nil {
+			L.ArgError(1, fmt.Sprintf("command '%s': %s", name, err))
+			return 0
+		}
+
+		permissions := int64(discordgo.PermissionAdministrator)
+		noDM := false
+		b.commands.addDefinition(&discordgo.ApplicationCommand{
+			Name:                     name,
+			Description:              "Reload the bot's scripts",
+			DefaultMemberPermissions: &permissions,
+			DMPermission:             &noDM,
+		})
+		b.mu.Lock()
+		b.command = name
+		b.mu.Unlock()
+
+		slog.Info("Registered reload command", "name", name)
+		if b.commands.Session != nil && !b.commands.holdSync {
+			b.commands.syncCommands(b.commands.Session)
+		}
+		return 0
+	}
+}
+
+// Reset forgets the command, which the reloaded scripts declare again.
+func (b *ReloadCommandBinding) Reset(L *lua.LState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.command = ""
+}
+
+// CanHandleInteraction matches invocations of the declared command.
+func (b *ReloadCommandBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	if interaction.Type != discordgo.InteractionApplicationCommand {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.command != "" && interaction.ApplicationCommandData().Name == b.command
+}
+
+// HandleInteraction reloads the scripts for an administrator and reports the
+// results. The command is hidden from other members, but permissions can be
+// overridden per guild, so they are checked again here.
+func (b *ReloadCommandBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	if interaction.Member == nil || interaction.Member.Permissions&discordgo.PermissionAdministrator == 0 {
+		if err := b.Session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "Only administrators can reload the scripts.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}); err != nil {
+			slog.Error("Failed to reject reload command", "error", err)
+		}
+		return nil
+	}
+
+	// Reloading can take longer than Discord waits for a response
+	if err := b.Session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to defer reload command: %w", err)
+	}
+
+	slog.Info("Reloading scripts from command", "user_id", interaction.Member.User.ID)
+	b.reload(func(results []ScriptResult) {
+		report := reloadReport(results)
+		if _, err := b.Session.InteractionResponseEdit(interaction.Interaction, &discordgo.WebhookEdit{
+			Content: &report,
+		}); err != nil {
+			slog.Error("Failed to report reload results", "error", err)
+		}
+	})
+	return nil
+}
+
+// reloadReport summarises the reload for the administrator who ran it.
+func reloadReport(results []ScriptResult) string {
+	var loaded []string
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, fmt.Sprintf("`%s`: %s", result.Path, result.Err))
+		} else {
+			loaded = append(loaded, fmt.Sprintf("`%s`", result.Path))
+		}
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "Reloaded %d of %d scripts.", len(loaded), len(results))
+	if len(loaded) > 0 {
+		fmt.Fprintf(&report, "\n**Loaded:** %s", strings.Join(loaded, ", "))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(&report, "\n**Failed:**\n%s", strings.Join(failed, "\n"))
+	}
+
+	if report.Len() > maxReloadReportLength {
+		return strings.ToValidUTF8(report.String()[:maxReloadReportLength], "") + "\n…"
+	}
+	return report.String()
+}
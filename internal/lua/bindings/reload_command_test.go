@@ -0,0 +1,198 @@
+package bindings
+
+import (
+	"driftwood/internal/lua/utils"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// acceptDiscord answers every request to Discord successfully.
+type acceptDiscord struct{}
+
+func (acceptDiscord) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+const reloadedScript = `
+	handled = handled or { command = 0, autocomplete = 0, component = 0 }
+
+	register({
+		name = "ping",
+		description = "Ping the bot",
+		options = {
+			{
+				type = 3,
+				name = "target",
+				description = "What to ping",
+				autocomplete = function()
+					handled.autocomplete = handled.autocomplete + 1
+					return {}
+				end,
+			},
+		},
+		handler = function()
+			handled.command = handled.command + 1
+		end,
+	})
+	register_interaction("press", function()
+		handled.component = handled.component + 1
+	end)
+	register_reload_command()
+`
+
+func TestReloadWhileDispatching(t *testing.T) {
+	session, _ := discordgo.New("Bot token")
+	session.Client = &http.Client{Transport: acceptDiscord{}}
+
+	commands := newTestCommandBinding()
+	commands.Session = session
+	interactions := NewInteractionEventBinding()
+	interactions.Session = session
+	var reloads atomic.Int64
+	reload := NewReloadCommandBinding(commands, func(done func(results []ScriptResult)) {
+		reloads.Add(1)
+		done(nil)
+	})
+	reload.Session = session
+
+	// Reloading resets every binding before the script declares them again,
+	// and never finishes, so nothing is synced with Discord
+	runner := utils.GetLuaRunner()
+	load := func(L *lua.LState) {
+		commands.Reset(L)
+		interactions.Reset(L)
+		reload.Reset(L)
+		L.SetGlobal("register", L.NewFunction(commands.Register()))
+		L.SetGlobal("register_interaction", L.NewFunction(interactions.Register()))
+		L.SetGlobal("register_reload_command", L.NewFunction(reload.Register()))
+		if err := L.DoString(reloadedScript); err != nil {
+			t.Error(err)
+		}
+	}
+	runner.Do(load)
+	runner.Wait()
+
+	member := &discordgo.Member{
+		User:        &discordgo.User{ID: "1"},
+		Permissions: discordgo.PermissionAdministrator,
+	}
+	interaction := func(kind discordgo.InteractionType, data discordgo.InteractionData) *discordgo.InteractionCreate {
+		return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			ID:      "2",
+			AppID:   "100",
+			Token:   "token",
+			Type:    kind,
+			GuildID: "guild",
+			Member:  member,
+			Data:    data,
+		}}
+	}
+
+	const dispatches = 50
+	tests := []struct {
+		name        string
+		binding     LuaBinding
+		interaction *discordgo.InteractionCreate
+		handled     func(L *lua.LState) int64
+	}{
+		{
+			name:        "command",
+			binding:     commands,
+			interaction: interaction(discordgo.InteractionApplicationCommand, discordgo.ApplicationCommandInteractionData{Name: "ping"}),
+			handled:     handledCount("command"),
+		},
+		{
+			name:    "autocomplete",
+			binding: commands,
+			interaction: interaction(discordgo.InteractionApplicationCommandAutocomplete, discordgo.ApplicationCommandInteractionData{
+				Name: "ping",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "target", Type: discordgo.ApplicationCommandOptionString, Value: "a", Focused: true},
+				},
+			}),
+			handled: handledCount("autocomplete"),
+		},
+		{
+			name:        "component",
+			binding:     interactions,
+			interaction: interaction(discordgo.InteractionMessageComponent, discordgo.MessageComponentInteractionData{CustomID: "press"}),
+			handled:     handledCount("component"),
+		},
+		{
+			// The command is briefly undeclared while reloading, so only
+			// matching it safely is checked
+			name:        "reload command",
+			binding:     reload,
+			interaction: interaction(discordgo.InteractionApplicationCommand, discordgo.ApplicationCommandInteractionData{Name: "reload"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := runHandledCount(runner, tt.handled)
+
+			var wg sync.WaitGroup
+			for range dispatches {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					runner.Do(load)
+				}()
+				go func() {
+					defer wg.Done()
+					if tt.binding.CanHandleInteraction(tt.interaction) {
+						if err := tt.binding.HandleInteraction(tt.interaction); err != nil {
+							t.Error(err)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			runner.Wait()
+
+			if tt.handled == nil {
+				return
+			}
+			if got := runHandledCount(runner, tt.handled) - before; got != dispatches {
+				t.Errorf("handled %d of %d dispatches", got, dispatches)
+			}
+		})
+	}
+
+	if reloads.Load() == 0 {
+		t.Error("reload command was never handled")
+	}
+}
+
+// handledCount reads how often the script's handler of a kind was called.
+func handledCount(kind string) func(L *lua.LState) int64 {
+	return func(L *lua.LState) int64 {
+		handled := L.GetGlobal("handled").(*lua.LTable)
+		return int64(handled.RawGetString(kind).(lua.LNumber))
+	}
+}
+
+// runHandledCount reads a handled count on the runner.
+func runHandledCount(runner *utils.LuaRunner, handled func(L *lua.LState) int64) int64 {
+	if handled == nil {
+		return 0
+	}
+	var count int64
+	runner.Do(func(L *lua.LState) {
+		count = handled(L)
+	})
+	runner.Wait()
+	return count
+}
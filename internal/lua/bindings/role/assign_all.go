@@ -0,0 +1,273 @@
+package role
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// memberPageSize is the largest page Discord returns when listing members.
+	memberPageSize = 1000
+
+	// assignInterval spaces out role changes so a large guild doesn't exhaust
+	// the rate limit shared with the rest of the bot.
+	assignInterval = 250 * time.Millisecond
+)
+
+// assignFilter selects the members a bulk assignment applies to.
+type assignFilter struct {
+	joinedBefore time.Time
+	joinedAfter  time.Time
+	hasRole      string
+	includeBots  bool
+}
+
+// matches reports whether a member is selected by the filter.
+func (f assignFilter) matches(member *discordgo.Member) bool {
+	if member.User != nil && member.User.Bot && !f.includeBots {
+		return false
+	}
+	if !f.joinedBefore.IsZero() && !member.JoinedAt.Before(f.joinedBefore) {
+		return false
+	}
+	if !f.joinedAfter.IsZero() && !member.JoinedAt.After(f.joinedAfter) {
+		return false
+	}
+	if f.hasRole != "" && !slices.Contains(member.Roles, f.hasRole) {
+		return false
+	}
+	return true
+}
+
+// assignResult counts the outcome of a bulk assignment.
+type assignResult struct {
+	changed  int
+	skipped  int
+	failed   int
+	wouldAdd []string // Members that would have been given the role, in dry-run mode
+}
+
+// RoleBindingAssignAll provides Lua bindings for adding a role to every guild
+// member matching a filter.
+type RoleBindingAssignAll struct {
+	Session *discordgo.Session
+	GuildID string
+
+	mu      sync.Mutex
+	running map[string]bool // Roles with an assignment in progress
+	nextID  int
+}
+
+// NewRoleBindingAssignAll initializes a new role assign all instance.
+func NewRoleBindingAssignAll(guildID string) *RoleBindingAssignAll {
+	slog.Debug("Creating new RoleBindingAssignAll")
+	return &RoleBindingAssignAll{
+		GuildID: guildID,
+		running: make(map[string]bool),
+	}
+}
+
+// Name returns the name of the binding.
+func (b *RoleBindingAssignAll) Name() string {
+	return "assign_all"
+}
+
+func (b *RoleBindingAssignAll) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the role-related functions in the Lua state. Members
+// are assigned in the background, and the optional callback receives the
+// counts once every member has been visited. In dry-run mode no role is
+// added, and the callback receives a preview of the members that would have
+// been given it along with the counts.
+func (b *RoleBindingAssignAll) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		roleID := L.CheckString(1)
+		filterTable := L.OptTable(2, L.NewTable())
+		callback := L.OptFunction(3, nil)
+
+		filter, err := parseAssignFilter(filterTable)
+		if err != nil {
+			L.ArgError(2, err.Error())
+			return 0
+		}
+
+		b.mu.Lock()
+		if b.running[roleID] {
+			b.mu.Unlock()
+			L.Push(lua.LFalse)
+			L.Push(lua.LString("An assignment of this role is already in progress"))
+			return 2
+		}
+		b.running[roleID] = true
+		handler := ""
+		if callback != nil {
+			handler = fmt.Sprintf("role_assign_all_handler_%d", b.nextID)
+			b.nextID++
+			L.SetGlobal(handler, callback)
+		}
+		b.mu.Unlock()
+
+		go b.assign(roleID, filter, handler, utils.DryRun())
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// assign adds the role to the guild members matching the filter, then
+// reports the counts to the callback. A dry run only notes the members it
+// would have changed.
+func (b *RoleBindingAssignAll) assign(roleID string, filter assignFilter, handler string, dryRun bool) {
+	defer func() {
+		b.mu.Lock()
+		delete(b.running, roleID)
+		b.mu.Unlock()
+	}()
+
+	slog.Info("Starting bulk role assignment", "guild_id", b.GuildID, "role_id", roleID, "dry_run", dryRun)
+	result, assignErr := b.visitMembers(roleID, filter, dryRun)
+
+	slog.Info("Finished bulk role assignment", "guild_id", b.GuildID, "role_id", roleID, "changed", result.changed, "skipped", result.skipped, "failed", result.failed)
+	if dryRun {
+		slog.Info("Dry run: would add role", "guild_id", b.GuildID, "role_id", roleID, "members", len(result.wouldAdd))
+	}
+
+	if handler == "" {
+		return
+	}
+
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		fn := L.GetGlobal(handler)
+		L.SetGlobal(handler, lua.LNil)
+
+		resultTable := L.NewTable()
+		if dryRun {
+			resultTable = utils.PrepareDryRunPreview(L, "add_role", result.wouldAdd)
+		}
+		resultTable.RawSetString("changed", lua.LNumber(result.changed))
+		resultTable.RawSetString("skipped", lua.LNumber(result.skipped))
+		resultTable.RawSetString("failed", lua.LNumber(result.failed))
+
+		errValue := lua.LValue(lua.LNil)
+		if assignErr != nil {
+			errValue = lua.LString(assignErr.Error())
+		}
+
+		if err := L.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    0,
+			Protect: true,
+		}, resultTable, errValue); err != nil {
+			slog.Error("Error executing Lua assign_all callback", "error", err)
+		}
+	})
+}
+
+// visitMembers adds the role to every matching member, one page of members
+// at a time. A panic stops the assignment rather than the bot, and is
+// returned like any other failure so the callback still hears back.
+func (b *RoleBindingAssignAll) visitMembers(roleID string, filter assignFilter, dryRun bool) (result assignResult, err error) {
+	defer func() {
+		if rcv := recover(); rcv != nil {
+			slog.Error("Recovered from panic during bulk role assignment", "guild_id", b.GuildID, "role_id", roleID, "panic", rcv, "stack", string(debug.Stack()))
+			err = fmt.Errorf("Assignment stopped unexpectedly: %v", rcv)
+		}
+	}()
+
+	after := ""
+	for {
+		members, err := b.Session.GuildMembers(b.GuildID, after, memberPageSize)
+		if err != nil {
+			slog.Error("Failed to list guild members", "guild_id", b.GuildID, "error", err)
+			return result, fmt.Errorf("Failed to list guild members: %w", err)
+		}
+
+		// Pages continue after the last member with a user, as members
+		// without one can't be assigned a role or page the list
+		last := ""
+		for _, member := range members {
+			if member.User == nil {
+				result.skipped++
+				continue
+			}
+			last = member.User.ID
+
+			if slices.Contains(member.Roles, roleID) || !filter.matches(member) {
+				result.skipped++
+				continue
+			}
+
+			if dryRun {
+				result.wouldAdd = append(result.wouldAdd, member.User.ID)
+				result.changed++
+				continue
+			}
+
+			if err := b.Session.GuildMemberRoleAdd(b.GuildID, member.User.ID, roleID); err != nil {
+				slog.Error("Failed to add role", "guild_id", b.GuildID, "user_id", member.User.ID, "role_id", roleID, "error", err)
+				result.failed++
+			} else {
+				result.changed++
+			}
+			time.Sleep(assignInterval)
+		}
+
+		if len(members) < memberPageSize {
+			return result, nil
+		}
+		if last == "" {
+			slog.Error("Guild member page has no users to continue after", "guild_id", b.GuildID, "after", after)
+			return result, fmt.Errorf("Failed to list guild members: a page had no users to continue after")
+		}
+		after = last
+	}
+}
+
+// parseAssignFilter reads the filter table. Join dates are unix timestamps
+// in seconds.
+func parseAssignFilter(table *lua.LTable) (assignFilter, error) {
+	var filter assignFilter
+
+	for _, field := range []string{"joined_before", "joined_after"} {
+		value := table.RawGetString(field)
+		if value == lua.LNil {
+			continue
+		}
+		timestamp, ok := value.(lua.LNumber)
+		if !ok {
+			return filter, fmt.Errorf("%s must be a unix timestamp", field)
+		}
+		if field == "joined_before" {
+			filter.joinedBefore = time.Unix(int64(timestamp), 0)
+		} else {
+			filter.joinedAfter = time.Unix(int64(timestamp), 0)
+		}
+	}
+
+	if hasRole, ok := table.RawGetString("has_role").(lua.LString); ok {
+		filter.hasRole = string(hasRole)
+	}
+	filter.includeBots = lua.LVAsBool(table.RawGetString("include_bots"))
+
+	return filter, nil
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *RoleBindingAssignAll) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *RoleBindingAssignAll) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,169 @@
+package role
+
+import (
+	"driftwood/internal/lua/utils"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// memberPages answers member listings with the page following each `after`.
+type memberPages struct {
+	pages map[string][]*discordgo.Member
+
+	mu     sync.Mutex
+	afters []string // The `after` of every listing, in order
+}
+
+func (f *memberPages) RoundTrip(req *http.Request) (*http.Response, error) {
+	after := req.URL.Query().Get("after")
+	f.mu.Lock()
+	f.afters = append(f.afters, after)
+	f.mu.Unlock()
+
+	page, ok := f.pages[after]
+	if !ok {
+		page = []*discordgo.Member{}
+	}
+	body, _ := json.Marshal(page)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+		Request:    req,
+	}, nil
+}
+
+// fullPage returns a page of members with users numbered from first,
+// except for the members at the given positions, which have no user.
+func fullPage(first int, withoutUser ...int) []*discordgo.Member {
+	page := make([]*discordgo.Member, memberPageSize)
+	for idx := range page {
+		page[idx] = &discordgo.Member{User: &discordgo.User{ID: strconv.Itoa(first + idx)}}
+	}
+	for _, idx := range withoutUser {
+		page[idx].User = nil
+	}
+	return page
+}
+
+// pageWithoutUsers returns a full page of members that have no user.
+func pageWithoutUsers() []*discordgo.Member {
+	page := make([]*discordgo.Member, memberPageSize)
+	for idx := range page {
+		page[idx] = &discordgo.Member{}
+	}
+	return page
+}
+
+func TestAssignAllMembersWithoutUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		session     bool
+		pages       map[string][]*discordgo.Member
+		wantAfters  []string
+		wantChanged int
+		wantSkipped int
+		wantErr     string
+	}{
+		{
+			name: "member without user is skipped",
+			pages: map[string][]*discordgo.Member{
+				"": {{User: nil}, {User: &discordgo.User{ID: "2"}}},
+			},
+			session:     true,
+			wantAfters:  []string{""},
+			wantChanged: 1,
+			wantSkipped: 1,
+		},
+		{
+			name: "page ending without user continues after the last user",
+			pages: map[string][]*discordgo.Member{
+				"":     fullPage(1, memberPageSize-1),
+				"999":  {{User: &discordgo.User{ID: "1001"}}},
+				"1000": {{User: &discordgo.User{ID: "wrong"}}},
+			},
+			session:     true,
+			wantAfters:  []string{"", "999"},
+			wantChanged: memberPageSize,
+			wantSkipped: 1,
+		},
+		{
+			name: "full page without users stops",
+			pages: map[string][]*discordgo.Member{
+				"": pageWithoutUsers(),
+			},
+			session:     true,
+			wantAfters:  []string{""},
+			wantSkipped: memberPageSize,
+			wantErr:     "Failed to list guild members: a page had no users to continue after",
+		},
+		{
+			name:    "panic is reported to the callback",
+			pages:   map[string][]*discordgo.Member{},
+			wantErr: "Assignment stopped unexpectedly: runtime error: invalid memory address or nil pointer dereference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &memberPages{pages: tt.pages}
+			binding := NewRoleBindingAssignAll("guild")
+			if tt.session {
+				session, _ := discordgo.New("Bot token")
+				session.Client = &http.Client{Transport: fake}
+				binding.Session = session
+			}
+
+			utils.SetDryRun(true)
+			defer utils.SetDryRun(false)
+
+			type report struct {
+				changed, skipped int
+				err              string
+			}
+			reports := make(chan report, 1)
+			utils.GetLuaRunner().Do(func(L *lua.LState) {
+				L.SetGlobal("assign_all", L.NewFunction(binding.Register()))
+				L.SetGlobal("report", L.NewFunction(func(L *lua.LState) int {
+					result := L.CheckTable(1)
+					reports <- report{
+						changed: int(lua.LVAsNumber(result.RawGetString("changed"))),
+						skipped: int(lua.LVAsNumber(result.RawGetString("skipped"))),
+						err:     lua.LVAsString(L.Get(2)),
+					}
+					return 0
+				}))
+				if err := L.DoString(`assign_all("3", {}, report)`); err != nil {
+					t.Error(err)
+				}
+			})
+
+			var got report
+			select {
+			case got = <-reports:
+			case <-time.After(5 * time.Second):
+				t.Fatal("callback was never called")
+			}
+
+			if got.changed != tt.wantChanged || got.skipped != tt.wantSkipped {
+				t.Errorf("changed %d and skipped %d, want %d and %d", got.changed, got.skipped, tt.wantChanged, tt.wantSkipped)
+			}
+			if got.err != tt.wantErr {
+				t.Errorf("error = %q, want %q", got.err, tt.wantErr)
+			}
+			if tt.session && !reflect.DeepEqual(fake.afters, tt.wantAfters) {
+				t.Errorf("listed members after %q, want %q", fake.afters, tt.wantAfters)
+			}
+		})
+	}
+}
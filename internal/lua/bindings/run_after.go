@@ -11,7 +11,9 @@ import (
 )
 
 // RunAfterBinding implements the `run_after` Lua function.
-type RunAfterBinding struct{}
+type RunAfterBinding struct {
+	nextID int // Numbers callback globals so they never collide
+}
 
 // NewRunAfterBinding creates a new RunAfterBinding.
 func NewRunAfterBinding() *RunAfterBinding {
@@ -38,7 +40,8 @@ func (b *RunAfterBinding) Register() lua.LGFunction {
 		}
 
 		// Generate a unique global name for the function
-		globalName := fmt.Sprintf("__run_after_%d", time.Now().UnixNano())
+		globalName := fmt.Sprintf("__run_after_%d", b.nextID)
+		b.nextID++
 		L.SetGlobal(globalName, fn)
 
 		// Start a goroutine to delay and call the function
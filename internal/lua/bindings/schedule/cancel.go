@@ -0,0 +1,48 @@
+package schedule
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ScheduleBindingCancel provides Lua bindings for cancelling a scheduled task.
+type ScheduleBindingCancel struct {
+	Scheduler *Scheduler
+}
+
+// NewScheduleBindingCancel initializes a new schedule cancel instance.
+func NewScheduleBindingCancel(scheduler *Scheduler) *ScheduleBindingCancel {
+	slog.Debug("Creating new ScheduleBindingCancel")
+	return &ScheduleBindingCancel{
+		Scheduler: scheduler,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *ScheduleBindingCancel) Name() string {
+	return "cancel"
+}
+
+func (b *ScheduleBindingCancel) SetSession(session *discordgo.Session) {}
+
+// Register registers the schedule-related functions in the Lua state.
+func (b *ScheduleBindingCancel) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		id := L.CheckString(1)
+
+		L.Push(lua.LBool(b.Scheduler.Cancel(id)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ScheduleBindingCancel) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ScheduleBindingCancel) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,51 @@
+package schedule
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ScheduleBindingHandler provides Lua bindings for naming the handlers
+// scheduled tasks call.
+type ScheduleBindingHandler struct {
+	Scheduler *Scheduler
+}
+
+// NewScheduleBindingHandler initializes a new schedule handler instance.
+func NewScheduleBindingHandler(scheduler *Scheduler) *ScheduleBindingHandler {
+	slog.Debug("Creating new ScheduleBindingHandler")
+	return &ScheduleBindingHandler{
+		Scheduler: scheduler,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *ScheduleBindingHandler) Name() string {
+	return "handler"
+}
+
+func (b *ScheduleBindingHandler) SetSession(session *discordgo.Session) {}
+
+// Register registers the schedule-related functions in the Lua state.
+func (b *ScheduleBindingHandler) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.CheckString(1)
+		fn := L.CheckFunction(2)
+
+		b.Scheduler.SetHandler(L, name, fn)
+		slog.Info("Registered scheduled task handler", "name", name)
+		return 0
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ScheduleBindingHandler) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ScheduleBindingHandler) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
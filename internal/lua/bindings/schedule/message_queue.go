@@ -0,0 +1,173 @@
+package schedule
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"driftwood/internal/lua/utils"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// queuePrefix starts the state key of every message queue.
+const queuePrefix = "__message_queue:"
+
+// queueSendGap is the least time between two messages of a queue, so a queue
+// catching up after a restart doesn't send its backlog in one burst.
+const queueSendGap = time.Second
+
+// QueuedMessage is one message of a queue and when it is due.
+type QueuedMessage struct {
+	Content string
+	Embed   lua.LValue // Embed table, or nil
+	FireAt  time.Time
+}
+
+// Queue stores messages to be sent to a channel one after another, and arms
+// the first. Each message is only armed once the one before it was sent, so
+// they arrive in order however their times are spaced. Like tasks, queues
+// are kept in the StateManager and resume after a restart. It must be called
+// on the Lua runner, and returns the queue ID.
+func (s *Scheduler) Queue(L *lua.LState, channelID string, messages []QueuedMessage, skipMissed bool) string {
+	id := s.newID()
+
+	messagesTable := L.NewTable()
+	for _, message := range messages {
+		messageTable := L.NewTable()
+		messageTable.RawSetString("content", lua.LString(message.Content))
+		if message.Embed != nil {
+			messageTable.RawSetString("embed", message.Embed)
+		}
+		messageTable.RawSetString("fire_at_ms", lua.LNumber(message.FireAt.UnixMilli()))
+		messagesTable.Append(messageTable)
+	}
+
+	queue := L.NewTable()
+	queue.RawSetString("channel_id", lua.LString(channelID))
+	queue.RawSetString("messages", messagesTable)
+	queue.RawSetString("next", lua.LNumber(1))
+	queue.RawSetString("skip_missed", lua.LBool(skipMissed))
+	s.StateManager.Set(queuePrefix+id, queue, 0)
+
+	s.arm(id, time.Until(messages[0].FireAt), s.fireQueue)
+	return id
+}
+
+// restoreQueues arms the saved queues that aren't armed yet. Messages whose
+// time passed while the bot was down are sent one queueSendGap apart, or
+// dropped when the queue was set to skip missed messages.
+func (s *Scheduler) restoreQueues() {
+	restored, skipped := 0, 0
+	for _, key := range s.StateManager.Keys(queuePrefix) {
+		id := strings.TrimPrefix(key, queuePrefix)
+
+		s.mu.Lock()
+		_, armed := s.timers[id]
+		s.mu.Unlock()
+		if armed {
+			continue
+		}
+
+		queue, ok := s.StateManager.Get(key).(*lua.LTable)
+		if !ok {
+			s.StateManager.Clear(key)
+			continue
+		}
+		messages, ok := queue.RawGetString("messages").(*lua.LTable)
+		if !ok {
+			s.StateManager.Clear(key)
+			continue
+		}
+
+		next := int(lua.LVAsNumber(queue.RawGetString("next")))
+		if lua.LVAsBool(queue.RawGetString("skip_missed")) {
+			first := next
+			for next <= messages.Len() && time.Now().After(queuedFireAt(messages, next)) {
+				next++
+			}
+			if next > first {
+				slog.Info("Skipping queued messages missed while offline", "id", id, "count", next-first)
+				queue.RawSetString("next", lua.LNumber(next))
+				s.StateManager.Set(key, queue, 0)
+				skipped += next - first
+			}
+		}
+		if next > messages.Len() {
+			s.StateManager.Clear(key)
+			continue
+		}
+
+		s.arm(id, max(time.Until(queuedFireAt(messages, next)), 0), s.fireQueue)
+		restored++
+	}
+
+	if restored > 0 || skipped > 0 {
+		slog.Info("Restored message queues", "restored", restored, "skipped_messages", skipped)
+	}
+}
+
+// fireQueue sends the next message of a queue and arms the one after it.
+// A message that fails to send is logged and skipped, so one bad message
+// doesn't hold up the rest. Queues cancelled in the meantime are ignored.
+func (s *Scheduler) fireQueue(L *lua.LState, id string) {
+	queue, ok := s.StateManager.Get(queuePrefix + id).(*lua.LTable)
+	if !ok {
+		return
+	}
+	messages, ok := queue.RawGetString("messages").(*lua.LTable)
+	if !ok {
+		s.StateManager.Clear(queuePrefix + id)
+		return
+	}
+
+	channelID := queue.RawGetString("channel_id").String()
+	next := int(lua.LVAsNumber(queue.RawGetString("next")))
+	if message, ok := messages.RawGetInt(next).(*lua.LTable); ok {
+		s.sendQueued(L, id, channelID, message)
+	}
+
+	next++
+	if next > messages.Len() {
+		slog.Info("Message queue finished", "id", id, "channel_id", channelID)
+		s.StateManager.Clear(queuePrefix + id)
+		return
+	}
+	queue.RawSetString("next", lua.LNumber(next))
+	s.StateManager.Set(queuePrefix+id, queue, 0)
+
+	s.arm(id, max(time.Until(queuedFireAt(messages, next)), queueSendGap), s.fireQueue)
+}
+
+// sendQueued sends one message of a queue to its channel.
+func (s *Scheduler) sendQueued(L *lua.LState, id, channelID string, message *lua.LTable) {
+	send := &discordgo.MessageSend{
+		Content: lua.LVAsString(message.RawGetString("content")),
+	}
+	if embedTable, ok := message.RawGetString("embed").(*lua.LTable); ok {
+		embed, err := utils.ParseEmbed(L, embedTable)
+		if err != nil {
+			slog.Error("Invalid embed in queued message", "id", id, "error", err)
+			return
+		}
+		send.Embed = embed
+	}
+
+	err := utils.RetryRateLimited(func(options ...discordgo.RequestOption) error {
+		_, err := s.Session.ChannelMessageSendComplex(channelID, send, options...)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to send queued message", "id", id, "channel_id", channelID, "error", err)
+	}
+}
+
+// queuedFireAt returns when the message at index is due.
+func queuedFireAt(messages *lua.LTable, index int) time.Time {
+	message, ok := messages.RawGetInt(index).(*lua.LTable)
+	if !ok {
+		return time.Time{}
+	}
+	return savedFireAt(message)
+}
@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"driftwood/internal/lua/utils"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ScheduleBindingQueue provides Lua bindings for queuing messages to be sent
+// to a channel in order, spaced out over time.
+type ScheduleBindingQueue struct {
+	Scheduler *Scheduler
+}
+
+// NewScheduleBindingQueue initializes a new message queue instance.
+func NewScheduleBindingQueue(scheduler *Scheduler) *ScheduleBindingQueue {
+	slog.Debug("Creating new ScheduleBindingQueue")
+	return &ScheduleBindingQueue{
+		Scheduler: scheduler,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *ScheduleBindingQueue) Name() string {
+	return "queue"
+}
+
+func (b *ScheduleBindingQueue) SetSession(session *discordgo.Session) {
+	b.Scheduler.Session = session
+}
+
+// Register registers the schedule-related functions in the Lua state. Each
+// message is a string or a table with "content", "embed" and "delay", the
+// seconds to wait after the previous message. Messages without a delay wait
+// the "interval" of the options, except the first which is sent right away.
+func (b *ScheduleBindingQueue) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID := L.CheckString(1)
+		messagesTable := L.CheckTable(2)
+		opts := L.OptTable(3, nil)
+
+		interval := lua.LNumber(0)
+		skipMissed := false
+		if opts != nil {
+			if value := opts.RawGetString("interval"); value != lua.LNil {
+				number, ok := value.(lua.LNumber)
+				if !ok || number < 0 {
+					L.ArgError(3, "options.interval must be a non-negative number")
+					return 0
+				}
+				interval = number
+			}
+
+			switch missed := opts.RawGetString("missed"); missed {
+			case lua.LNil, lua.LString("fire"):
+			case lua.LString("skip"):
+				skipMissed = true
+			default:
+				L.ArgError(3, "options.missed must be \"fire\" or \"skip\"")
+				return 0
+			}
+		}
+
+		if messagesTable.Len() == 0 {
+			L.ArgError(2, "messages must be a non-empty array")
+			return 0
+		}
+
+		// Times are fixed now, so a restart doesn't shift the schedule
+		fireAt := time.Now()
+		messages := make([]QueuedMessage, 0, messagesTable.Len())
+		for i := 1; i <= messagesTable.Len(); i++ {
+			message := QueuedMessage{}
+			delay := interval
+			if i == 1 {
+				delay = 0
+			}
+
+			switch value := messagesTable.RawGetInt(i).(type) {
+			case lua.LString:
+				message.Content = string(value)
+			case *lua.LTable:
+				if content := value.RawGetString("content"); content != lua.LNil {
+					if content.Type() != lua.LTString {
+						L.ArgError(2, fmt.Sprintf("messages[%d].content must be a string", i))
+						return 0
+					}
+					message.Content = content.String()
+				}
+
+				if em := value.RawGetString("embed"); em != lua.LNil {
+					embedTable, ok := em.(*lua.LTable)
+					if !ok {
+						L.ArgError(2, fmt.Sprintf("messages[%d].embed must be a table", i))
+						return 0
+					}
+					if _, err := utils.ParseEmbed(L, embedTable); err != nil {
+						L.ArgError(2, fmt.Sprintf("invalid embed in messages[%d]: %s", i, err.Error()))
+						return 0
+					}
+
+					// Copied, so later changes to the table don't alter the
+					// queued message, and checked to be storable in state
+					raw, err := utils.LuaToGo(embedTable)
+					if err != nil {
+						L.ArgError(2, fmt.Sprintf("invalid embed in messages[%d]: %s", i, err.Error()))
+						return 0
+					}
+					message.Embed = utils.GoToLua(L, raw)
+				}
+
+				if d := value.RawGetString("delay"); d != lua.LNil {
+					number, ok := d.(lua.LNumber)
+					if !ok || number < 0 {
+						L.ArgError(2, fmt.Sprintf("messages[%d].delay must be a non-negative number", i))
+						return 0
+					}
+					delay = number
+				}
+			default:
+				L.ArgError(2, fmt.Sprintf("messages[%d] must be a string or a table", i))
+				return 0
+			}
+
+			if message.Content == "" && message.Embed == nil {
+				L.ArgError(2, fmt.Sprintf("messages[%d] must have content or an embed", i))
+				return 0
+			}
+
+			fireAt = fireAt.Add(time.Duration(float64(delay) * float64(time.Second)))
+			message.FireAt = fireAt
+			messages = append(messages, message)
+		}
+
+		id := b.Scheduler.Queue(L, channelID, messages, skipMissed)
+		slog.Debug("Queued messages", "id", id, "channel_id", channelID, "count", len(messages))
+
+		L.Push(lua.LString(id))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ScheduleBindingQueue) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ScheduleBindingQueue) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
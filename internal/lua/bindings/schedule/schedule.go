@@ -0,0 +1,76 @@
+package schedule
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ScheduleBindingSchedule provides Lua bindings for scheduling a task that
+// survives restarts.
+type ScheduleBindingSchedule struct {
+	Scheduler *Scheduler
+}
+
+// NewScheduleBindingSchedule initializes a new schedule instance.
+func NewScheduleBindingSchedule(scheduler *Scheduler) *ScheduleBindingSchedule {
+	slog.Debug("Creating new ScheduleBindingSchedule")
+	return &ScheduleBindingSchedule{
+		Scheduler: scheduler,
+	}
+}
+
+// Name returns the name of the binding.
+func (b *ScheduleBindingSchedule) Name() string {
+	return "schedule"
+}
+
+func (b *ScheduleBindingSchedule) SetSession(session *discordgo.Session) {}
+
+// Register registers the schedule-related functions in the Lua state. The
+// options table may set "missed" to "fire" (the default) or "skip", deciding
+// what happens to a task whose time passed while the bot was offline.
+func (b *ScheduleBindingSchedule) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		handler := L.CheckString(1)
+		delaySeconds := L.CheckNumber(2)
+		args := L.Get(3)
+		opts := L.OptTable(4, nil)
+
+		if delaySeconds < 0 {
+			L.ArgError(2, "delay must be a non-negative number")
+			return 0
+		}
+
+		skipMissed := false
+		if opts != nil {
+			switch missed := opts.RawGetString("missed"); missed {
+			case lua.LNil, lua.LString("fire"):
+			case lua.LString("skip"):
+				skipMissed = true
+			default:
+				L.ArgError(4, "options.missed must be \"fire\" or \"skip\"")
+				return 0
+			}
+		}
+
+		delay := time.Duration(float64(delaySeconds) * float64(time.Second))
+		id := b.Scheduler.Schedule(L, handler, delay, args, skipMissed)
+		slog.Debug("Scheduled task", "id", id, "handler", handler, "delay", delay)
+
+		L.Push(lua.LString(id))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ScheduleBindingSchedule) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ScheduleBindingSchedule) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,197 @@
+package schedule
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"driftwood/internal/lua/utils"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// taskPrefix starts the state key of every scheduled task.
+const taskPrefix = "__scheduled_task:"
+
+// Scheduler runs named Lua handlers at a later time. Each pending task is
+// kept in the StateManager with its fire time, handler name and arguments,
+// so with persistence enabled it is rescheduled after a restart. Handlers
+// are functions, which can't be saved, so scripts register them by name on
+// every start and tasks refer to them by that name.
+type Scheduler struct {
+	StateManager *utils.StateManager
+	Session      *discordgo.Session // Sends the messages of queues
+
+	mu       sync.Mutex
+	handlers map[string]string      // Maps handler names to Lua globals
+	timers   map[string]*time.Timer // Pending tasks armed in this process
+	nextID   int
+}
+
+// NewScheduler initializes the scheduler.
+func NewScheduler(sm *utils.StateManager) *Scheduler {
+	slog.Debug("Creating new Scheduler")
+	return &Scheduler{
+		StateManager: sm,
+		handlers:     make(map[string]string),
+		timers:       make(map[string]*time.Timer),
+	}
+}
+
+// SetHandler stores a handler under a name, replacing any handler with that
+// name. It must be called on the Lua runner.
+func (s *Scheduler) SetHandler(L *lua.LState, name string, fn *lua.LFunction) {
+	globalName := fmt.Sprintf("scheduled_handler_%s", name)
+	L.SetGlobal(globalName, fn)
+
+	s.mu.Lock()
+	s.handlers[name] = globalName
+	s.mu.Unlock()
+}
+
+// Schedule stores a task calling the named handler with args after the
+// delay, and arms it. When the task is restored after its fire time has
+// passed, it fires straight away unless skipMissed is set. It must be called
+// on the Lua runner, and returns the task ID.
+func (s *Scheduler) Schedule(L *lua.LState, handler string, delay time.Duration, args lua.LValue, skipMissed bool) string {
+	id := s.newID()
+	fireAt := time.Now().Add(delay)
+
+	task := L.NewTable()
+	task.RawSetString("handler", lua.LString(handler))
+	task.RawSetString("fire_at_ms", lua.LNumber(fireAt.UnixMilli()))
+	task.RawSetString("args", args)
+	task.RawSetString("skip_missed", lua.LBool(skipMissed))
+	s.StateManager.Set(taskPrefix+id, task, 0)
+
+	s.arm(id, delay, s.fire)
+	return id
+}
+
+// newID returns a task ID unique to this process and distinct from those of
+// previous runs.
+func (s *Scheduler) newID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("%d-%d", time.Now().UnixNano(), s.nextID)
+	s.nextID++
+	return id
+}
+
+// Cancel removes a pending task or message queue, reporting whether it was
+// pending.
+func (s *Scheduler) Cancel(id string) bool {
+	s.mu.Lock()
+	if timer, ok := s.timers[id]; ok {
+		timer.Stop()
+		delete(s.timers, id)
+	}
+	s.mu.Unlock()
+
+	for _, prefix := range []string{taskPrefix, queuePrefix} {
+		if s.StateManager.Get(prefix+id) != lua.LNil {
+			s.StateManager.Clear(prefix + id)
+			return true
+		}
+	}
+	return false
+}
+
+// Restore arms the saved tasks that aren't armed yet, such as those loaded
+// from a previous run. Tasks whose fire time passed while the bot was down
+// fire immediately, or are dropped when they were scheduled to skip missed
+// runs. Saved message queues are resumed as well. It must be called on the
+// Lua runner, after scripts registered their handlers.
+func (s *Scheduler) Restore() {
+	restored, skipped := 0, 0
+	for _, key := range s.StateManager.Keys(taskPrefix) {
+		id := strings.TrimPrefix(key, taskPrefix)
+
+		s.mu.Lock()
+		_, armed := s.timers[id]
+		s.mu.Unlock()
+		if armed {
+			continue
+		}
+
+		task, ok := s.StateManager.Get(key).(*lua.LTable)
+		if !ok {
+			s.StateManager.Clear(key)
+			continue
+		}
+
+		fireAt := savedFireAt(task)
+		delay := time.Until(fireAt)
+		if delay < 0 {
+			if lua.LVAsBool(task.RawGetString("skip_missed")) {
+				slog.Info("Skipping scheduled task missed while offline", "id", id, "handler", task.RawGetString("handler").String(), "fire_at", fireAt)
+				s.StateManager.Clear(key)
+				skipped++
+				continue
+			}
+			delay = 0
+		}
+
+		s.arm(id, delay, s.fire)
+		restored++
+	}
+
+	if restored > 0 || skipped > 0 {
+		slog.Info("Restored scheduled tasks", "restored", restored, "skipped", skipped)
+	}
+
+	s.restoreQueues()
+}
+
+// savedFireAt returns when a saved task or queued message is due. Times are
+// kept in milliseconds, so delays of a fraction of a second aren't lost.
+func savedFireAt(table *lua.LTable) time.Time {
+	return time.UnixMilli(int64(lua.LVAsNumber(table.RawGetString("fire_at_ms"))))
+}
+
+// arm starts the timer that fires a task or the next message of a queue.
+func (s *Scheduler) arm(id string, delay time.Duration, fire func(L *lua.LState, id string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timers[id] = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		delete(s.timers, id)
+		s.mu.Unlock()
+
+		utils.GetLuaRunner().Do(func(L *lua.LState) {
+			fire(L, id)
+		})
+	})
+}
+
+// fire removes a task from the state and calls its handler with the task's
+// arguments and ID. Tasks cancelled in the meantime are ignored.
+func (s *Scheduler) fire(L *lua.LState, id string) {
+	task, ok := s.StateManager.Get(taskPrefix + id).(*lua.LTable)
+	if !ok {
+		return
+	}
+	s.StateManager.Clear(taskPrefix + id)
+
+	handler := task.RawGetString("handler").String()
+	s.mu.Lock()
+	globalName, exists := s.handlers[handler]
+	s.mu.Unlock()
+	if !exists {
+		slog.Error("Scheduled task handler not registered", "id", id, "handler", handler)
+		return
+	}
+
+	if err := L.CallByParam(lua.P{
+		Fn:      L.GetGlobal(globalName),
+		NRet:    0,
+		Protect: true,
+	}, task.RawGetString("args"), lua.LString(id)); err != nil {
+		slog.Error("Error executing scheduled task handler", "id", id, "handler", handler, "error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"driftwood/internal/lua/utils"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestSavedFireTimes(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []time.Duration // When each task or message is due, after the script ran
+	}{
+		{
+			name:   "task in a quarter second",
+			script: `id = schedule("handler", 0.25)`,
+			want:   []time.Duration{250 * time.Millisecond},
+		},
+		{
+			name:   "task in one and a half seconds",
+			script: `id = schedule("handler", 1.5)`,
+			want:   []time.Duration{1500 * time.Millisecond},
+		},
+		{
+			name:   "queue at half second intervals",
+			script: `id = queue("1", {{content = "a", delay = 60}, "b", "c"}, {interval = 0.5})`,
+			want:   []time.Duration{60 * time.Second, 60500 * time.Millisecond, 61 * time.Second},
+		},
+		{
+			name:   "queue with fractional delays",
+			script: `id = queue("1", {{content = "a", delay = 60.2}, {content = "b", delay = 0.1}})`,
+			want:   []time.Duration{60200 * time.Millisecond, 60300 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := NewScheduler(utils.NewStateManager())
+			L := lua.NewState()
+			defer L.Close()
+			L.SetGlobal("schedule", L.NewFunction(NewScheduleBindingSchedule(scheduler).Register()))
+			L.SetGlobal("queue", L.NewFunction(NewScheduleBindingQueue(scheduler).Register()))
+
+			start := time.Now()
+			if err := L.DoString(tt.script); err != nil {
+				t.Fatal(err)
+			}
+			id := lua.LVAsString(L.GetGlobal("id"))
+			defer scheduler.Cancel(id)
+
+			var got []time.Time
+			if task, ok := scheduler.StateManager.Get(taskPrefix + id).(*lua.LTable); ok {
+				got = append(got, savedFireAt(task))
+			}
+			if queue, ok := scheduler.StateManager.Get(queuePrefix + id).(*lua.LTable); ok {
+				messages := queue.RawGetString("messages").(*lua.LTable)
+				for i := 1; i <= messages.Len(); i++ {
+					got = append(got, queuedFireAt(messages, i))
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("saved %d fire times, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if offset := got[i].Sub(start); offset < want-time.Millisecond || offset > want+50*time.Millisecond {
+					t.Errorf("fire time %d is %v after the script ran, want %v", i+1, offset, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// SnowflakeBindingTimestamp provides a Lua helper for decoding the creation
+// time encoded in a Discord snowflake ID.
+type SnowflakeBindingTimestamp struct{}
+
+// NewSnowflakeBindingTimestamp initializes a new snowflake timestamp instance.
+func NewSnowflakeBindingTimestamp() *SnowflakeBindingTimestamp {
+	slog.Debug("Creating new SnowflakeBindingTimestamp")
+	return &SnowflakeBindingTimestamp{}
+}
+
+// Name returns the name of the binding.
+func (b *SnowflakeBindingTimestamp) Name() string {
+	return "timestamp"
+}
+
+func (b *SnowflakeBindingTimestamp) SetSession(session *discordgo.Session) {}
+
+// Register registers the snowflake-related functions in the Lua state. The
+// function returns the Unix time in seconds, in milliseconds, and as an
+// RFC 3339 formatted UTC date.
+func (b *SnowflakeBindingTimestamp) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		id := L.CheckString(1)
+
+		created, err := discordgo.SnowflakeTimestamp(id)
+		if err != nil {
+			L.ArgError(1, "invalid snowflake, expected a numeric ID string")
+			return 0
+		}
+		created = created.UTC()
+
+		L.Push(lua.LNumber(created.Unix()))
+		L.Push(lua.LNumber(created.UnixMilli()))
+		L.Push(lua.LString(created.Format(time.RFC3339)))
+		return 3
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *SnowflakeBindingTimestamp) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *SnowflakeBindingTimestamp) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
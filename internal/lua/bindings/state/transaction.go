@@ -0,0 +1,87 @@
+package state
+
+import (
+	"driftwood/internal/lua/utils"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// StateBindingTransaction provides Lua bindings for grouping state changes.
+type StateBindingTransaction struct {
+	StateManager *utils.StateManager
+}
+
+// NewStateBindingTransaction initializes a new state management instance.
+func NewStateBindingTransaction(sm *utils.StateManager) *StateBindingTransaction {
+	slog.Debug("Creating new StateBindingTransaction")
+	return &StateBindingTransaction{
+		StateManager: sm,
+	}
+}
+
+// Name returns the name of the binding for global registration in Lua.
+func (b *StateBindingTransaction) Name() string {
+	return "transaction"
+}
+
+func (b *StateBindingTransaction) SetSession(session *discordgo.Session) {}
+
+// Register adds the state-related functions to the Lua state. The callback
+// receives a transaction table with get, set and clear methods. Its changes
+// are committed together when it returns, or discarded if it raises an error
+// or a value it read was changed in the meantime.
+func (b *StateBindingTransaction) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		fn := L.CheckFunction(1)
+
+		tx := b.StateManager.Begin()
+		txTable := L.NewTable()
+		txTable.RawSetString("get", L.NewFunction(func(L *lua.LState) int {
+			L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+			L.Push(tx.Get(L, L.CheckString(2)))
+			return 1
+		}))
+		txTable.RawSetString("set", L.NewFunction(func(L *lua.LState) int {
+			L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+			tx.Set(L.CheckString(2), L.CheckAny(3), L.OptInt(4, 0))
+			return 0
+		}))
+		txTable.RawSetString("clear", L.NewFunction(func(L *lua.LState) int {
+			L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+			tx.Clear(L.CheckString(2))
+			return 0
+		}))
+
+		if err := L.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    0,
+			Protect: true,
+		}, txTable); err != nil {
+			slog.Warn("State transaction rolled back", "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		if err := tx.Commit(); err != nil {
+			slog.Warn("State transaction rolled back", "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *StateBindingTransaction) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *StateBindingTransaction) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
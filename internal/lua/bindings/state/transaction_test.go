@@ -0,0 +1,113 @@
+package state
+
+import (
+	"driftwood/internal/lua/utils"
+	"reflect"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestTransaction(t *testing.T) {
+	tests := []struct {
+		name          string
+		script        string // Runs with the stored score table under "score"
+		wantCommitted bool
+		want          any
+	}{
+		{
+			name: "table changed in place in a failing callback",
+			script: `return transaction(function(tx)
+				local score = tx:get("score")
+				score.points = 99
+				score.history[1] = "changed"
+				table.insert(score.history, "added")
+				error("failed")
+			end)`,
+			want: map[string]any{"points": 1.0, "history": []any{"first"}},
+		},
+		{
+			name: "table set again in a failing callback",
+			script: `return transaction(function(tx)
+				local score = tx:get("score")
+				score.points = 99
+				tx:set("score", score)
+				error("failed")
+			end)`,
+			want: map[string]any{"points": 1.0, "history": []any{"first"}},
+		},
+		{
+			name: "table changed in place in a committed callback",
+			script: `return transaction(function(tx)
+				local score = tx:get("score")
+				score.points = score.points + 1
+				table.insert(score.history, "second")
+			end)`,
+			wantCommitted: true,
+			want:          map[string]any{"points": 2.0, "history": []any{"first", "second"}},
+		},
+		{
+			name: "reads see the same copy",
+			script: `return transaction(function(tx)
+				tx:get("score").points = 5
+				assert(tx:get("score").points == 5, "second read missed the first read's change")
+			end)`,
+			wantCommitted: true,
+			want:          map[string]any{"points": 5.0, "history": []any{"first"}},
+		},
+		{
+			name: "cleared in a failing callback",
+			script: `return transaction(function(tx)
+				tx:clear("score")
+				assert(tx:get("score") == nil)
+				error("failed")
+			end)`,
+			want: map[string]any{"points": 1.0, "history": []any{"first"}},
+		},
+		{
+			name: "value read was changed outside",
+			script: `return transaction(function(tx)
+				local score = tx:get("score")
+				outside_set("score", { points = 10, history = {} })
+				score.points = score.points + 1
+			end)`,
+			// An empty table has no sequence, so it converts to a map
+			want: map[string]any{"points": 10.0, "history": map[string]any{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := utils.NewStateManager()
+
+			L := lua.NewState()
+			defer L.Close()
+			L.SetGlobal("transaction", L.NewFunction(NewStateBindingTransaction(sm).Register()))
+			L.SetGlobal("outside_set", L.NewFunction(func(L *lua.LState) int {
+				sm.Set(L.CheckString(1), L.CheckTable(2), 0)
+				return 0
+			}))
+
+			if err := L.DoString(`return { points = 1, history = { "first" } }`); err != nil {
+				t.Fatal(err)
+			}
+			sm.Set("score", L.Get(-1), 0)
+			L.Pop(1)
+
+			if err := L.DoString(tt.script); err != nil {
+				t.Fatal(err)
+			}
+			if committed := lua.LVAsBool(L.Get(1)); committed != tt.wantCommitted {
+				t.Errorf("committed = %v, want %v (error: %v)", committed, tt.wantCommitted, L.Get(2))
+			}
+
+			got, err := utils.LuaToGo(sm.Get("score"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stored %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
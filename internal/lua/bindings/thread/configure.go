@@ -0,0 +1,139 @@
+package thread
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxSlowmodeSeconds is the longest per-user rate limit Discord allows (6 hours).
+const maxSlowmodeSeconds = 21600
+
+// archiveDurations lists the auto-archive durations (in minutes) Discord accepts.
+var archiveDurations = map[int]bool{
+	60:    true, // 1 hour
+	1440:  true, // 24 hours
+	4320:  true, // 3 days
+	10080: true, // 1 week
+}
+
+// ThreadBindingConfigure provides Lua bindings for configuring thread settings.
+type ThreadBindingConfigure struct {
+	Session *discordgo.Session
+}
+
+// NewThreadBindingConfigure initializes a new thread configure instance.
+func NewThreadBindingConfigure() *ThreadBindingConfigure {
+	slog.Debug("Creating new ThreadBindingConfigure")
+	return &ThreadBindingConfigure{}
+}
+
+// Name returns the name of the binding.
+func (b *ThreadBindingConfigure) Name() string {
+	return "configure"
+}
+
+func (b *ThreadBindingConfigure) SetSession(session *discordgo.Session) {
+	b.Session = session
+}
+
+// Register registers the thread-related functions in the Lua state.
+func (b *ThreadBindingConfigure) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		channelID := L.CheckString(1)
+		opts := L.CheckTable(2)
+
+		edit := &discordgo.ChannelEdit{}
+
+		if duration := opts.RawGetString("auto_archive_duration"); duration != lua.LNil {
+			number, ok := duration.(lua.LNumber)
+			if !ok {
+				L.ArgError(2, "options.auto_archive_duration must be a number")
+				return 0
+			}
+			minutes := int(number)
+			if number != lua.LNumber(minutes) || !archiveDurations[minutes] {
+				L.ArgError(2, "options.auto_archive_duration must be one of 60, 1440, 4320 or 10080 minutes")
+				return 0
+			}
+			edit.AutoArchiveDuration = minutes
+		}
+
+		if slowmode := opts.RawGetString("slowmode"); slowmode != lua.LNil {
+			number, ok := slowmode.(lua.LNumber)
+			if !ok {
+				L.ArgError(2, "options.slowmode must be a number")
+				return 0
+			}
+			seconds := int(number)
+			if number != lua.LNumber(seconds) || seconds < 0 || seconds > maxSlowmodeSeconds {
+				L.ArgError(2, fmt.Sprintf("options.slowmode must be a whole number of seconds between 0 and %d", maxSlowmodeSeconds))
+				return 0
+			}
+			edit.RateLimitPerUser = &seconds
+		}
+
+		// Checked in a fixed order, so the same field is reported every time
+		for _, field := range []struct {
+			key    string
+			target **bool
+		}{
+			{"locked", &edit.Locked},
+			{"archived", &edit.Archived},
+		} {
+			value := opts.RawGetString(field.key)
+			if value == lua.LNil {
+				continue
+			}
+			if value.Type() != lua.LTBool {
+				L.ArgError(2, fmt.Sprintf("options.%s must be a boolean", field.key))
+				return 0
+			}
+			flag := lua.LVAsBool(value)
+			*field.target = &flag
+		}
+
+		if edit.AutoArchiveDuration == 0 && edit.RateLimitPerUser == nil && edit.Locked == nil && edit.Archived == nil {
+			L.ArgError(2, "options must set auto_archive_duration, slowmode, locked or archived")
+			return 0
+		}
+
+		channel, err := b.Session.State.Channel(channelID)
+		if err != nil {
+			channel, err = b.Session.Channel(channelID)
+		}
+		if err != nil {
+			slog.Error("Failed to get thread", "channel_id", channelID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to get thread: %s", err.Error())))
+			return 2
+		}
+		if !channel.IsThread() {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString("channel is not a thread"))
+			return 2
+		}
+
+		if _, err := b.Session.ChannelEditComplex(channelID, edit); err != nil {
+			slog.Error("Failed to configure thread", "channel_id", channelID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to configure thread: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *ThreadBindingConfigure) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *ThreadBindingConfigure) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
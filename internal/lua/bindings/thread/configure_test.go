@@ -0,0 +1,124 @@
+package thread
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// threadDiscord answers every request with a thread and records the edits.
+type threadDiscord struct {
+	edits []map[string]any
+}
+
+func (f *threadDiscord) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPatch {
+		edit := map[string]any{}
+		if err := json.NewDecoder(req.Body).Decode(&edit); err != nil {
+			return nil, err
+		}
+		f.edits = append(f.edits, edit)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"1","type":11}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestConfigureNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  string
+		wantErr  string // Part of the argument error, empty when the thread is edited
+		wantEdit map[string]any
+	}{
+		{
+			name:     "archive duration",
+			options:  `{auto_archive_duration = 60}`,
+			wantEdit: map[string]any{"auto_archive_duration": 60.0},
+		},
+		{
+			name:    "fractional archive duration",
+			options: `{auto_archive_duration = 60.5}`,
+			wantErr: "options.auto_archive_duration must be one of",
+		},
+		{
+			name:    "unknown archive duration",
+			options: `{auto_archive_duration = 30}`,
+			wantErr: "options.auto_archive_duration must be one of",
+		},
+		{
+			name:     "slowmode",
+			options:  `{slowmode = 30}`,
+			wantEdit: map[string]any{"rate_limit_per_user": 30.0},
+		},
+		{
+			name:     "slowmode off",
+			options:  `{slowmode = 0}`,
+			wantEdit: map[string]any{"rate_limit_per_user": 0.0},
+		},
+		{
+			name:    "fractional slowmode",
+			options: `{slowmode = 30.5}`,
+			wantErr: "options.slowmode must be a whole number",
+		},
+		{
+			name:    "negative slowmode",
+			options: `{slowmode = -1}`,
+			wantErr: "options.slowmode must be a whole number",
+		},
+		{
+			name:    "slowmode too long",
+			options: `{slowmode = 21601}`,
+			wantErr: "options.slowmode must be a whole number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &threadDiscord{}
+			session, err := discordgo.New("Bot token")
+			if err != nil {
+				t.Fatal(err)
+			}
+			session.Client = &http.Client{Transport: fake}
+			binding := NewThreadBindingConfigure()
+			binding.SetSession(session)
+
+			L := lua.NewState()
+			defer L.Close()
+			L.SetGlobal("configure", L.NewFunction(binding.Register()))
+			err = L.DoString(`configure("1", ` + tt.options + `)`)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want one containing %q", err, tt.wantErr)
+				}
+				if len(fake.edits) != 0 {
+					t.Errorf("thread was edited despite the error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.edits) != 1 {
+				t.Fatalf("sent %d edits, want 1", len(fake.edits))
+			}
+			for key, want := range tt.wantEdit {
+				if got := fake.edits[0][key]; got != want {
+					t.Errorf("edit %s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package bindings
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// timestampStyles lists the styles Discord accepts in `<t:unix:style>` markup.
+const timestampStyles = "tTdDfFR"
+
+// TimeBindingFormat provides a Lua helper for Discord's dynamic timestamp markup.
+type TimeBindingFormat struct{}
+
+// NewTimeBindingFormat initializes a new time format instance.
+func NewTimeBindingFormat() *TimeBindingFormat {
+	slog.Debug("Creating new TimeBindingFormat")
+	return &TimeBindingFormat{}
+}
+
+// Name returns the name of the binding.
+func (b *TimeBindingFormat) Name() string {
+	return "format"
+}
+
+func (b *TimeBindingFormat) SetSession(session *discordgo.Session) {}
+
+// Register creates the `format` Lua function, which renders a Unix timestamp
+// as `<t:unix:style>` so each client shows it in the reader's own time zone.
+func (b *TimeBindingFormat) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		unix := L.CheckNumber(1)
+		style := L.OptString(2, "f")
+
+		if len(style) != 1 || !strings.Contains(timestampStyles, style) {
+			L.ArgError(2, fmt.Sprintf("invalid style '%s', expected one of t, T, d, D, f, F, R", style))
+			return 0
+		}
+
+		L.Push(lua.LString(fmt.Sprintf("<t:%d:%s>", int64(unix), style)))
+		return 1
+	}
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *TimeBindingFormat) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// This binding does not handle interactions
+	return nil
+}
+
+func (b *TimeBindingFormat) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -0,0 +1,79 @@
+package bindings
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// unknownCommandHandler is the Lua global holding the fallback handler.
+const unknownCommandHandler = "unknown_command_handler"
+
+// UnknownCommandMessage is the default reply to a command without a handler,
+// such as a removed command Discord still lists.
+const UnknownCommandMessage = "This command is not available right now."
+
+// UnknownCommandBinding manages the `on_unknown_command` Lua function, which
+// registers a fallback handler for commands that have no registered handler,
+// such as stale commands or commands routed by a dynamic dispatcher. Without
+// a fallback handler such commands get an ephemeral reply instead.
+type UnknownCommandBinding struct {
+	Handler string // Lua global handler name, empty until a fallback is registered
+	Message string // Reply used while no fallback handler is registered
+}
+
+// NewUnknownCommandBinding initializes a new UnknownCommandBinding.
+func NewUnknownCommandBinding() *UnknownCommandBinding {
+	slog.Debug("Creating new UnknownCommandBinding")
+	return &UnknownCommandBinding{
+		Message: UnknownCommandMessage,
+	}
+}
+
+// Name returns the name of the Lua function for this binding.
+func (b *UnknownCommandBinding) Name() string {
+	return "on_unknown_command"
+}
+
+func (b *UnknownCommandBinding) SetSession(session *discordgo.Session) {}
+
+// Register adds the `on_unknown_command` function to Lua. It takes either a
+// handler or the message to reply with. Registering again replaces the
+// previous fallback.
+func (b *UnknownCommandBinding) Register() lua.LGFunction {
+	return func(L *lua.LState) int {
+		if message, ok := L.Get(1).(lua.LString); ok {
+			L.SetGlobal(unknownCommandHandler, lua.LNil)
+			b.Handler = ""
+			b.Message = string(message)
+			slog.Info("Registered unknown command reply", "message", b.Message)
+			return 0
+		}
+
+		handler := L.CheckFunction(1) // First argument is the handler function
+
+		L.SetGlobal(unknownCommandHandler, handler)
+		b.Handler = unknownCommandHandler
+
+		slog.Info("Registered unknown command handler", "handler", unknownCommandHandler)
+		return 0
+	}
+}
+
+// Reset removes the fallback handler and restores the default reply.
+func (b *UnknownCommandBinding) Reset(L *lua.LState) {
+	L.SetGlobal(unknownCommandHandler, lua.LNil)
+	b.Handler = ""
+	b.Message = UnknownCommandMessage
+}
+
+// HandleInteraction is not applicable for this binding.
+func (b *UnknownCommandBinding) HandleInteraction(interaction *discordgo.InteractionCreate) error {
+	// The ApplicationCommandBinding calls the fallback when it finds no handler
+	return nil
+}
+
+func (b *UnknownCommandBinding) CanHandleInteraction(interaction *discordgo.InteractionCreate) bool {
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"time"
 
 	"log/slog"
@@ -13,10 +14,30 @@ import (
 	lua "github.com/yuin/gopher-lua"
 
 	"driftwood/internal/lua/bindings"
+	bindings_attachment "driftwood/internal/lua/bindings/attachment"
+	bindings_audit "driftwood/internal/lua/bindings/audit"
+	bindings_bot "driftwood/internal/lua/bindings/bot"
+	bindings_cache "driftwood/internal/lua/bindings/cache"
+	bindings_config "driftwood/internal/lua/bindings/config"
+	bindings_cooldown "driftwood/internal/lua/bindings/cooldown"
+	bindings_format "driftwood/internal/lua/bindings/format"
+	bindings_forum "driftwood/internal/lua/bindings/forum"
+	bindings_guild "driftwood/internal/lua/bindings/guild"
+	bindings_id "driftwood/internal/lua/bindings/id"
+	bindings_member "driftwood/internal/lua/bindings/member"
 	bindings_message "driftwood/internal/lua/bindings/message"
+	bindings_metrics "driftwood/internal/lua/bindings/metrics"
 	bindings_options "driftwood/internal/lua/bindings/options"
+	bindings_parse "driftwood/internal/lua/bindings/parse"
+	bindings_presence "driftwood/internal/lua/bindings/presence"
+	bindings_random "driftwood/internal/lua/bindings/random"
 	bindings_reaction "driftwood/internal/lua/bindings/reaction"
+	bindings_reactionrole "driftwood/internal/lua/bindings/reactionrole"
+	bindings_role "driftwood/internal/lua/bindings/role"
+	bindings_schedule "driftwood/internal/lua/bindings/schedule"
+	bindings_snowflake "driftwood/internal/lua/bindings/snowflake"
 	bindings_state "driftwood/internal/lua/bindings/state"
+	bindings_thread "driftwood/internal/lua/bindings/thread"
 
 	"driftwood/internal/lua/utils"
 )
@@ -36,20 +57,64 @@ var DiscordOptionTypes = map[string]int{
 	"option_attachment":       11,
 }
 
+// DiscordColors maps color names to embed color values, exposed as
+// `driftwood.color.<name>`. Includes Discord's brand palette.
+var DiscordColors = map[string]int{
+	"blurple":  0x5865F2,
+	"green":    0x57F287,
+	"yellow":   0xFEE75C,
+	"fuchsia":  0xEB459E,
+	"red":      0xED4245,
+	"white":    0xFFFFFF,
+	"black":    0x23272A,
+	"blue":     0x3498DB,
+	"orange":   0xE67E22,
+	"purple":   0x9B59B6,
+	"grey":     0x95A5A6,
+	"dark_red": 0x992D22,
+}
+
 // LuaManager handles loading and executing Lua scripts and binding them to Discord commands/events.
 type LuaManager struct {
 	Bindings     map[string][]bindings.LuaBinding
-	OnReadyCbs   []string
+	OnReady      *bindings.EventHandlers
 	StateManager *utils.StateManager
+	ConfigStore  *utils.ConfigStore
+	Metrics      *utils.Metrics
+	Audit        *utils.AuditLog
+	Cooldowns    *utils.Cooldowns
+	Cache        *utils.LRUCache
+	Owners       *utils.Owners
+	Commands     *bindings.ApplicationCommandBinding
+
+	ReactionRoles *bindings_reactionrole.ReactionRoles
+	Scheduler     *bindings_schedule.Scheduler
+
+	scriptsPath  string          // Directory the scripts were loaded from
+	baseModules  map[string]bool // Modules loaded before the scripts, kept on reload
+	interactions *utils.SeenSet  // Recently handled interaction IDs, to drop redeliveries
+	readyAt      time.Time       // When the bot first connected, only touched on the Lua runner
 }
 
 // NewManager creates a new LuaManager with the given session and Guild ID.
 func NewManager(session *discordgo.Session, guildID string) *LuaManager {
 	sm := utils.NewStateManager()
 	manager := &LuaManager{
-		StateManager: sm,
-		Bindings:     make(map[string][]bindings.LuaBinding),
-		OnReadyCbs:   make([]string, 0),
+		StateManager:  sm,
+		ConfigStore:   utils.NewConfigStore(sm, guildID),
+		Metrics:       utils.NewMetrics(),
+		Audit:         utils.NewAuditLog(),
+		Cooldowns:     utils.NewCooldowns(),
+		Cache:         utils.NewLRUCache(utils.DefaultCacheCapacity),
+		Owners:        utils.NewOwners(),
+		ReactionRoles: bindings_reactionrole.NewReactionRoles(sm),
+		Scheduler:     bindings_schedule.NewScheduler(sm),
+		Bindings:      make(map[string][]bindings.LuaBinding),
+		OnReady:       bindings.NewEventHandlers("on_ready"),
+
+		// A redelivered interaction can only be answered while its token is
+		// valid, so its ID needs remembering no longer than that
+		interactions: utils.NewSeenSet(utils.InteractionTokenLifetime),
 	}
 
 	manager.RegisterBindings(session, guildID)
@@ -61,9 +126,30 @@ func NewManager(session *discordgo.Session, guildID string) *LuaManager {
 
 // RegisterBindings initializes grouped Lua bindings.
 func (m *LuaManager) RegisterBindings(session *discordgo.Session, guildID string) {
+	middleware := bindings.NewMiddlewareBinding()
+	unknownCommand := bindings.NewUnknownCommandBinding()
+	presence := bindings_presence.NewPresence(guildID)
+	random := bindings_random.NewRandom()
+	commands := bindings.NewApplicationCommandBinding(guildID, middleware, unknownCommand, m.Metrics, m.Audit, m.Cooldowns, m.StateManager)
+	m.Commands = commands
+	awaitComponent := bindings.NewAwaitComponentBinding()
+
 	m.Bindings = map[string][]bindings.LuaBinding{
 		"default": {
-			bindings.NewApplicationCommandBinding(guildID),
+			bindings.NewReloadCommandBinding(commands, m.ReloadScripts), // Checked before the commands it sits alongside
+			commands,
+			bindings.NewCommandGroupBinding(commands),
+			middleware,
+			unknownCommand,
+			bindings.NewMessageEventBinding(),
+			bindings.NewReactionEventBinding(true),
+			bindings.NewReactionEventBinding(false),
+			bindings.NewEntitlementsBinding(),
+			bindings.NewIsOwnerBinding(m.Owners),
+			bindings.NewDryRunBinding(),
+			bindings.NewAwaitMessageBinding(),
+			awaitComponent, // Checked before InteractionEventBinding
+			bindings.NewConfirmBinding(awaitComponent),
 			bindings.NewInteractionEventBinding(),
 			bindings.NewNewButtonBinding(),
 			bindings.NewNewSelectMenuBinding(),
@@ -71,20 +157,39 @@ func (m *LuaManager) RegisterBindings(session *discordgo.Session, guildID string
 		},
 		"timer": {
 			bindings.NewRunAfterBinding(),
+			bindings_schedule.NewScheduleBindingHandler(m.Scheduler),
+			bindings_schedule.NewScheduleBindingSchedule(m.Scheduler),
+			bindings_schedule.NewScheduleBindingCancel(m.Scheduler),
+			bindings_schedule.NewScheduleBindingQueue(m.Scheduler),
 		},
 		"state": {
 			bindings_state.NewStateBindingGet(m.StateManager),
 			bindings_state.NewStateBindingSet(m.StateManager),
 			bindings_state.NewStateBindingClear(m.StateManager),
+			bindings_state.NewStateBindingTransaction(m.StateManager),
+		},
+		"config": {
+			bindings_config.NewConfigBindingGet(m.ConfigStore),
+			bindings_config.NewConfigBindingSet(m.ConfigStore),
 		},
 		"message": {
-			bindings_message.NewMessageBindingAdd(),
-			bindings_message.NewMessageBindingEdit(),
-			bindings_message.NewMessageBindingDelete(),
+			bindings_message.NewMessageBindingAdd(guildID),
+			bindings_message.NewMessageBindingReply(guildID),
+			bindings_message.NewMessageBindingEdit(guildID),
+			bindings_message.NewMessageBindingDelete(guildID),
+			bindings_message.NewMessageBindingPollResults(guildID),
+			bindings_message.NewMessageBindingGet(guildID),
+			bindings_message.NewMessageBindingHistory(guildID),
+			bindings_message.NewMessageBindingLink(),
 		},
 		"reaction": {
 			bindings_reaction.NewReactionBindingAdd(),
 			bindings_reaction.NewReactionBindingRemove(),
+			bindings_reaction.NewReactionBindingTally(),
+		},
+		"reaction_role": {
+			bindings_reactionrole.NewReactionRoleBindingBind(m.ReactionRoles),
+			bindings_reactionrole.NewReactionRoleBindingUnbind(m.ReactionRoles),
 		},
 		"option": {
 			bindings_options.NewNewOptionStringBinding(),
@@ -94,6 +199,84 @@ func (m *LuaManager) RegisterBindings(session *discordgo.Session, guildID string
 		"channel": {
 			bindings.NewChannelBindingGet(guildID),
 		},
+		"guild": {
+			bindings_guild.NewGuildBindingCounts(guildID),
+			bindings_guild.NewGuildBindingAuditLog(guildID),
+			bindings_guild.NewGuildBindingIconURL(guildID),
+			bindings_guild.NewGuildBindingBannerURL(guildID),
+		},
+		"member": {
+			bindings_member.NewMemberBindingSetNick(guildID),
+			bindings_member.NewMemberBindingAddRole(guildID),
+			bindings_member.NewMemberBindingRemoveRole(guildID),
+		},
+		"role": {
+			bindings_role.NewRoleBindingAssignAll(guildID),
+		},
+		"thread": {
+			bindings_thread.NewThreadBindingConfigure(),
+		},
+		"forum": {
+			bindings_forum.NewForumBindingCreatePost(),
+			bindings_forum.NewForumBindingTags(),
+		},
+		"snowflake": {
+			bindings_snowflake.NewSnowflakeBindingTimestamp(),
+		},
+		"time": {
+			bindings.NewTimeBindingFormat(),
+		},
+		"format": {
+			bindings_format.NewFormatBindingMentionUser(),
+			bindings_format.NewFormatBindingMentionChannel(),
+			bindings_format.NewFormatBindingMentionRole(),
+			bindings_format.NewFormatBindingCodeBlock(),
+			bindings_format.NewFormatBindingEscapeMarkdown(),
+		},
+		"attachment": {
+			bindings_attachment.NewAttachmentBindingDownload(),
+		},
+		"parse": {
+			bindings_parse.NewParseBindingCSV(),
+			bindings_parse.NewParseBindingJSON(),
+		},
+		"presence": {
+			bindings_presence.NewPresenceBindingSet(presence),
+			bindings_presence.NewPresenceBindingRotate(presence),
+		},
+		"command": {
+			bindings.NewCommandBindingDisable(commands),
+			bindings.NewCommandBindingEnable(commands),
+		},
+		"random": {
+			bindings_random.NewRandomBindingInt(random),
+			bindings_random.NewRandomBindingChoice(random),
+			bindings_random.NewRandomBindingShuffle(random),
+		},
+		"id": {
+			bindings_id.NewIDBindingNew(),
+		},
+		"bot": {
+			bindings_bot.NewBotBindingPing(),
+			bindings_bot.NewBotBindingUptime(func() time.Time { return m.readyAt }),
+		},
+		"cooldown": {
+			bindings_cooldown.NewCooldownBindingRemaining(m.Cooldowns),
+		},
+		"cache": {
+			bindings_cache.NewCacheBindingGet(m.Cache),
+			bindings_cache.NewCacheBindingSet(m.Cache),
+		},
+		"color": {
+			bindings.NewColorBindingRGB(),
+		},
+		"audit": {
+			bindings_audit.NewAuditBindingRecent(m.Audit),
+		},
+		"metrics": {
+			bindings_metrics.NewMetricsBindingCommands(m.Metrics),
+			bindings_metrics.NewMetricsBindingReset(m.Metrics),
+		},
 	}
 
 	slog.Info("Lua bindings registered successfully")
@@ -112,53 +295,122 @@ func (m *LuaManager) LoadScripts(path string) error {
 	if err != nil {
 		absPath = path // Fallback to relative path if absolute conversion fails.
 	}
+	m.scriptsPath = path
 
-	// Update `package.path` to include the new path.
+	// Update `package.path` to include the new path, and note the modules
+	// loaded before any script so a reload only forgets the scripts' own.
 	utils.GetLuaRunner().Do(func(L *lua.LState) {
 		packagePath := L.GetField(L.GetGlobal("package"), "path").String()
 		newPath := filepath.Join(absPath, "?.lua")
 		L.SetField(L.GetGlobal("package"), "path", lua.LString(packagePath+";"+newPath))
+
+		m.baseModules = make(map[string]bool)
+		L.GetField(L.GetGlobal("package"), "loaded").(*lua.LTable).ForEach(func(key, _ lua.LValue) {
+			m.baseModules[key.String()] = true
+		})
 	})
 
-	// Walk through the directory and load each Lua script
-	err = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+	scripts, err := scriptPaths(path)
+	if err != nil {
+		return err
+	}
+
+	for _, script := range scripts {
+		slog.Debug("Loading Lua script", "path", script)
+		utils.GetLuaRunner().Do(func(L *lua.LState) {
+			if loadErr := L.DoFile(script); loadErr != nil {
+				slog.Error("Failed to load Lua script", "path", script, "error", loadErr)
+			}
+		})
+	}
+
+	// The scripts only run on the Lua runner, so wait for them before
+	// anything asks what they registered, such as the intents they need
+	utils.GetLuaRunner().Wait()
+
+	slog.Info("Lua scripts loaded successfully")
+	return nil
+}
+
+// scriptPaths lists the scripts in the directory in load order. Directories
+// with an `init.lua` are modules loaded through that file, and every other
+// `.lua` file is a single-file script.
+func scriptPaths(path string) ([]string, error) {
+	var scripts []string
+
+	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("error walking through Lua scripts: %w", err)
 		}
 
-		// Skip directories that are not the entry point (init.lua).
 		if info.IsDir() {
 			initFilePath := filepath.Join(path, "init.lua")
 			if _, err := os.Stat(initFilePath); err == nil {
-				slog.Debug("Loading Lua module", "path", initFilePath)
-				utils.GetLuaRunner().Do(func(L *lua.LState) {
-					if loadErr := L.DoFile(initFilePath); loadErr != nil {
-						slog.Error("Failed to load Lua module", "path", initFilePath, "error", loadErr)
-					}
-				})
+				scripts = append(scripts, initFilePath)
 			}
 			return nil
 		}
 
-		// Load single-file commands.
 		if filepath.Ext(path) == ".lua" && info.Name() != "init.lua" {
-			slog.Debug("Loading Lua script", "path", path)
-			utils.GetLuaRunner().Do(func(L *lua.LState) {
-				if loadErr := L.DoFile(path); loadErr != nil {
-					slog.Error("Failed to load Lua script", "path", path, "error", loadErr)
-				}
-			})
+			scripts = append(scripts, path)
 		}
-
 		return nil
 	})
 
-	if err != nil {
-		return err
-	}
+	return scripts, err
+}
 
-	slog.Info("Lua scripts loaded successfully")
-	return nil
+// ReloadScripts drops every handler the scripts registered and runs the
+// scripts again in the same Lua state, so state, config and pending timers
+// survive. Modules the scripts `require` are loaded afresh, and the commands
+// are synced once every script has run. `on_ready` handlers are not called
+// again, as the bot is already connected. done is called on the Lua runner
+// with the outcome of each script.
+func (m *LuaManager) ReloadScripts(done func(results []bindings.ScriptResult)) {
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		slog.Info("Reloading Lua scripts", "path", m.scriptsPath)
+
+		for groupIdx := range m.Bindings {
+			for idx := range m.Bindings[groupIdx] {
+				if binding, ok := m.Bindings[groupIdx][idx].(bindings.ResettableBinding); ok {
+					binding.Reset(L)
+				}
+			}
+		}
+		m.OnReady.Reset(L)
+
+		loaded := L.GetField(L.GetGlobal("package"), "loaded").(*lua.LTable)
+		var stale []lua.LValue
+		loaded.ForEach(func(key, _ lua.LValue) {
+			if !m.baseModules[key.String()] && key.String() != "driftwood" {
+				stale = append(stale, key)
+			}
+		})
+		for _, key := range stale {
+			loaded.RawSet(key, lua.LNil)
+		}
+
+		var results []bindings.ScriptResult
+		scripts, err := scriptPaths(m.scriptsPath)
+		if err != nil {
+			slog.Error("Failed to list Lua scripts", "path", m.scriptsPath, "error", err)
+			results = append(results, bindings.ScriptResult{Path: m.scriptsPath, Err: err})
+		}
+		for _, script := range scripts {
+			result := bindings.ScriptResult{Path: script, Err: L.DoFile(script)}
+			if rel, err := filepath.Rel(m.scriptsPath, script); err == nil {
+				result.Path = rel
+			}
+			if result.Err != nil {
+				slog.Error("Failed to reload Lua script", "path", script, "error", result.Err)
+			}
+			results = append(results, result)
+		}
+
+		m.Commands.FinishReload()
+		slog.Info("Lua scripts reloaded", "scripts", len(results))
+		done(results)
+	})
 }
 
 // RegisterDiscordModule creates a custom loader for `require("driftwood")`
@@ -197,6 +449,12 @@ func (m *LuaManager) RegisterDiscordModule() {
 				slog.Info("Registered binding", "name", fmt.Sprintf("%s.%s", groupName, binding.Name()))
 			}
 
+			if groupName == "color" {
+				for key, value := range DiscordColors {
+					subTable.RawSetString(key, lua.LNumber(value))
+				}
+			}
+
 			L.SetField(module, groupName, subTable)
 		}
 
@@ -217,12 +475,9 @@ func (m *LuaManager) addReady(L *lua.LState, module *lua.LTable) {
 	L.SetField(module, "on_ready", L.NewFunction(func(L *lua.LState) int {
 		handler := L.CheckFunction(1) // First argument is the handler function
 
-		// Create a global function name for the handler
-		globalName := fmt.Sprintf("on_ready_handler_%d", time.Now().Unix())
-
-		// Set the Lua function as a global
-		L.SetGlobal(globalName, handler)
-		m.OnReadyCbs = append(m.OnReadyCbs, globalName)
+		// Handlers are numbered in registration order, so handlers
+		// registered by several modules never share a global
+		m.OnReady.Add(L, handler)
 		return 0
 	}))
 }
@@ -247,7 +502,24 @@ func addLogging(L *lua.LState, module *lua.LTable) {
 	L.SetField(module, "log", logTable)
 }
 
+// recoverEvent stops a panic while dispatching a gateway event from taking
+// down the bot, so the next event is handled as usual.
+func recoverEvent(event string) {
+	if rcv := recover(); rcv != nil {
+		slog.Error("Recovered from panic while dispatching event", "event", event, "panic", rcv, "stack", string(debug.Stack()))
+	}
+}
+
 func (m *LuaManager) HandleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	defer recoverEvent("interaction")
+
+	// Discord can deliver the same interaction twice around gateway
+	// reconnects, which must not run a command twice
+	if m.interactions.Seen(i.ID) {
+		slog.Warn("Dropping redelivered interaction", "interaction_id", i.ID)
+		return
+	}
+
 	// Route the command to the ApplicationCommandBinding.
 	for groupIdx := range m.Bindings {
 		for idx := range m.Bindings[groupIdx] {
@@ -268,27 +540,111 @@ func (m *LuaManager) HandleCommand(s *discordgo.Session, i *discordgo.Interactio
 }
 
 func (m *LuaManager) ReadyHandler(s *discordgo.Session, r *discordgo.Ready) {
+	defer recoverEvent("ready")
 	slog.Info("Handling ready event")
 	m.setSession(s)
-	for _, cb := range m.OnReadyCbs {
 
-		utils.GetLuaRunner().Do(func(L *lua.LState) {
-			fn := L.GetGlobal(cb)
-			if fn == lua.LNil {
-				slog.Error("Lua on_ready handler not found", "handler", cb)
-				return
+	// Fetched before the on_ready handlers run, so they can check owners.
+	// Reconnects fetch again, picking up changes to the team
+	if err := m.Owners.Load(s); err != nil {
+		slog.Error("Failed to fetch application owners", "error", err)
+	} else {
+		slog.Info("Fetched application owners", "count", m.Owners.Len())
+	}
+
+	// Scripts have registered their task handlers by now, so tasks saved
+	// before a restart can be rescheduled. Reconnects fire ready again, but
+	// the uptime counts from the first connection.
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		if m.readyAt.IsZero() {
+			m.readyAt = time.Now()
+		}
+		m.Scheduler.Restore()
+	})
+
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		m.OnReady.Call(L, nil)
+	})
+}
+
+// RequiredIntents collects the gateway intents needed by the bindings the
+// loaded scripts use.
+func (m *LuaManager) RequiredIntents() discordgo.Intent {
+	intents := discordgo.IntentsNone
+	for groupIdx := range m.Bindings {
+		for idx := range m.Bindings[groupIdx] {
+			if binding, ok := m.Bindings[groupIdx][idx].(bindings.IntentBinding); ok {
+				intents |= binding.RequiredIntents()
 			}
+		}
+	}
+	return intents
+}
 
-			err := L.CallByParam(lua.P{
-				Fn:      fn,
-				NRet:    0,
-				Protect: true,
-			})
-			if err != nil {
-				slog.Error("Error executing Lua on_ready handler", "error", err)
+// MessageCreateHandler passes new messages to every binding that reacts to messages.
+func (m *LuaManager) MessageCreateHandler(s *discordgo.Session, msg *discordgo.MessageCreate) {
+	defer recoverEvent("message_create")
+	if s.State.User != nil && msg.Author != nil && msg.Author.ID == s.State.User.ID {
+		return // Ignore the bot's own messages
+	}
+
+	for groupIdx := range m.Bindings {
+		for idx := range m.Bindings[groupIdx] {
+			if handler, ok := m.Bindings[groupIdx][idx].(bindings.MessageHandler); ok {
+				handler.HandleMessage(msg)
 			}
-		})
+		}
+	}
+}
 
+// GuildCreateHandler registers the commands in each guild the bot is in when
+// registering in every guild, including guilds it joins while running.
+func (m *LuaManager) GuildCreateHandler(s *discordgo.Session, g *discordgo.GuildCreate) {
+	defer recoverEvent("guild_create")
+	utils.GetLuaRunner().Do(func(L *lua.LState) {
+		m.Commands.AddGuild(g.ID)
+	})
+}
+
+// GuildDeleteHandler stops registering commands in a guild the bot has left.
+// Guilds that are only unavailable during an outage are kept.
+func (m *LuaManager) GuildDeleteHandler(s *discordgo.Session, g *discordgo.GuildDelete) {
+	defer recoverEvent("guild_delete")
+	if g.Unavailable {
+		return
+	}
+	m.Commands.RemoveGuild(g.ID)
+}
+
+// ReactionAddHandler grants reaction roles when a member reacts to a message,
+// and passes the reaction to every binding that reacts to reactions.
+func (m *LuaManager) ReactionAddHandler(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	defer recoverEvent("reaction_add")
+	m.ReactionRoles.HandleReactionAdd(s, r.MessageReaction)
+	m.dispatchReaction(s, r.MessageReaction, true)
+}
+
+// ReactionRemoveHandler removes reaction roles when a member removes their
+// reaction, and passes the removal to every binding that reacts to reactions.
+func (m *LuaManager) ReactionRemoveHandler(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
+	defer recoverEvent("reaction_remove")
+	m.ReactionRoles.HandleReactionRemove(s, r.MessageReaction)
+	m.dispatchReaction(s, r.MessageReaction, false)
+}
+
+// dispatchReaction passes a reaction to the reaction handling bindings,
+// ignoring the bot's own.
+func (m *LuaManager) dispatchReaction(s *discordgo.Session, r *discordgo.MessageReaction, added bool) {
+	if s.State.User != nil && r.UserID == s.State.User.ID {
+		return
+	}
+
+	for groupIdx := range m.Bindings {
+		for idx := range m.Bindings[groupIdx] {
+			if handler, ok := m.Bindings[groupIdx][idx].(bindings.ReactionHandler); ok {
+				handler.HandleReaction(r, added)
+			}
+		}
 	}
 }
 
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// AllowedMentions returns the mentions an interaction response may ping.
+// Users and roles are pinged as usual, but @everyone and @here are suppressed
+// unless allowEveryone is set, so a command echoing user input can't ping
+// the whole server.
+func AllowedMentions(allowEveryone bool) *discordgo.MessageAllowedMentions {
+	parse := []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeUsers, discordgo.AllowedMentionTypeRoles}
+	if allowEveryone {
+		parse = append(parse, discordgo.AllowedMentionTypeEveryone)
+	}
+	return &discordgo.MessageAllowedMentions{Parse: parse}
+}
+
+// parseAllowEveryone reads the "allow_everyone" flag from the options of a
+// response, raising an argument error at arg when it isn't a boolean.
+func parseAllowEveryone(L *lua.LState, options *lua.LTable, arg int) bool {
+	if options == nil || options.RawGetString("allow_everyone") == lua.LNil {
+		return false
+	}
+	if options.RawGetString("allow_everyone").Type() != lua.LTBool {
+		L.ArgError(arg, "'allow_everyone' in options must be a boolean")
+		return false
+	}
+	return lua.LVAsBool(options.RawGetString("allow_everyone"))
+}
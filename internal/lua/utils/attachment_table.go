@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PrepareAttachmentTable converts a Discord attachment into a Lua table.
+func PrepareAttachmentTable(L *lua.LState, attachment *discordgo.MessageAttachment) *lua.LTable {
+	attachmentTable := L.NewTable()
+	attachmentTable.RawSetString("id", lua.LString(attachment.ID))
+	attachmentTable.RawSetString("url", lua.LString(attachment.URL))
+	attachmentTable.RawSetString("filename", lua.LString(attachment.Filename))
+	attachmentTable.RawSetString("content_type", lua.LString(attachment.ContentType))
+	attachmentTable.RawSetString("size", lua.LNumber(attachment.Size))
+	return attachmentTable
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxAuditEntries is how many command invocations the audit log keeps.
+const MaxAuditEntries = 500
+
+// AuditEntry records a single command invocation.
+type AuditEntry struct {
+	Command   string    // Command name, with subcommands as `command_subcommand`
+	UserID    string    // User who ran the command
+	ChannelID string    // Channel the command was run in
+	At        time.Time // Time the command was run
+	Success   bool      // Whether the handler completed without raising an error
+}
+
+// AuditLog is a thread-safe, fixed-size history of recent command
+// invocations. Entries can also be posted to a log channel as they happen.
+type AuditLog struct {
+	mu        sync.Mutex
+	entries   []AuditEntry // Oldest first
+	channelID string       // Channel entries are posted to, empty to only keep them in memory
+}
+
+// NewAuditLog initializes an empty audit log.
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+// SetChannel sets the channel every recorded invocation is posted to. An
+// empty ID stops posting.
+func (a *AuditLog) SetChannel(channelID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.channelID = channelID
+}
+
+// Record adds an invocation to the log, dropping the oldest entry once the
+// log is full, and posts it to the log channel if one is set.
+func (a *AuditLog) Record(session *discordgo.Session, entry AuditEntry) {
+	a.mu.Lock()
+	a.entries = append(a.entries, entry)
+	if len(a.entries) > MaxAuditEntries {
+		a.entries = a.entries[len(a.entries)-MaxAuditEntries:]
+	}
+	channelID := a.channelID
+	a.mu.Unlock()
+
+	if channelID == "" || session == nil {
+		return
+	}
+
+	// Posting happens off the Lua runner so a slow request doesn't hold up handlers
+	go func() {
+		outcome := "succeeded"
+		if !entry.Success {
+			outcome = "failed"
+		}
+		content := fmt.Sprintf("`/%s` by <@%s> in <#%s> %s <t:%d:R>", entry.Command, entry.UserID, entry.ChannelID, outcome, entry.At.Unix())
+		if _, err := session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:         content,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		}); err != nil {
+			slog.Error("Failed to post audit log entry", "channel_id", channelID, "error", err)
+		}
+	}()
+}
+
+// Recent returns up to n of the most recent invocations, newest first.
+func (a *AuditLog) Recent(n int) []AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	n = min(n, len(a.entries))
+	recent := make([]AuditEntry, 0, n)
+	for idx := len(a.entries) - 1; idx >= len(a.entries)-n; idx-- {
+		recent = append(recent, a.entries[idx])
+	}
+	return recent
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"log/slog"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Limits Discord enforces on autocomplete responses.
+const (
+	MaxAutocompleteChoices     = 25
+	MaxChoiceNameLength        = 100
+	MaxChoiceStringValueLength = 100
+)
+
+// ParseAutocompleteChoices converts the choices returned by an autocomplete
+// handler into a response Discord accepts. Each choice is either a string,
+// used as both name and value, or a table with a "name" and a "value".
+// Rather than letting Discord reject the whole response, names and string
+// values that are too long are truncated, values are coerced to the option
+// type, and choices beyond the first 25 are dropped, each with a warning.
+// Choices whose value can't be coerced are skipped.
+func ParseAutocompleteChoices(command string, optionType discordgo.ApplicationCommandOptionType, value lua.LValue) []*discordgo.ApplicationCommandOptionChoice {
+	choicesTable, ok := value.(*lua.LTable)
+	if !ok {
+		if value != lua.LNil {
+			slog.Warn("Autocomplete handler must return an array of choices", "command", command, "type", value.Type().String())
+		}
+		return []*discordgo.ApplicationCommandOptionChoice{}
+	}
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	choicesTable.ForEach(func(_, entry lua.LValue) {
+		name, rawValue := entry, entry
+		if entryTable, ok := entry.(*lua.LTable); ok {
+			name, rawValue = entryTable.RawGetString("name"), entryTable.RawGetString("value")
+			if rawValue == lua.LNil {
+				rawValue = name
+			}
+		}
+
+		choiceName := truncateChoiceText(command, "name", lua.LVAsString(name), MaxChoiceNameLength)
+		if choiceName == "" {
+			slog.Warn("Skipping autocomplete choice without a name", "command", command)
+			return
+		}
+
+		choiceValue, ok := coerceChoiceValue(command, optionType, rawValue)
+		if !ok {
+			slog.Warn("Skipping autocomplete choice with a value of the wrong type", "command", command, "name", choiceName, "type", rawValue.Type().String())
+			return
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: choiceName, Value: choiceValue})
+	})
+
+	if len(choices) > MaxAutocompleteChoices {
+		slog.Warn("Autocomplete returned too many choices, keeping the first ones", "command", command, "count", len(choices), "max", MaxAutocompleteChoices)
+		choices = choices[:MaxAutocompleteChoices]
+	}
+	return choices
+}
+
+// coerceChoiceValue converts a choice value to the type of the option it
+// completes, accepting numbers for string options and numeric strings for
+// number options.
+func coerceChoiceValue(command string, optionType discordgo.ApplicationCommandOptionType, value lua.LValue) (any, bool) {
+	switch optionType {
+	case discordgo.ApplicationCommandOptionInteger:
+		number, ok := choiceNumber(value)
+		return int64(number), ok
+	case discordgo.ApplicationCommandOptionNumber:
+		return choiceNumber(value)
+	default:
+		switch value.Type() {
+		case lua.LTString, lua.LTNumber:
+			return truncateChoiceText(command, "value", lua.LVAsString(value), MaxChoiceStringValueLength), true
+		}
+		return nil, false
+	}
+}
+
+// choiceNumber reads a number, or a string holding one.
+func choiceNumber(value lua.LValue) (float64, bool) {
+	switch v := value.(type) {
+	case lua.LNumber:
+		return float64(v), true
+	case lua.LString:
+		number, err := strconv.ParseFloat(string(v), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// truncateChoiceText shortens text to the limit, logging when it does.
+func truncateChoiceText(command, field, text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	slog.Warn("Truncating autocomplete choice", "command", command, "field", field, "length", utf8.RuneCountInString(text), "max", limit)
+	runes := []rune(text)
+	return string(runes[:limit])
+}
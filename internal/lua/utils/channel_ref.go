@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ResolveChannel returns the ID of a channel given as either its ID or its
+// name, optionally prefixed with "#". Names are looked up in the guild of the
+// interaction being handled, or guildID outside of interactions, using the
+// cached channel list when the guild is cached. A name shared by several
+// channels is an error rather than a guess.
+func ResolveChannel(session *discordgo.Session, guildID, ref string) (string, error) {
+	if isSnowflake(ref) {
+		return ref, nil
+	}
+	name := strings.TrimPrefix(ref, "#")
+	if name == "" {
+		return "", fmt.Errorf("channel name must not be empty")
+	}
+
+	if current := GetLuaRunner().CurrentGuild(); current != "" {
+		guildID = current
+	}
+
+	var channels []*discordgo.Channel
+	if guild, err := session.State.Guild(guildID); err == nil && len(guild.Channels) > 0 {
+		channels = guild.Channels
+	} else {
+		channels, err = session.GuildChannels(guildID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get channels: %w", err)
+		}
+	}
+
+	var matches []string
+	for _, channel := range channels {
+		if channel.Name == name {
+			matches = append(matches, channel.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no channel is named '%s'", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d channels are named '%s', use an ID instead: %s", len(matches), name, strings.Join(matches, ", "))
+	}
+}
+
+// isSnowflake reports whether s looks like a Discord ID rather than a name.
+func isSnowflake(s string) bool {
+	if len(s) < 15 || len(s) > 20 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ChannelTypeNames maps Discord channel types to the names exposed to Lua.
+var ChannelTypeNames = map[discordgo.ChannelType]string{
+	discordgo.ChannelTypeGuildText:          "text",
+	discordgo.ChannelTypeDM:                 "dm",
+	discordgo.ChannelTypeGuildVoice:         "voice",
+	discordgo.ChannelTypeGroupDM:            "group_dm",
+	discordgo.ChannelTypeGuildCategory:      "category",
+	discordgo.ChannelTypeGuildNews:          "news",
+	discordgo.ChannelTypeGuildNewsThread:    "news_thread",
+	discordgo.ChannelTypeGuildPublicThread:  "public_thread",
+	discordgo.ChannelTypeGuildPrivateThread: "private_thread",
+	discordgo.ChannelTypeGuildStageVoice:    "stage",
+	discordgo.ChannelTypeGuildDirectory:     "directory",
+	discordgo.ChannelTypeGuildForum:         "forum",
+	discordgo.ChannelTypeGuildMedia:         "media",
+}
+
+// InteractionChannel looks up the channel an interaction was used in,
+// preferring the session's state cache over a REST request. Returns nil if
+// the channel can't be found.
+func InteractionChannel(session *discordgo.Session, interaction *discordgo.InteractionCreate) *discordgo.Channel {
+	if session == nil || interaction.ChannelID == "" {
+		return nil
+	}
+
+	if channel, err := session.State.Channel(interaction.ChannelID); err == nil {
+		return channel
+	}
+
+	channel, err := session.Channel(interaction.ChannelID)
+	if err != nil {
+		slog.Warn("Failed to look up interaction channel", "channel_id", interaction.ChannelID, "error", err)
+		return nil
+	}
+	return channel
+}
+
+// InteractionChannelType names the type of channel an interaction was used
+// in, such as "text", "public_thread" or "dm". Interactions outside a guild
+// are always "dm", and "unknown" is returned when the channel can't be found.
+func InteractionChannelType(session *discordgo.Session, interaction *discordgo.InteractionCreate) string {
+	if interaction.GuildID == "" {
+		return "dm"
+	}
+
+	channel := InteractionChannel(session, interaction)
+	if channel == nil {
+		return "unknown"
+	}
+	if name, ok := ChannelTypeNames[channel.Type]; ok {
+		return name
+	}
+	return "unknown"
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PrepareComponentsTable converts a message's components into a Lua array,
+// using the same shape `ParseComponents` accepts. Action rows are flattened,
+// so components appear in the order they are displayed.
+func PrepareComponentsTable(L *lua.LState, components []discordgo.MessageComponent) *lua.LTable {
+	componentsTable := L.NewTable()
+	appendComponents(L, componentsTable, components)
+	return componentsTable
+}
+
+// appendComponents adds the buttons, selects, text displays and containers
+// among components to the table, descending into action rows.
+func appendComponents(L *lua.LState, componentsTable *lua.LTable, components []discordgo.MessageComponent) {
+	for _, component := range components {
+		switch c := component.(type) {
+		case *discordgo.ActionsRow:
+			appendComponents(L, componentsTable, c.Components)
+		case *discordgo.Button:
+			buttonTable := L.NewTable()
+			buttonTable.RawSetString("type", lua.LString("button"))
+			buttonTable.RawSetString("label", lua.LString(c.Label))
+			buttonTable.RawSetString("custom_id", lua.LString(c.CustomID))
+			buttonTable.RawSetString("disabled", lua.LBool(c.Disabled))
+			componentsTable.Append(buttonTable)
+		case *discordgo.SelectMenu:
+			componentsTable.Append(prepareSelectMenuTable(L, c))
+		case *discordgo.TextDisplay:
+			textTable := L.NewTable()
+			textTable.RawSetString("type", lua.LString("text_display"))
+			textTable.RawSetString("content", lua.LString(c.Content))
+			componentsTable.Append(textTable)
+		case *discordgo.Container:
+			containerTable := L.NewTable()
+			containerTable.RawSetString("type", lua.LString("container"))
+			containerTable.RawSetString("components", PrepareComponentsTable(L, c.Components))
+			containerTable.RawSetString("spoiler", lua.LBool(c.Spoiler))
+			if c.AccentColor != nil {
+				containerTable.RawSetString("accent_color", lua.LNumber(*c.AccentColor))
+			}
+			componentsTable.Append(containerTable)
+		}
+	}
+}
+
+// prepareSelectMenuTable converts a select menu into a Lua table.
+func prepareSelectMenuTable(L *lua.LState, menu *discordgo.SelectMenu) *lua.LTable {
+	menuTable := L.NewTable()
+	menuTable.RawSetString("type", lua.LString("select"))
+	menuTable.RawSetString("placeholder", lua.LString(menu.Placeholder))
+	menuTable.RawSetString("custom_id", lua.LString(menu.CustomID))
+	menuTable.RawSetString("disabled", lua.LBool(menu.Disabled))
+	for name, menuType := range selectMenuTypes {
+		if menuType == menu.MenuType {
+			menuTable.RawSetString("menu_type", lua.LString(name))
+		}
+	}
+	if menu.MinValues != nil {
+		menuTable.RawSetString("min_values", lua.LNumber(*menu.MinValues))
+	}
+	if menu.MaxValues != 0 {
+		menuTable.RawSetString("max_values", lua.LNumber(menu.MaxValues))
+	}
+
+	if menu.MenuType == discordgo.StringSelectMenu {
+		optionsTable := L.NewTable()
+		for _, option := range menu.Options {
+			optionTable := L.NewTable()
+			optionTable.RawSetString("label", lua.LString(option.Label))
+			optionTable.RawSetString("value", lua.LString(option.Value))
+			optionTable.RawSetString("default", lua.LBool(option.Default))
+			if option.Description != "" {
+				optionTable.RawSetString("description", lua.LString(option.Description))
+			}
+			if option.Emoji != nil {
+				optionTable.RawSetString("emoji", lua.LString(FormatComponentEmoji(option.Emoji)))
+			}
+			optionsTable.Append(optionTable)
+		}
+		menuTable.RawSetString("options", optionsTable)
+	} else {
+		defaultsTable := L.NewTable()
+		for _, value := range menu.DefaultValues {
+			defaultTable := L.NewTable()
+			defaultTable.RawSetString("id", lua.LString(value.ID))
+			defaultTable.RawSetString("type", lua.LString(value.Type))
+			defaultsTable.Append(defaultTable)
+		}
+		menuTable.RawSetString("default_values", defaultsTable)
+	}
+
+	return menuTable
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ConfigStore provides per-guild settings on top of the StateManager. Keys
+// are namespaced by guild ID, so each server gets its own configuration.
+type ConfigStore struct {
+	state        *StateManager
+	defaultGuild string // Used when no interaction guild is available
+}
+
+// NewConfigStore initializes a new ConfigStore. The default guild is used
+// outside interactions (e.g. on_ready or timers) and in single-guild setups.
+func NewConfigStore(sm *StateManager, defaultGuild string) *ConfigStore {
+	return &ConfigStore{
+		state:        sm,
+		defaultGuild: defaultGuild,
+	}
+}
+
+// ResolveGuild returns the guild to scope a lookup to: the explicit guild if
+// given, otherwise the guild of the current interaction, otherwise the
+// default guild.
+func (cs *ConfigStore) ResolveGuild(guildID string) string {
+	if guildID != "" {
+		return guildID
+	}
+	if current := GetLuaRunner().CurrentGuild(); current != "" {
+		return current
+	}
+	return cs.defaultGuild
+}
+
+// Get retrieves a configuration value for a guild.
+func (cs *ConfigStore) Get(guildID, key string) lua.LValue {
+	return cs.state.Get(cs.stateKey(guildID, key))
+}
+
+// Set stores a configuration value for a guild. Configuration never expires.
+func (cs *ConfigStore) Set(guildID, key string, value lua.LValue) {
+	if value == lua.LNil {
+		cs.state.Clear(cs.stateKey(guildID, key))
+		return
+	}
+	cs.state.Set(cs.stateKey(guildID, key), value, 0)
+}
+
+// stateKey namespaces a configuration key by guild.
+func (cs *ConfigStore) stateKey(guildID, key string) string {
+	return fmt.Sprintf("__config:%s:%s", guildID, key)
+}
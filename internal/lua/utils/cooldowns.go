@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Cooldowns is a thread-safe tracker of when cooldowns expire. Each cooldown
+// is identified by a name, such as a command, and a scope ID, such as the
+// user it applies to.
+type Cooldowns struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// NewCooldowns initializes an empty cooldown tracker.
+func NewCooldowns() *Cooldowns {
+	return &Cooldowns{
+		until: make(map[string]time.Time),
+	}
+}
+
+// Start puts a cooldown in place for the given duration, replacing any
+// cooldown already running under the same name and scope.
+func (c *Cooldowns) Start(name, scopeID string, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Expired cooldowns are dropped here so the map doesn't grow forever
+	now := time.Now()
+	for key, until := range c.until {
+		if !now.Before(until) {
+			delete(c.until, key)
+		}
+	}
+	c.until[cooldownKey(name, scopeID)] = now.Add(duration)
+}
+
+// Remaining returns how long until a cooldown expires, 0 when it has
+// expired or was never started.
+func (c *Cooldowns) Remaining(name, scopeID string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	until, exists := c.until[cooldownKey(name, scopeID)]
+	if !exists {
+		return 0
+	}
+	return max(time.Until(until), 0)
+}
+
+// cooldownKey joins the name and scope of a cooldown into its map key.
+func cooldownKey(name, scopeID string) string {
+	return name + "/" + scopeID
+}
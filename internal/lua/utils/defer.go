@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DeferFunction returns a Lua function that acknowledges an interaction with
+// a deferred "thinking" response. The handler then has up to 15 minutes to
+// fill it in with reply, edit_response or followup.
+func DeferFunction(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState) lua.LGFunction {
+	return func(L *lua.LState) int {
+		L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+		options := L.OptTable(2, nil)
+
+		if state.Responded() {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString("interaction has already been acknowledged"))
+			return 2
+		}
+
+		ephemeral := false
+		if options != nil {
+			if options.RawGetString("ephemeral") != lua.LNil {
+				if options.RawGetString("ephemeral").Type() != lua.LTBool {
+					L.ArgError(2, "'ephemeral' in options must be a boolean")
+					return 0
+				}
+				ephemeral = lua.LVAsBool(options.RawGetString("ephemeral"))
+			}
+		}
+
+		flags := discordgo.MessageFlags(0)
+		if ephemeral {
+			flags = discordgo.MessageFlagsEphemeral
+		}
+
+		if err := session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Flags: flags,
+			},
+		}); err != nil {
+			slog.Error("Failed to defer interaction", "interaction_id", interaction.ID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to defer interaction: %s", err.Error())))
+			return 2
+		}
+
+		state.MarkDeferred(ephemeral)
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// discardDeferred deletes the "thinking" placeholder of a deferred response.
+// Discord turns the first followup after a defer into the response itself,
+// keeping the visibility chosen when deferring, so the placeholder is
+// discarded first when a followup needs the other visibility.
+func discardDeferred(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState) error {
+	if err := session.InteractionResponseDelete(interaction.Interaction); err != nil {
+		return err
+	}
+	state.MarkResponded(state.Ephemeral())
+	return nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DeferUpdateFunction returns a Lua function that acknowledges a component
+// interaction without changing the message it is attached to. The handler
+// then has up to 15 minutes to change the message with edit_response, or to
+// send new messages with reply or followup.
+func DeferUpdateFunction(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState) lua.LGFunction {
+	return func(L *lua.LState) int {
+		L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+
+		if state.Responded() {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString("interaction has already been acknowledged"))
+			return 2
+		}
+
+		if err := session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredMessageUpdate,
+		}); err != nil {
+			slog.Error("Failed to defer interaction update", "interaction_id", interaction.ID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to defer interaction update: %s", err.Error())))
+			return 2
+		}
+
+		// There is no pending response to fill in, so later replies are sent
+		// as followups rather than replacing the component's message.
+		state.MarkResponded(false)
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DeleteResponseFunction returns a Lua function for deleting the original
+// response to an interaction. An expired or already deleted response is
+// reported back to Lua instead of raising an error.
+func DeleteResponseFunction(session *discordgo.Session, interaction *discordgo.InteractionCreate) lua.LGFunction {
+	return func(L *lua.LState) int {
+		L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+
+		if InteractionExpired(interaction) {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(expiredTokenMessage))
+			return 2
+		}
+
+		if err := session.InteractionResponseDelete(interaction.Interaction); err != nil {
+			if isExpiredTokenError(err) {
+				slog.Warn("Interaction response already gone", "interaction_id", interaction.ID, "error", err)
+				L.Push(lua.LFalse)
+				L.Push(lua.LString(expiredTokenMessage))
+				return 2
+			}
+
+			slog.Error("Failed to delete interaction response", "interaction_id", interaction.ID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to delete response: %s", err.Error())))
+			return 2
+		}
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
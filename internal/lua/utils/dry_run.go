@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"sync/atomic"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// dryRun makes destructive bindings report what they would do instead of doing it.
+var dryRun atomic.Bool
+
+// SetDryRun enables or disables dry-run mode.
+func SetDryRun(enabled bool) {
+	dryRun.Store(enabled)
+}
+
+// DryRun reports whether destructive bindings should only preview their
+// changes. It is checked by message.delete, reaction.remove,
+// member.remove_role, role.assign_all and reaction_role.unbind.
+func DryRun() bool {
+	return dryRun.Load()
+}
+
+// PrepareDryRunPreview builds the table a destructive binding returns in
+// dry-run mode, naming the action and the IDs it would have affected.
+func PrepareDryRunPreview(L *lua.LState, action string, ids []string) *lua.LTable {
+	previewTable := L.NewTable()
+	previewTable.RawSetString("dry_run", lua.LTrue)
+	previewTable.RawSetString("action", lua.LString(action))
+
+	idsTable := L.NewTable()
+	for _, id := range ids {
+		idsTable.Append(lua.LString(id))
+	}
+	previewTable.RawSetString("ids", idsTable)
+	return previewTable
+}
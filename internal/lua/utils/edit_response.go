@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// InteractionTokenLifetime is how long Discord accepts an interaction token
+// for editing or deleting the original response and sending followups.
+const InteractionTokenLifetime = 15 * time.Minute
+
+// EditResponseFunction returns a Lua function for editing the original
+// response to an interaction. The interaction (and its token) is captured by
+// the closure, so the function keeps working when called later from a timer,
+// as long as the token has not expired. Editing a deferred response fills it
+// in, so later replies are sent as followups rather than overwriting it.
+func EditResponseFunction(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState) lua.LGFunction {
+	return func(L *lua.LState) int {
+		L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+		content := L.CheckString(2)
+		options := L.OptTable(3, nil)
+
+		if InteractionExpired(interaction) {
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(expiredTokenMessage))
+			return 2
+		}
+
+		edit := &discordgo.WebhookEdit{
+			Content:         &content,
+			AllowedMentions: AllowedMentions(parseAllowEveryone(L, options, 3)),
+		}
+		componentsV2 := false
+
+		if options != nil {
+			embedRaw := options.RawGetString("embed")
+			if embedRaw != lua.LNil {
+				embedTable, ok := embedRaw.(*lua.LTable)
+				if !ok {
+					L.ArgError(3, "'embed' in options must be a table")
+					return 0
+				}
+				embed, err := ParseEmbed(L, embedTable)
+				if err != nil {
+					L.ArgError(3, fmt.Sprintf("invalid embed: %s", err.Error()))
+					return 0
+				}
+				edit.Embeds = &[]*discordgo.MessageEmbed{embed}
+			}
+
+			componentsRaw := options.RawGetString("components")
+			if componentsRaw != lua.LNil {
+				componentsTable, ok := componentsRaw.(*lua.LTable)
+				if !ok {
+					L.ArgError(3, "'components' in options must be a table")
+					return 0
+				}
+				components, err := ParseComponents(L, componentsTable)
+				if err != nil {
+					L.ArgError(3, fmt.Sprintf("invalid components: %s", err.Error()))
+					return 0
+				}
+				edit.Components = &components
+
+				// Layout components replace the content and embed of a message
+				if UsesComponentsV2(components) {
+					if content != "" || edit.Embeds != nil {
+						L.ArgError(2, "content and embed must be empty when using layout components, use a text_display instead")
+						return 0
+					}
+					edit.Content = nil
+					componentsV2 = true
+				}
+			}
+		}
+
+		var err error
+		if componentsV2 {
+			err = editResponseV2(session, interaction, edit)
+		} else {
+			_, err = session.InteractionResponseEdit(interaction.Interaction, edit)
+		}
+		if isExpiredTokenError(err) {
+			slog.Warn("Interaction response can no longer be edited", "interaction_id", interaction.ID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(expiredTokenMessage))
+			return 2
+		}
+		if err != nil {
+			slog.Error("Failed to edit interaction response", "interaction_id", interaction.ID, "error", err)
+			L.Push(lua.LFalse)
+			L.Push(lua.LString(fmt.Sprintf("Failed to edit response: %s", err.Error())))
+			return 2
+		}
+		if state.Deferred() {
+			state.MarkResponded(state.Ephemeral())
+		}
+
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// webhookEditV2 is a response edit carrying message flags, which discordgo's
+// WebhookEdit has no field for.
+type webhookEditV2 struct {
+	*discordgo.WebhookEdit
+	Flags discordgo.MessageFlags `json:"flags"`
+}
+
+// editResponseV2 fills in the original response with layout components,
+// which Discord only accepts along with the IS_COMPONENTS_V2 flag.
+func editResponseV2(session *discordgo.Session, interaction *discordgo.InteractionCreate, edit *discordgo.WebhookEdit) error {
+	uri := discordgo.EndpointWebhookMessage(interaction.AppID, interaction.Token, "@original")
+	_, err := session.RequestWithBucketID("PATCH", uri, webhookEditV2{
+		WebhookEdit: edit,
+		Flags:       discordgo.MessageFlagsIsComponentsV2,
+	}, discordgo.EndpointWebhookToken("", ""))
+	return err
+}
+
+// InteractionExpired reports whether the interaction token is past its
+// lifetime, based on the creation time encoded in the interaction ID.
+func InteractionExpired(interaction *discordgo.InteractionCreate) bool {
+	created, err := discordgo.SnowflakeTimestamp(interaction.ID)
+	if err != nil {
+		return false // Let Discord decide if the ID can't be decoded
+	}
+	return time.Since(created) > InteractionTokenLifetime
+}
+
+// expiredTokenMessage is the error returned to Lua for a response that can
+// no longer be changed, because the token expired or the response is gone.
+const expiredTokenMessage = "interaction token has expired or the response no longer exists"
+
+// isExpiredTokenError reports whether a Discord API error means the
+// interaction token is no longer valid or its response no longer exists.
+func isExpiredTokenError(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Message == nil {
+		return false
+	}
+
+	switch restErr.Message.Code {
+	case discordgo.ErrCodeUnknownWebhook,
+		discordgo.ErrCodeInvalidWebhookTokenProvided,
+		discordgo.ErrCodeUnknownInteraction,
+		discordgo.ErrCodeUnknownMessage:
+		return true
+	}
+	return false
+}
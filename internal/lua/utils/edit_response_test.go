@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// recordedRequest is a request the fake Discord API received.
+type recordedRequest struct {
+	method string
+	path   string
+	body   map[string]any
+}
+
+// fakeDiscord answers every request with an empty message and records it.
+type fakeDiscord struct {
+	requests  []recordedRequest
+	errorCode int // When set, every request fails with this Discord error code
+}
+
+func (f *fakeDiscord) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := recordedRequest{method: req.Method, path: req.URL.Path}
+	if req.Body != nil {
+		raw, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(raw, &recorded.body)
+	}
+	f.requests = append(f.requests, recorded)
+
+	if f.errorCode != 0 {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"message":"failed","code":%d}`, f.errorCode))),
+			Request:    req,
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"1","channel_id":"2"}`)),
+		Request:    req,
+	}, nil
+}
+
+// newTestInteraction returns a command interaction created just now, so its
+// token hasn't expired, on a session whose requests go to a fake Discord.
+func newTestInteraction(t *testing.T) (*discordgo.Session, *discordgo.InteractionCreate, *fakeDiscord) {
+	t.Helper()
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeDiscord{}
+	session.Client = &http.Client{Transport: fake}
+
+	id := (time.Now().UnixMilli() - 1420070400000) << 22
+	interaction := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:    strconv.FormatInt(id, 10),
+		AppID: "100",
+		Type:  discordgo.InteractionApplicationCommand,
+		Token: "token",
+	}}
+	return session, interaction, fake
+}
+
+func TestDeferThenEditWithComponents(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		components string
+		wantTypes  []float64 // Types of the top-level components sent
+		wantV2     bool
+	}{
+		{
+			name:       "buttons",
+			content:    "Pick one",
+			components: `{ { type = "button", label = "Yes", custom_id = "yes" }, { type = "button", label = "No", custom_id = "no" } }`,
+			wantTypes:  []float64{float64(discordgo.ActionsRowComponent)},
+		},
+		{
+			name:       "layout",
+			content:    "",
+			components: `{ { type = "text_display", content = "Pick one" }, { type = "button", label = "Yes", custom_id = "yes" } }`,
+			wantTypes:  []float64{float64(discordgo.TextDisplayComponent), float64(discordgo.ActionsRowComponent)},
+			wantV2:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, interaction, fake := newTestInteraction(t)
+			state := NewResponseState()
+
+			L := lua.NewState()
+			defer L.Close()
+			L.SetGlobal("defer", L.NewFunction(DeferFunction(session, interaction, state)))
+			L.SetGlobal("edit_response", L.NewFunction(EditResponseFunction(session, interaction, state)))
+			L.SetGlobal("content", lua.LString(tt.content))
+
+			script := `
+				local interaction = {}
+				assert(defer(interaction))
+				local ok, err = edit_response(interaction, content, { components = ` + tt.components + ` })
+				assert(ok, err)
+			`
+			if err := L.DoString(script); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(fake.requests) != 2 {
+				t.Fatalf("got %d requests, want a defer and an edit", len(fake.requests))
+			}
+			deferred := fake.requests[0]
+			if deferred.method != http.MethodPost || deferred.body["type"] != float64(discordgo.InteractionResponseDeferredChannelMessageWithSource) {
+				t.Errorf("first request is %s with type %v, want a deferred response", deferred.method, deferred.body["type"])
+			}
+
+			edit := fake.requests[1]
+			if edit.method != http.MethodPatch || !strings.HasSuffix(edit.path, "/webhooks/100/token/messages/@original") {
+				t.Fatalf("second request is %s %s, want an edit of the original response", edit.method, edit.path)
+			}
+
+			components, _ := edit.body["components"].([]any)
+			if len(components) != len(tt.wantTypes) {
+				t.Fatalf("edit has %d components, want %d: %v", len(components), len(tt.wantTypes), edit.body["components"])
+			}
+			for i, component := range components {
+				if got := component.(map[string]any)["type"]; got != tt.wantTypes[i] {
+					t.Errorf("component %d has type %v, want %v", i, got, tt.wantTypes[i])
+				}
+			}
+
+			flags, _ := edit.body["flags"].(float64)
+			if gotV2 := discordgo.MessageFlags(flags)&discordgo.MessageFlagsIsComponentsV2 != 0; gotV2 != tt.wantV2 {
+				t.Errorf("edit has the components V2 flag %v, want %v", gotV2, tt.wantV2)
+			}
+			if tt.wantV2 {
+				if _, hasContent := edit.body["content"]; hasContent {
+					t.Errorf("edit with layout components has content %v", edit.body["content"])
+				}
+			}
+
+			if state.Deferred() || !state.Responded() {
+				t.Errorf("edit left the response deferred")
+			}
+		})
+	}
+}
+
+func TestEditWithLayoutComponentsRejectsContent(t *testing.T) {
+	session, interaction, fake := newTestInteraction(t)
+	state := NewResponseState()
+	state.MarkDeferred(false)
+
+	L := lua.NewState()
+	defer L.Close()
+	L.SetGlobal("edit_response", L.NewFunction(EditResponseFunction(session, interaction, state)))
+
+	err := L.DoString(`edit_response({}, "Pick one", { components = { { type = "text_display", content = "Pick one" } } })`)
+	if err == nil || !strings.Contains(err.Error(), "content and embed must be empty") {
+		t.Fatalf("got error %v, want content to be rejected", err)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("sent %d requests for a rejected edit", len(fake.requests))
+	}
+}
+
+func TestExpiredTokenErrors(t *testing.T) {
+	functions := map[string]func(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState) lua.LGFunction{
+		"edit_response": EditResponseFunction,
+		"followup":      FollowupFunction,
+		"delete_response": func(session *discordgo.Session, interaction *discordgo.InteractionCreate, _ *ResponseState) lua.LGFunction {
+			return DeleteResponseFunction(session, interaction)
+		},
+	}
+
+	tests := []struct {
+		name         string
+		function     string
+		expired      bool // Whether the interaction is older than its token
+		errorCode    int
+		wantErr      string
+		wantRequests int
+	}{
+		{name: "edit after expiry", function: "edit_response", expired: true, wantErr: expiredTokenMessage},
+		{name: "edit with unknown webhook", function: "edit_response", errorCode: discordgo.ErrCodeUnknownWebhook, wantErr: expiredTokenMessage, wantRequests: 1},
+		{name: "edit of a deleted response", function: "edit_response", errorCode: discordgo.ErrCodeUnknownMessage, wantErr: expiredTokenMessage, wantRequests: 1},
+		{name: "edit with other error", function: "edit_response", errorCode: discordgo.ErrCodeMissingPermissions, wantErr: "Failed to edit response: ", wantRequests: 1},
+		{name: "followup after expiry", function: "followup", expired: true, wantErr: expiredTokenMessage},
+		{name: "followup with invalid token", function: "followup", errorCode: discordgo.ErrCodeInvalidWebhookTokenProvided, wantErr: expiredTokenMessage, wantRequests: 1},
+		{name: "followup with unknown interaction", function: "followup", errorCode: discordgo.ErrCodeUnknownInteraction, wantErr: expiredTokenMessage, wantRequests: 1},
+		{name: "followup with other error", function: "followup", errorCode: discordgo.ErrCodeMissingPermissions, wantErr: "Failed to send followup: ", wantRequests: 1},
+		{name: "delete after expiry", function: "delete_response", expired: true, wantErr: expiredTokenMessage},
+		{name: "delete of a deleted response", function: "delete_response", errorCode: discordgo.ErrCodeUnknownMessage, wantErr: expiredTokenMessage, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, interaction, fake := newTestInteraction(t)
+			fake.errorCode = tt.errorCode
+			if tt.expired {
+				created := time.Now().Add(-InteractionTokenLifetime - time.Minute)
+				interaction.ID = strconv.FormatInt((created.UnixMilli()-1420070400000)<<22, 10)
+			}
+			state := NewResponseState()
+			state.MarkResponded(false)
+
+			L := lua.NewState()
+			defer L.Close()
+			L.SetGlobal("call", L.NewFunction(functions[tt.function](session, interaction, state)))
+			if err := L.DoString(`ok, err = call({}, "Hello")`); err != nil {
+				t.Fatal(err)
+			}
+
+			got := lua.LVAsString(L.GetGlobal("err"))
+			if !strings.HasPrefix(got, tt.wantErr) || (tt.wantErr == expiredTokenMessage && got != tt.wantErr) {
+				t.Errorf("got error %q, want %q", got, tt.wantErr)
+			}
+			if lua.LVAsBool(L.GetGlobal("ok")) {
+				t.Errorf("call succeeded, want it to fail")
+			}
+			if len(fake.requests) != tt.wantRequests {
+				t.Errorf("sent %d requests, want %d", len(fake.requests), tt.wantRequests)
+			}
+		})
+	}
+}
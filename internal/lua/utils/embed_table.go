@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PrepareEmbedTable converts a Discord embed into a Lua table, using the same
+// shape `ParseEmbed` accepts. Link previews also carry a "type" and a
+// "provider", such as the site a link points to.
+func PrepareEmbedTable(L *lua.LState, embed *discordgo.MessageEmbed) *lua.LTable {
+	embedTable := L.NewTable()
+	embedTable.RawSetString("type", lua.LString(embed.Type))
+	embedTable.RawSetString("title", lua.LString(embed.Title))
+	embedTable.RawSetString("description", lua.LString(embed.Description))
+	embedTable.RawSetString("url", lua.LString(embed.URL))
+	embedTable.RawSetString("color", lua.LNumber(embed.Color))
+	if embed.Timestamp != "" {
+		embedTable.RawSetString("timestamp", lua.LString(embed.Timestamp))
+	}
+
+	if embed.Image != nil {
+		imageTable := L.NewTable()
+		imageTable.RawSetString("url", lua.LString(embed.Image.URL))
+		embedTable.RawSetString("image", imageTable)
+	}
+	if embed.Thumbnail != nil {
+		thumbnailTable := L.NewTable()
+		thumbnailTable.RawSetString("url", lua.LString(embed.Thumbnail.URL))
+		embedTable.RawSetString("thumbnail", thumbnailTable)
+	}
+	if embed.Video != nil {
+		videoTable := L.NewTable()
+		videoTable.RawSetString("url", lua.LString(embed.Video.URL))
+		embedTable.RawSetString("video", videoTable)
+	}
+	if embed.Provider != nil {
+		providerTable := L.NewTable()
+		providerTable.RawSetString("name", lua.LString(embed.Provider.Name))
+		providerTable.RawSetString("url", lua.LString(embed.Provider.URL))
+		embedTable.RawSetString("provider", providerTable)
+	}
+	if embed.Author != nil {
+		authorTable := L.NewTable()
+		authorTable.RawSetString("name", lua.LString(embed.Author.Name))
+		authorTable.RawSetString("url", lua.LString(embed.Author.URL))
+		authorTable.RawSetString("icon_url", lua.LString(embed.Author.IconURL))
+		embedTable.RawSetString("author", authorTable)
+	}
+	if embed.Footer != nil {
+		footerTable := L.NewTable()
+		footerTable.RawSetString("text", lua.LString(embed.Footer.Text))
+		footerTable.RawSetString("icon_url", lua.LString(embed.Footer.IconURL))
+		embedTable.RawSetString("footer", footerTable)
+	}
+
+	fieldsTable := L.NewTable()
+	for _, field := range embed.Fields {
+		fieldTable := L.NewTable()
+		fieldTable.RawSetString("name", lua.LString(field.Name))
+		fieldTable.RawSetString("value", lua.LString(field.Value))
+		fieldTable.RawSetString("inline", lua.LBool(field.Inline))
+		fieldsTable.Append(fieldTable)
+	}
+	embedTable.RawSetString("fields", fieldsTable)
+
+	return embedTable
+}
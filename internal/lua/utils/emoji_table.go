@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PrepareEmojiTable converts a Discord emoji into a Lua table. Unicode emojis
+// have no ID, so custom ones can be told apart by `unicode`, and `to_string`
+// formats the emoji the way `reaction.add` accepts it.
+func PrepareEmojiTable(L *lua.LState, emoji *discordgo.Emoji) *lua.LTable {
+	emojiTable := L.NewTable()
+	emojiTable.RawSetString("name", lua.LString(emoji.Name))
+	if emoji.ID != "" {
+		emojiTable.RawSetString("id", lua.LString(emoji.ID))
+	}
+	emojiTable.RawSetString("animated", lua.LBool(emoji.Animated))
+	emojiTable.RawSetString("unicode", lua.LBool(emoji.ID == ""))
+
+	apiName := emoji.APIName()
+	emojiTable.RawSetString("to_string", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LString(apiName))
+		return 1
+	}))
+	return emojiTable
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ActiveSKUs returns the SKU IDs of the entitlements that are currently
+// valid, each listed once.
+func ActiveSKUs(entitlements []*discordgo.Entitlement) []string {
+	now := time.Now()
+	seen := make(map[string]bool)
+
+	var skus []string
+	for _, entitlement := range entitlements {
+		if entitlement.Deleted || seen[entitlement.SKUID] {
+			continue
+		}
+		if entitlement.StartsAt != nil && now.Before(*entitlement.StartsAt) {
+			continue
+		}
+		if entitlement.EndsAt != nil && now.After(*entitlement.EndsAt) {
+			continue
+		}
+		seen[entitlement.SKUID] = true
+		skus = append(skus, entitlement.SKUID)
+	}
+	return skus
+}
+
+// PrepareSKUTable converts a list of SKU IDs into a Lua array.
+func PrepareSKUTable(L *lua.LState, skus []string) *lua.LTable {
+	skuTable := L.NewTable()
+	for _, sku := range skus {
+		skuTable.Append(lua.LString(sku))
+	}
+	return skuTable
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// FollowupFunction returns a Lua function for sending a followup message to
+// an interaction that has already been acknowledged (replied to or deferred).
+// Followups can carry embeds, components and file attachments, and each one
+// can be ephemeral on its own, even right after a defer with the other
+// visibility. The sent message is returned after its ID so handlers can link
+// to it.
+func FollowupFunction(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState) lua.LGFunction {
+	return func(L *lua.LState) int {
+		L.CheckType(1, lua.LTTable) // Check 'self' argument is a table
+		content := L.CheckString(2)
+		options := L.OptTable(3, nil)
+
+		if InteractionExpired(interaction) {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(expiredTokenMessage))
+			return 2
+		}
+
+		params := &discordgo.WebhookParams{
+			Content:         content,
+			AllowedMentions: AllowedMentions(parseAllowEveryone(L, options, 3)),
+		}
+		ephemeral := false
+
+		if options != nil {
+			if options.RawGetString("ephemeral") != lua.LNil {
+				if options.RawGetString("ephemeral").Type() != lua.LTBool {
+					L.ArgError(3, "'ephemeral' in options must be a boolean")
+					return 0
+				}
+				ephemeral = lua.LVAsBool(options.RawGetString("ephemeral"))
+				if ephemeral {
+					params.Flags = discordgo.MessageFlagsEphemeral
+				}
+			}
+
+			embedRaw := options.RawGetString("embed")
+			if embedRaw != lua.LNil {
+				embedTable, ok := embedRaw.(*lua.LTable)
+				if !ok {
+					L.ArgError(3, "'embed' in options must be a table")
+					return 0
+				}
+				embed, err := ParseEmbed(L, embedTable)
+				if err != nil {
+					L.ArgError(3, fmt.Sprintf("invalid embed: %s", err.Error()))
+					return 0
+				}
+				params.Embeds = []*discordgo.MessageEmbed{embed}
+			}
+
+			componentsRaw := options.RawGetString("components")
+			if componentsRaw != lua.LNil {
+				componentsTable, ok := componentsRaw.(*lua.LTable)
+				if !ok {
+					L.ArgError(3, "'components' in options must be a table")
+					return 0
+				}
+				components, err := ParseComponents(L, componentsTable)
+				if err != nil {
+					L.ArgError(3, fmt.Sprintf("invalid components: %s", err.Error()))
+					return 0
+				}
+				params.Components = components
+
+				// Layout components replace the content and embed of a message
+				if UsesComponentsV2(components) {
+					if params.Content != "" || len(params.Embeds) > 0 {
+						L.ArgError(2, "content and embed must be empty when using layout components, use a text_display instead")
+						return 0
+					}
+					params.Flags |= discordgo.MessageFlagsIsComponentsV2
+				}
+			}
+
+			filesRaw := options.RawGetString("files")
+			if filesRaw != lua.LNil {
+				filesTable, ok := filesRaw.(*lua.LTable)
+				if !ok {
+					L.ArgError(3, "'files' in options must be a table")
+					return 0
+				}
+				files, err := ParseFiles(L, filesTable)
+				if err != nil {
+					L.ArgError(3, fmt.Sprintf("invalid files: %s", err.Error()))
+					return 0
+				}
+				params.Files = files
+			}
+		}
+
+		if state.Deferred() && ephemeral != state.Ephemeral() {
+			if err := discardDeferred(session, interaction, state); isExpiredTokenError(err) {
+				slog.Warn("Interaction token expired before followup", "interaction_id", interaction.ID, "error", err)
+				L.Push(lua.LNil)
+				L.Push(lua.LString(expiredTokenMessage))
+				return 2
+			} else if err != nil {
+				slog.Error("Failed to discard deferred response before followup", "interaction_id", interaction.ID, "error", err)
+				L.Push(lua.LNil)
+				L.Push(lua.LString(fmt.Sprintf("Failed to send followup: %s", err.Error())))
+				return 2
+			}
+		}
+
+		message, err := session.FollowupMessageCreate(interaction.Interaction, true, params)
+		if isExpiredTokenError(err) {
+			slog.Warn("Interaction token expired before followup", "interaction_id", interaction.ID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(expiredTokenMessage))
+			return 2
+		}
+		if err != nil {
+			slog.Error("Failed to send followup message", "interaction_id", interaction.ID, "error", err)
+			L.Push(lua.LNil)
+			L.Push(lua.LString(fmt.Sprintf("Failed to send followup: %s", err.Error())))
+			return 2
+		}
+
+		// A followup to a pending defer becomes the response, which later
+		// replies must not edit over
+		if state.Deferred() {
+			state.MarkResponded(state.Ephemeral())
+		}
+
+		L.Push(lua.LString(message.ID))
+		L.Push(PrepareMessageTable(L, message, interaction.GuildID))
+		return 2
+	}
+}
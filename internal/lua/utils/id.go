@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// idGenerator hands out UUIDv7s: a millisecond timestamp, then a counter
+// that keeps IDs from the same millisecond ordered, then random bits. IDs
+// are unique even when many are generated at once, and sort by creation.
+var idGenerator struct {
+	mu      sync.Mutex
+	lastMs  int64
+	counter uint16
+}
+
+// NewID returns a new UUIDv7 string, e.g. "01920c5e-8f3a-7000-9c7e-3f1d2a4b5c6d".
+func NewID() string {
+	idGenerator.mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms <= idGenerator.lastMs {
+		// Same millisecond, or the clock went backwards: keep counting from
+		// the last timestamp, borrowing the next millisecond when the 12 bit
+		// counter runs out.
+		ms = idGenerator.lastMs
+		idGenerator.counter++
+		if idGenerator.counter > 0x0fff {
+			ms++
+			idGenerator.counter = 0
+		}
+	} else {
+		idGenerator.counter = 0
+	}
+	idGenerator.lastMs = ms
+	counter := idGenerator.counter
+	idGenerator.mu.Unlock()
+
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[0:8], uint64(ms)<<16)
+	binary.BigEndian.PutUint16(id[6:8], 0x7000|counter)
+	if _, err := rand.Read(id[8:]); err != nil {
+		// The timestamp and counter alone are still unique within this process
+		binary.BigEndian.PutUint64(id[8:], uint64(time.Now().UnixNano()))
+	}
+	id[8] = id[8]&0x3f | 0x80 // RFC 9562 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
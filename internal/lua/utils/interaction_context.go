@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// InteractionContext is the Go side of an interaction table, for bindings
+// that take an interaction as an argument and need to respond to it.
+type InteractionContext struct {
+	Interaction *discordgo.InteractionCreate
+	State       *ResponseState
+}
+
+// interactionEntry is a remembered interaction table and its context.
+type interactionEntry struct {
+	table     *lua.LTable
+	ctx       *InteractionContext
+	expiresAt time.Time // When the token expires at the latest
+}
+
+// interactionContexts maps interaction tables to their context while the
+// interaction can still be responded to. The entries are also listed in the
+// order they were remembered, which is the order they expire in, so expired
+// entries are dropped from the front without visiting the rest.
+var (
+	interactionContextsMu sync.Mutex
+	interactionContexts   = make(map[*lua.LTable]*list.Element) // Elements hold an *interactionEntry
+	interactionExpiries   = list.New()
+)
+
+// rememberInteraction records the context of an interaction table, and drops
+// the contexts whose token has expired since.
+func rememberInteraction(table *lua.LTable, interaction *discordgo.InteractionCreate, state *ResponseState) {
+	interactionContextsMu.Lock()
+	defer interactionContextsMu.Unlock()
+
+	now := time.Now()
+	for front := interactionExpiries.Front(); front != nil; front = interactionExpiries.Front() {
+		if !now.After(front.Value.(*interactionEntry).expiresAt) {
+			break
+		}
+		forgetElement(front)
+	}
+
+	// The token was created before it arrived, so it expires no later than this
+	interactionContexts[table] = interactionExpiries.PushBack(&interactionEntry{
+		table:     table,
+		ctx:       &InteractionContext{Interaction: interaction, State: state},
+		expiresAt: now.Add(InteractionTokenLifetime),
+	})
+}
+
+// ReleaseInteraction forgets the context of an interaction table once its
+// handler has returned, unless its deferred response is still to be filled
+// in. Interactions left remembered are forgotten once their token expires.
+func ReleaseInteraction(table *lua.LTable, state *ResponseState) {
+	if !state.Responded() || state.Deferred() {
+		return
+	}
+
+	interactionContextsMu.Lock()
+	defer interactionContextsMu.Unlock()
+	if element, ok := interactionContexts[table]; ok {
+		forgetElement(element)
+	}
+}
+
+// forgetElement drops a remembered interaction. It must be called with the
+// lock held.
+func forgetElement(element *list.Element) {
+	delete(interactionContexts, element.Value.(*interactionEntry).table)
+	interactionExpiries.Remove(element)
+}
+
+// LookupInteraction returns the context of an interaction table, or false if
+// the table is not an interaction or the interaction has expired.
+func LookupInteraction(table *lua.LTable) (*InteractionContext, bool) {
+	interactionContextsMu.Lock()
+	defer interactionContextsMu.Unlock()
+
+	element, ok := interactionContexts[table]
+	if !ok {
+		return nil, false
+	}
+	ctx := element.Value.(*interactionEntry).ctx
+	if InteractionExpired(ctx.Interaction) {
+		forgetElement(element)
+		return nil, false
+	}
+	return ctx, true
+}
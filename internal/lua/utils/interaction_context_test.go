@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReleaseInteraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		respond  func(state *ResponseState)
+		wantKept bool
+	}{
+		{
+			name:    "replied",
+			respond: func(state *ResponseState) { state.MarkResponded(false) },
+		},
+		{
+			name: "deferred and then replied",
+			respond: func(state *ResponseState) {
+				state.MarkDeferred(false)
+				state.MarkResponded(false)
+			},
+		},
+		{
+			name:     "deferred",
+			respond:  func(state *ResponseState) { state.MarkDeferred(true) },
+			wantKept: true,
+		},
+		{
+			name:     "unanswered",
+			respond:  func(state *ResponseState) {},
+			wantKept: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, interaction, _ := newTestInteraction(t)
+			L := lua.NewState()
+			defer L.Close()
+
+			table, state := PrepareInteractionTable(L, nil, interaction)
+			if _, ok := LookupInteraction(table); !ok {
+				t.Fatal("interaction was not remembered")
+			}
+
+			tt.respond(state)
+			ReleaseInteraction(table, state)
+			if _, kept := LookupInteraction(table); kept != tt.wantKept {
+				t.Errorf("kept = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestRememberInteractionDropsExpired(t *testing.T) {
+	_, interaction, _ := newTestInteraction(t)
+	L := lua.NewState()
+	defer L.Close()
+
+	expired, _ := PrepareInteractionTable(L, nil, interaction)
+	live, _ := PrepareInteractionTable(L, nil, interaction)
+
+	interactionContextsMu.Lock()
+	interactionContexts[expired].Value.(*interactionEntry).expiresAt = time.Now().Add(-time.Second)
+	interactionExpiries.MoveToFront(interactionContexts[expired])
+	interactionContextsMu.Unlock()
+
+	remembered, _ := PrepareInteractionTable(L, nil, interaction)
+
+	interactionContextsMu.Lock()
+	defer interactionContextsMu.Unlock()
+	for _, tt := range []struct {
+		name     string
+		table    *lua.LTable
+		wantKept bool
+	}{
+		{name: "expired", table: expired},
+		{name: "live", table: live, wantKept: true},
+		{name: "remembered", table: remembered, wantKept: true},
+	} {
+		if _, kept := interactionContexts[tt.table]; kept != tt.wantKept {
+			t.Errorf("%s: kept = %v, want %v", tt.name, kept, tt.wantKept)
+		}
+	}
+	if interactionExpiries.Len() != len(interactionContexts) {
+		t.Errorf("%d listed entries for %d remembered tables", interactionExpiries.Len(), len(interactionContexts))
+	}
+}
@@ -6,23 +6,63 @@ import (
 )
 
 // PrepareInteractionTable prepares a Lua table containing interaction details.
-func PrepareInteractionTable(L *lua.LState, session *discordgo.Session, interaction *discordgo.InteractionCreate) *lua.LTable {
+// The returned state tracks whether the handler has responded through it.
+func PrepareInteractionTable(L *lua.LState, session *discordgo.Session, interaction *discordgo.InteractionCreate) (*lua.LTable, *ResponseState) {
 	interactionTable := L.NewTable()
 
-	// Add the `reply` method to the interaction table
-	interactionTable.RawSetString("reply", L.NewFunction(ReplyFunction(session, interaction)))
+	// Add the response methods to the interaction table. They share a single
+	// state so each one knows whether the interaction was already acknowledged.
+	state := NewResponseState()
+	interactionTable.RawSetString("reply", L.NewFunction(ReplyFunction(session, interaction, state)))
+	interactionTable.RawSetString("defer", L.NewFunction(DeferFunction(session, interaction, state)))
+	interactionTable.RawSetString("followup", L.NewFunction(FollowupFunction(session, interaction, state)))
+	interactionTable.RawSetString("edit_response", L.NewFunction(EditResponseFunction(session, interaction, state)))
+	interactionTable.RawSetString("delete_response", L.NewFunction(DeleteResponseFunction(session, interaction)))
+	if interaction.Type == discordgo.InteractionMessageComponent {
+		interactionTable.RawSetString("defer_update", L.NewFunction(DeferUpdateFunction(session, interaction, state)))
+	}
 
 	interactionTable.RawSetString("interaction_id", lua.LString(interaction.ID))
+
+	// The creation time is encoded in the ID, and the token expires a fixed
+	// time after it, after which edits and followups are rejected
+	if created, err := discordgo.SnowflakeTimestamp(interaction.ID); err == nil {
+		interactionTable.RawSetString("created_at", lua.LNumber(created.Unix()))
+		interactionTable.RawSetString("expires_at", lua.LNumber(created.Add(InteractionTokenLifetime).Unix()))
+	}
 	interactionTable.RawSetString("channel_id", lua.LString(interaction.ChannelID))
 
-	// Add the `user` table to the interaction table
+	channelType := InteractionChannelType(session, interaction)
+	interactionTable.RawSetString("channel_type", lua.LString(channelType))
+	interactionTable.RawSetString("in_thread", lua.LBool(
+		channelType == "public_thread" || channelType == "private_thread" || channelType == "news_thread",
+	))
+
+	// Add the `user` table to the interaction table. Guild interactions carry
+	// the user on the member, DM interactions carry it directly.
+	user := interaction.User
+	if interaction.Member != nil && interaction.Member.User != nil {
+		user = interaction.Member.User
+	}
 	userTable := L.NewTable()
-	userTable.RawSetString("id", lua.LString(interaction.Member.User.ID))
-	userTable.RawSetString("username", lua.LString(interaction.Member.User.Username))
-	userTable.RawSetString("global_name", lua.LString(interaction.Member.User.GlobalName))
-	userTable.RawSetString("discriminator", lua.LString(interaction.Member.User.Discriminator))
-	userTable.RawSetString("avatar", lua.LString(interaction.Member.User.Avatar))
+	if user != nil {
+		userTable.RawSetString("id", lua.LString(user.ID))
+		userTable.RawSetString("username", lua.LString(user.Username))
+		userTable.RawSetString("global_name", lua.LString(user.GlobalName))
+		userTable.RawSetString("discriminator", lua.LString(user.Discriminator))
+		userTable.RawSetString("avatar", lua.LString(user.Avatar))
+		userTable.RawSetString("bot", lua.LBool(user.Bot))
+	}
 	interactionTable.RawSetString("user", userTable)
+	interactionTable.RawSetString("app_permissions", PreparePermissionsTable(L, interaction.AppPermissions))
+	interactionTable.RawSetString("entitlements", PrepareSKUTable(L, ActiveSKUs(interaction.Entitlements)))
+
+	// Component interactions carry the message the component is attached to,
+	// so handlers can update its content selectively.
+	if interaction.Type == discordgo.InteractionMessageComponent && interaction.Message != nil {
+		interactionTable.RawSetString("message", PrepareMessageTable(L, interaction.Message, interaction.GuildID))
+	}
 
-	return interactionTable
+	rememberInteraction(interactionTable, interaction, state)
+	return interactionTable, state
 }
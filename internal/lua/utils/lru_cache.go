@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DefaultCacheCapacity is how many entries the cache holds unless configured.
+const DefaultCacheCapacity = 1000
+
+// LRUCache is a thread-safe in-memory cache for transient values, such as API
+// responses. Unlike the StateManager it is never persisted, and once full the
+// least recently used entry is evicted to make room.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List               // Entries from most to least recently used
+	entries  map[string]*list.Element // Maps keys to their element in order
+}
+
+// cacheEntry is a cached value and when it expires, zero for never.
+type cacheEntry struct {
+	key     string
+	value   lua.LValue
+	expires time.Time
+}
+
+// NewLRUCache initializes an empty cache holding up to capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// SetCapacity changes how many entries the cache holds, evicting the least
+// recently used entries when it shrinks.
+func (c *LRUCache) SetCapacity(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = capacity
+	c.evict()
+}
+
+// Set caches a value, or removes it when the value is nil. A ttl of 0 keeps
+// the value until it is evicted.
+func (c *LRUCache) Set(key string, value lua.LValue, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+	if value == lua.LNil {
+		return
+	}
+
+	entry := &cacheEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.evict()
+}
+
+// Get returns a cached value, or nil when it is missing or expired. Reading
+// a value marks it as recently used.
+func (c *LRUCache) Get(key string) lua.LValue {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return lua.LNil
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return lua.LNil
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value
+}
+
+// evict drops the least recently used entries until the cache fits its
+// capacity. The caller must hold the lock.
+func (c *LRUCache) evict() {
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
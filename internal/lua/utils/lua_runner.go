@@ -1,16 +1,31 @@
 package utils
 
 import (
+	"log/slog"
+	"runtime/debug"
 	"sync"
 
+	"github.com/bwmarrin/discordgo"
 	lua "github.com/yuin/gopher-lua"
 )
 
 type luaTask func(L *lua.LState)
 
+// queueWarnThreshold is the backlog of tasks at which the runner warns that
+// it isn't keeping up.
+const queueWarnThreshold = 100
+
+// LuaRunner executes every task against the single Lua state, one at a time
+// in the order they were scheduled.
 type LuaRunner struct {
-	L     *lua.LState
-	tasks chan luaTask
+	L *lua.LState
+
+	mu    sync.Mutex
+	queue []luaTask     // Tasks waiting to run, guarded by mu
+	wake  chan struct{} // Signalled when the queue goes from empty to non-empty
+
+	guildID string // Guild of the interaction being handled, only touched on the runner
+	userID  string // User who triggered the interaction being handled, only touched on the runner
 }
 
 var runner *LuaRunner
@@ -20,8 +35,8 @@ func GetLuaRunner() *LuaRunner {
 	once.Do(func() {
 		L := lua.NewState()
 		r := &LuaRunner{
-			L:     L,
-			tasks: make(chan luaTask, 100),
+			L:    L,
+			wake: make(chan struct{}, 1),
 		}
 		runner = r
 
@@ -31,12 +46,104 @@ func GetLuaRunner() *LuaRunner {
 }
 
 func (r *LuaRunner) loop() {
-	for task := range r.tasks {
-		task(r.L)
+	for range r.wake {
+		for {
+			r.mu.Lock()
+			if len(r.queue) == 0 {
+				r.mu.Unlock()
+				break
+			}
+			task := r.queue[0]
+			r.queue[0] = nil
+			r.queue = r.queue[1:]
+			r.mu.Unlock()
+
+			r.run(task)
+		}
+	}
+}
+
+// run executes a task inside a protected call, so a panic in one handler
+// can't stop the runner or leave the state it shares with every other
+// handler corrupted. Lua's own protected call unwinds the call frames and
+// stack the aborted task left behind, which a plain recover would keep.
+func (r *LuaRunner) run(task luaTask) {
+	defer func() {
+		// Only reached if the unwinding itself fails
+		if rcv := recover(); rcv != nil {
+			slog.Error("Recovered from panic outside of a Lua task", "panic", rcv, "stack", string(debug.Stack()))
+		}
+	}()
+
+	r.L.Push(r.L.NewFunction(func(L *lua.LState) int {
+		task(L)
+		return 0
+	}))
+	if err := r.L.PCall(0, 0, nil); err != nil {
+		slog.Error("Recovered from a failed Lua task, later tasks are unaffected", "error", err)
+		r.guildID, r.userID = "", ""
 	}
 }
 
-// schedule a call
+// Do schedules a task to run on the Lua state. It never blocks, so tasks may
+// schedule further tasks, such as a handler starting a timer that fires
+// straight away. Those run after the current task returns, never nested in it.
 func (r *LuaRunner) Do(task luaTask) {
-	r.tasks <- task
+	r.mu.Lock()
+	r.queue = append(r.queue, task)
+	backlog := len(r.queue)
+	r.mu.Unlock()
+
+	if backlog == queueWarnThreshold {
+		slog.Warn("Lua runner is falling behind, tasks are queueing up", "backlog", backlog)
+	}
+
+	select {
+	case r.wake <- struct{}{}:
+	default: // The runner is already awake and will drain the queue
+	}
+}
+
+// Wait blocks until every task scheduled before it has run. It must not be
+// called from inside a task, which would wait on itself forever.
+func (r *LuaRunner) Wait() {
+	done := make(chan struct{})
+	r.Do(func(L *lua.LState) {
+		close(done)
+	})
+	<-done
+}
+
+// WithInteraction runs fn with the guild and user of the interaction recorded
+// as the current ones. It must be called from inside a task, so bindings
+// invoked by fn can scope their behaviour to the interaction being handled.
+func (r *LuaRunner) WithInteraction(interaction *discordgo.InteractionCreate, fn func()) {
+	previousGuild, previousUser := r.guildID, r.userID
+	r.guildID, r.userID = interaction.GuildID, InteractionUserID(interaction)
+	defer func() { r.guildID, r.userID = previousGuild, previousUser }()
+	fn()
+}
+
+// CurrentGuild returns the guild recorded by WithInteraction, or an empty
+// string when the running task is not handling an interaction.
+func (r *LuaRunner) CurrentGuild() string {
+	return r.guildID
+}
+
+// CurrentUser returns the user recorded by WithInteraction, or an empty
+// string when the running task is not handling an interaction.
+func (r *LuaRunner) CurrentUser() string {
+	return r.userID
+}
+
+// InteractionUserID returns the ID of the user who triggered an interaction,
+// whether it happened in a guild or in a DM.
+func InteractionUserID(interaction *discordgo.InteractionCreate) string {
+	if interaction.Member != nil && interaction.Member.User != nil {
+		return interaction.Member.User.ID
+	}
+	if interaction.User != nil {
+		return interaction.User.ID
+	}
+	return ""
 }
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxLuaValueDepth bounds how deeply nested tables are converted, which also
+// stops self-referencing tables from recursing forever.
+const maxLuaValueDepth = 100
+
+// LuaToGo converts a Lua value into plain Go values suitable for encoding,
+// such as JSON. Tables with consecutive integer keys from 1 become slices and
+// all other tables become maps with string keys. Functions, userdata and
+// other values that can't be represented outside the Lua state are an error.
+func LuaToGo(value lua.LValue) (any, error) {
+	return luaToGo(value, 0)
+}
+
+func luaToGo(value lua.LValue, depth int) (any, error) {
+	if depth > maxLuaValueDepth {
+		return nil, fmt.Errorf("table nesting exceeds %d levels", maxLuaValueDepth)
+	}
+
+	switch v := value.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LBool:
+		return bool(v), nil
+	case lua.LNumber:
+		return float64(v), nil
+	case lua.LString:
+		return string(v), nil
+	case *lua.LTable:
+		return tableToGo(v, depth)
+	default:
+		return nil, fmt.Errorf("cannot convert a %s value", value.Type().String())
+	}
+}
+
+// tableToGo converts a Lua table into a slice when it is a sequence, or a map otherwise.
+func tableToGo(table *lua.LTable, depth int) (any, error) {
+	keys := 0
+	table.ForEach(func(_, _ lua.LValue) { keys++ })
+
+	if n := table.MaxN(); n > 0 && n == keys {
+		list := make([]any, 0, n)
+		for idx := 1; idx <= n; idx++ {
+			item, err := luaToGo(table.RawGetInt(idx), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	}
+
+	dict := make(map[string]any, keys)
+	var convErr error
+	table.ForEach(func(key, value lua.LValue) {
+		if convErr != nil {
+			return
+		}
+		if key.Type() != lua.LTString && key.Type() != lua.LTNumber {
+			convErr = fmt.Errorf("cannot convert a table with %s keys", key.Type().String())
+			return
+		}
+		item, err := luaToGo(value, depth+1)
+		if err != nil {
+			convErr = err
+			return
+		}
+		dict[key.String()] = item
+	})
+	if convErr != nil {
+		return nil, convErr
+	}
+	return dict, nil
+}
+
+// GoToLua converts plain Go values, as produced by LuaToGo or by decoding
+// JSON into an `any`, back into Lua values. Unsupported types become nil.
+func GoToLua(L *lua.LState, value any) lua.LValue {
+	switch v := value.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case int:
+		return lua.LNumber(v)
+	case int64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []any:
+		table := L.NewTable()
+		for _, item := range v {
+			table.Append(GoToLua(L, item))
+		}
+		return table
+	case map[string]any:
+		table := L.NewTable()
+		for key, item := range v {
+			table.RawSetString(key, GoToLua(L, item))
+		}
+		return table
+	default:
+		return lua.LNil
+	}
+}
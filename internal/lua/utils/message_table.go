@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MessageLink builds the canonical jump link to a message. Messages outside a
+// guild (DMs) use "@me" in place of the guild ID.
+func MessageLink(guildID, channelID, messageID string) string {
+	if guildID == "" {
+		guildID = "@me"
+	}
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, messageID)
+}
+
+// PrepareMessageTable converts a Discord message into a Lua table. The guild
+// ID is used for the jump link when the message itself does not carry one,
+// which is the case for messages fetched over REST.
+func PrepareMessageTable(L *lua.LState, message *discordgo.Message, guildID string) *lua.LTable {
+	if message.GuildID != "" {
+		guildID = message.GuildID
+	}
+
+	messageTable := L.NewTable()
+	messageTable.RawSetString("id", lua.LString(message.ID))
+	messageTable.RawSetString("channel_id", lua.LString(message.ChannelID))
+	messageTable.RawSetString("guild_id", lua.LString(guildID))
+	messageTable.RawSetString("content", lua.LString(message.Content))
+	messageTable.RawSetString("link", lua.LString(MessageLink(guildID, message.ChannelID, message.ID)))
+
+	// Timestamps are unix seconds, edited_timestamp is left out for messages
+	// that were never edited
+	messageTable.RawSetString("timestamp", lua.LNumber(message.Timestamp.Unix()))
+	if message.EditedTimestamp != nil {
+		messageTable.RawSetString("edited_timestamp", lua.LNumber(message.EditedTimestamp.Unix()))
+	}
+
+	attachmentsTable := L.NewTable()
+	for _, attachment := range message.Attachments {
+		attachmentsTable.Append(PrepareAttachmentTable(L, attachment))
+	}
+	messageTable.RawSetString("attachments", attachmentsTable)
+
+	embedsTable := L.NewTable()
+	for _, embed := range message.Embeds {
+		embedsTable.Append(PrepareEmbedTable(L, embed))
+	}
+	messageTable.RawSetString("embeds", embedsTable)
+	messageTable.RawSetString("components", PrepareComponentsTable(L, message.Components))
+
+	if message.Author != nil {
+		authorTable := L.NewTable()
+		authorTable.RawSetString("id", lua.LString(message.Author.ID))
+		authorTable.RawSetString("username", lua.LString(message.Author.Username))
+		authorTable.RawSetString("global_name", lua.LString(message.Author.GlobalName))
+		authorTable.RawSetString("bot", lua.LBool(message.Author.Bot))
+		messageTable.RawSetString("author", authorTable)
+	}
+
+	return messageTable
+}
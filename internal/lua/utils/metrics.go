@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// CommandStats holds the aggregated metrics of a single command.
+type CommandStats struct {
+	Count    int64         // Number of invocations
+	Errors   int64         // Number of invocations whose handler raised an error
+	Total    time.Duration // Sum of handler durations
+	Max      time.Duration // Longest handler duration
+	LastUsed time.Time     // Time of the most recent invocation
+}
+
+// Average returns the mean handler duration.
+func (cs CommandStats) Average() time.Duration {
+	if cs.Count == 0 {
+		return 0
+	}
+	return cs.Total / time.Duration(cs.Count)
+}
+
+// ErrorRate returns the fraction of invocations that failed, between 0 and 1.
+func (cs CommandStats) ErrorRate() float64 {
+	if cs.Count == 0 {
+		return 0
+	}
+	return float64(cs.Errors) / float64(cs.Count)
+}
+
+// Metrics is a thread-safe in-memory registry of command invocation metrics.
+type Metrics struct {
+	mu       sync.Mutex
+	commands map[string]*CommandStats
+}
+
+// NewMetrics initializes an empty metrics registry.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		commands: make(map[string]*CommandStats),
+	}
+}
+
+// RecordCommand records a single invocation of a command.
+func (m *Metrics) RecordCommand(name string, duration time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, exists := m.commands[name]
+	if !exists {
+		stats = &CommandStats{}
+		m.commands[name] = stats
+	}
+
+	stats.Count++
+	if failed {
+		stats.Errors++
+	}
+	stats.Total += duration
+	if duration > stats.Max {
+		stats.Max = duration
+	}
+	stats.LastUsed = time.Now()
+}
+
+// Commands returns a copy of the metrics of every command invoked so far.
+func (m *Metrics) Commands() map[string]CommandStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]CommandStats, len(m.commands))
+	for name, stats := range m.commands {
+		snapshot[name] = *stats
+	}
+	return snapshot
+}
+
+// Reset clears all recorded metrics.
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.commands = make(map[string]*CommandStats)
+}
@@ -11,6 +11,11 @@ func NewOptionRegister(optionType discordgo.ApplicationCommandOptionType) lua.LG
 		buttonTable := L.NewTable()
 
 		switch argCount {
+		case 4:
+			buttonTable.RawSetString("name", lua.LString(L.CheckString(1)))
+			buttonTable.RawSetString("description", lua.LString(L.CheckString(2)))
+			buttonTable.RawSetString("required", lua.LBool(L.CheckBool(3)))
+			buttonTable.RawSetString("choices", copyChoices(L, L.CheckTable(4)))
 		case 3:
 			buttonTable.RawSetString("name", lua.LString(L.CheckString(1)))
 			buttonTable.RawSetString("description", lua.LString(L.CheckString(2)))
@@ -20,7 +25,7 @@ func NewOptionRegister(optionType discordgo.ApplicationCommandOptionType) lua.LG
 			buttonTable.RawSetString("description", lua.LString(L.CheckString(2)))
 			buttonTable.RawSetString("required", lua.LFalse)
 		default:
-			L.ArgError(1, "invalid arguments, expected (name, custom_id [, style])")
+			L.ArgError(1, "invalid arguments, expected (name, description [, required [, choices]])")
 		}
 
 		// Create a Table for the button
@@ -30,3 +35,21 @@ func NewOptionRegister(optionType discordgo.ApplicationCommandOptionType) lua.LG
 		return 1
 	}
 }
+
+// copyChoices copies the choices given to an option builder, so a choices
+// table the script keeps using or changing afterwards doesn't alter options
+// already built from it.
+func copyChoices(L *lua.LState, choices *lua.LTable) *lua.LTable {
+	copied := L.NewTable()
+	choices.ForEach(func(key, value lua.LValue) {
+		if choice, ok := value.(*lua.LTable); ok {
+			choiceCopy := L.NewTable()
+			choice.ForEach(func(field, fieldValue lua.LValue) {
+				choiceCopy.RawSet(field, fieldValue)
+			})
+			value = choiceCopy
+		}
+		copied.RawSet(key, value)
+	})
+	return copied
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PrepareResolvedOptionTable converts a user, channel, role, mentionable or
+// attachment option into a Lua table. The table always has the raw `id`, so
+// handlers that only need the ID work even when Discord sent no resolved
+// data; `resolved` reports whether the remaining fields were filled in.
+func PrepareResolvedOptionTable(L *lua.LState, optionType discordgo.ApplicationCommandOptionType, id string, resolved *discordgo.ApplicationCommandInteractionDataResolved) *lua.LTable {
+	optionTable := L.NewTable()
+	optionTable.RawSetString("id", lua.LString(id))
+	optionTable.RawSetString("resolved", lua.LFalse)
+	if resolved == nil {
+		return optionTable
+	}
+
+	switch optionType {
+	case discordgo.ApplicationCommandOptionUser:
+		setResolvedUser(optionTable, id, resolved)
+	case discordgo.ApplicationCommandOptionChannel:
+		setResolvedChannel(optionTable, id, resolved)
+	case discordgo.ApplicationCommandOptionRole:
+		setResolvedRole(optionTable, id, resolved)
+	case discordgo.ApplicationCommandOptionMentionable:
+		// Mentionables are either a user or a role, told apart by which
+		// resolved map carries the ID, so both share `name`.
+		if setResolvedUser(optionTable, id, resolved) {
+			optionTable.RawSetString("type", lua.LString("user"))
+		} else if setResolvedRole(optionTable, id, resolved) {
+			optionTable.RawSetString("type", lua.LString("role"))
+		}
+	case discordgo.ApplicationCommandOptionAttachment:
+		if attachment, ok := resolved.Attachments[id]; ok && attachment != nil {
+			PrepareAttachmentTable(L, attachment).ForEach(func(key, value lua.LValue) {
+				optionTable.RawSet(key, value)
+			})
+			optionTable.RawSetString("resolved", lua.LTrue)
+		}
+	}
+
+	return optionTable
+}
+
+// selectOptionTypes maps the entity select menus to the option type whose
+// resolution they share.
+var selectOptionTypes = map[discordgo.ComponentType]discordgo.ApplicationCommandOptionType{
+	discordgo.UserSelectMenuComponent:        discordgo.ApplicationCommandOptionUser,
+	discordgo.RoleSelectMenuComponent:        discordgo.ApplicationCommandOptionRole,
+	discordgo.ChannelSelectMenuComponent:     discordgo.ApplicationCommandOptionChannel,
+	discordgo.MentionableSelectMenuComponent: discordgo.ApplicationCommandOptionMentionable,
+}
+
+// PrepareSelectedTable converts the entities picked in a user, role, channel
+// or mentionable select menu into an array of tables shaped like resolved
+// command options, in the order of the picked values. Returns nil for string
+// selects and other components, whose values aren't entities.
+func PrepareSelectedTable(L *lua.LState, data discordgo.MessageComponentInteractionData) *lua.LTable {
+	optionType, ok := selectOptionTypes[data.ComponentType]
+	if !ok {
+		return nil
+	}
+
+	resolved := &discordgo.ApplicationCommandInteractionDataResolved{
+		Users:    data.Resolved.Users,
+		Members:  data.Resolved.Members,
+		Roles:    data.Resolved.Roles,
+		Channels: data.Resolved.Channels,
+	}
+
+	selectedTable := L.NewTable()
+	for _, id := range data.Values {
+		selectedTable.Append(PrepareResolvedOptionTable(L, optionType, id, resolved))
+	}
+	return selectedTable
+}
+
+// setResolvedUser fills in the user, and their membership when the option was
+// used in a guild, reporting whether the user was resolved.
+func setResolvedUser(optionTable *lua.LTable, id string, resolved *discordgo.ApplicationCommandInteractionDataResolved) bool {
+	user, ok := resolved.Users[id]
+	if !ok || user == nil {
+		return false
+	}
+
+	optionTable.RawSetString("name", lua.LString(user.DisplayName()))
+	optionTable.RawSetString("username", lua.LString(user.Username))
+	optionTable.RawSetString("global_name", lua.LString(user.GlobalName))
+	optionTable.RawSetString("avatar", lua.LString(user.Avatar))
+	optionTable.RawSetString("bot", lua.LBool(user.Bot))
+	if member, ok := resolved.Members[id]; ok && member != nil {
+		optionTable.RawSetString("nick", lua.LString(member.Nick))
+	}
+	optionTable.RawSetString("resolved", lua.LTrue)
+	return true
+}
+
+// setResolvedChannel fills in the channel, reporting whether it was resolved.
+func setResolvedChannel(optionTable *lua.LTable, id string, resolved *discordgo.ApplicationCommandInteractionDataResolved) bool {
+	channel, ok := resolved.Channels[id]
+	if !ok || channel == nil {
+		return false
+	}
+
+	channelType, ok := ChannelTypeNames[channel.Type]
+	if !ok {
+		channelType = "unknown"
+	}
+	optionTable.RawSetString("name", lua.LString(channel.Name))
+	optionTable.RawSetString("type", lua.LString(channelType))
+	optionTable.RawSetString("resolved", lua.LTrue)
+	return true
+}
+
+// setResolvedRole fills in the role, reporting whether it was resolved.
+func setResolvedRole(optionTable *lua.LTable, id string, resolved *discordgo.ApplicationCommandInteractionDataResolved) bool {
+	role, ok := resolved.Roles[id]
+	if !ok || role == nil {
+		return false
+	}
+
+	optionTable.RawSetString("name", lua.LString(role.Name))
+	optionTable.RawSetString("color", lua.LNumber(role.Color))
+	optionTable.RawSetString("resolved", lua.LTrue)
+	return true
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Owners is a thread-safe list of the users who own the bot's application:
+// its owner, or the owner and accepted members of the team owning it.
+type Owners struct {
+	mu  sync.RWMutex
+	ids map[string]bool
+}
+
+// NewOwners initializes an empty owner list, which knows no owners until it
+// is loaded.
+func NewOwners() *Owners {
+	return &Owners{
+		ids: make(map[string]bool),
+	}
+}
+
+// Load fetches the application from Discord and replaces the owner list. The
+// list is kept as it was when the fetch fails.
+func (o *Owners) Load(session *discordgo.Session) error {
+	app, err := session.Application("@me")
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]bool)
+	if app.Team != nil {
+		// The owner of a team owned application is a placeholder user
+		// standing for the team, so the members are the owners
+		ids[app.Team.OwnerID] = true
+		for _, member := range app.Team.Members {
+			if member.User != nil && member.MembershipState == discordgo.MembershipStateAccepted {
+				ids[member.User.ID] = true
+			}
+		}
+	} else if app.Owner != nil {
+		ids[app.Owner.ID] = true
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ids = ids
+	return nil
+}
+
+// IsOwner reports whether the user owns the application.
+func (o *Owners) IsOwner(userID string) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.ids[userID]
+}
+
+// Len returns how many owners are known.
+func (o *Owners) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.ids)
+}
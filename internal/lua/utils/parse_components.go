@@ -2,89 +2,369 @@ package utils
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
 	lua "github.com/yuin/gopher-lua"
 )
 
+// Length limits Discord enforces on component text fields.
+const (
+	MaxCustomIDLength          = 100
+	MaxButtonLabelLength       = 80
+	MaxPlaceholderLength       = 150
+	MaxSelectOptionLabelLength = 100
+	MaxSelectOptionValueLength = 100
+	MaxSelectOptionDescLength  = 100
+	MaxTextDisplayLength       = 4000
+)
+
+// CheckComponentLength returns an error naming the field when value is longer
+// than the limit Discord accepts for it.
+func CheckComponentLength(field, value string, limit int) error {
+	if length := utf8.RuneCountInString(value); length > limit {
+		return fmt.Errorf("%s must be %d characters or fewer, got %d", field, limit, length)
+	}
+	return nil
+}
+
 // ParseComponents parses a Lua table into Discord message components.
+// Text fields longer than Discord accepts are reported as an error.
+//
+// Buttons and selects on their own are wrapped in a single action row. When
+// the table also holds layout components such as text displays or containers,
+// the message uses Components V2 instead: the layout components are kept in
+// order and consecutive buttons are grouped into rows between them. Check
+// `UsesComponentsV2` to set the message flag Discord requires for them.
 func ParseComponents(_ *lua.LState, table *lua.LTable) ([]discordgo.MessageComponent, error) {
+	components, err := parseComponentList(table, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("no valid components found")
+	}
+
+	if !hasLayoutComponents(components) {
+		// Wrap components in an action row
+		return []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: components},
+		}, nil
+	}
+
+	return layoutComponents(components), nil
+}
+
+// UsesComponentsV2 reports whether parsed components include layout
+// components, which Discord only accepts on messages sent with the
+// IS_COMPONENTS_V2 flag. Such messages cannot have content or embeds.
+func UsesComponentsV2(components []discordgo.MessageComponent) bool {
+	return hasLayoutComponents(components)
+}
+
+// hasLayoutComponents reports whether any component is a Components V2 layout
+// component rather than an action row, button or select.
+func hasLayoutComponents(components []discordgo.MessageComponent) bool {
+	for _, component := range components {
+		switch component.(type) {
+		case discordgo.TextDisplay, discordgo.Container:
+			return true
+		}
+	}
+	return false
+}
+
+// layoutComponents arranges components for a Components V2 message. Runs of
+// buttons share an action row, while each select gets a row of its own.
+func layoutComponents(components []discordgo.MessageComponent) []discordgo.MessageComponent {
+	var layout []discordgo.MessageComponent
+	var buttons []discordgo.MessageComponent
+
+	flushButtons := func() {
+		if len(buttons) > 0 {
+			layout = append(layout, discordgo.ActionsRow{Components: buttons})
+			buttons = nil
+		}
+	}
+
+	for _, component := range components {
+		switch component.(type) {
+		case discordgo.Button:
+			buttons = append(buttons, component)
+		case discordgo.SelectMenu:
+			flushButtons()
+			layout = append(layout, discordgo.ActionsRow{Components: []discordgo.MessageComponent{component}})
+		default:
+			flushButtons()
+			layout = append(layout, component)
+		}
+	}
+	flushButtons()
+
+	return layout
+}
+
+// parseComponentList parses each entry of a components table, skipping
+// invalid ones. Containers are only allowed at the top level.
+func parseComponentList(table *lua.LTable, allowContainers bool) ([]discordgo.MessageComponent, error) {
 	var components []discordgo.MessageComponent
+	var parseErr error
 
 	table.ForEach(func(_, value lua.LValue) {
+		if parseErr != nil {
+			return
+		}
+
 		componentTable, ok := value.(*lua.LTable)
 		if !ok {
 			return // Skip invalid entries
 		}
 
-		componentType := componentTable.RawGetString("type").String()
-		switch componentType {
-		case "button":
-			label := componentTable.RawGetString("label").String()
-			customID := componentTable.RawGetString("custom_id").String()
+		var component discordgo.MessageComponent
+		component, parseErr = parseComponent(componentTable, allowContainers)
+		if component != nil {
+			components = append(components, component)
+		}
+	})
 
-			disabled := false
-			disabledRaw := componentTable.RawGetString("disabled")
-			if disabledRaw.Type() == lua.LTBool {
-				disabled = lua.LVAsBool(disabledRaw)
-			}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return components, nil
+}
 
-			components = append(components, discordgo.Button{
-				Label:    label,
-				CustomID: customID,
-				Style:    discordgo.PrimaryButton, // Default style
-				Disabled: disabled,
-			})
-		case "select":
-			placeholder := componentTable.RawGetString("placeholder").String()
-			customID := componentTable.RawGetString("custom_id").String()
-
-			disabled := false
-			disabledRaw := componentTable.RawGetString("disabled")
-			if disabledRaw.Type() == lua.LTBool {
-				disabled = lua.LVAsBool(disabledRaw)
-			}
+// parseComponent parses a single component table. Unknown or malformed
+// components are skipped by returning nil without an error.
+func parseComponent(componentTable *lua.LTable, allowContainers bool) (discordgo.MessageComponent, error) {
+	componentType := componentTable.RawGetString("type").String()
+	switch componentType {
+	case "button":
+		label := componentTable.RawGetString("label").String()
+		customID := componentTable.RawGetString("custom_id").String()
+		if err := CheckComponentLength("button label", label, MaxButtonLabelLength); err != nil {
+			return nil, err
+		}
+		if err := CheckComponentLength("button custom_id", customID, MaxCustomIDLength); err != nil {
+			return nil, err
+		}
 
+		disabled := false
+		disabledRaw := componentTable.RawGetString("disabled")
+		if disabledRaw.Type() == lua.LTBool {
+			disabled = lua.LVAsBool(disabledRaw)
+		}
+
+		return discordgo.Button{
+			Label:    label,
+			CustomID: customID,
+			Style:    discordgo.PrimaryButton, // Default style
+			Disabled: disabled,
+		}, nil
+	case "select":
+		placeholder := componentTable.RawGetString("placeholder").String()
+		customID := componentTable.RawGetString("custom_id").String()
+		if err := CheckComponentLength("select placeholder", placeholder, MaxPlaceholderLength); err != nil {
+			return nil, err
+		}
+		if err := CheckComponentLength("select custom_id", customID, MaxCustomIDLength); err != nil {
+			return nil, err
+		}
+
+		disabled := false
+		disabledRaw := componentTable.RawGetString("disabled")
+		if disabledRaw.Type() == lua.LTBool {
+			disabled = lua.LVAsBool(disabledRaw)
+		}
+
+		menuType, ok := selectMenuTypes[componentTable.RawGetString("menu_type").String()]
+		if componentTable.RawGetString("menu_type") == lua.LNil {
+			menuType, ok = discordgo.StringSelectMenu, true
+		}
+		if !ok {
+			return nil, nil // Skip invalid entries
+		}
+
+		menu := discordgo.SelectMenu{
+			MenuType:    menuType,
+			Placeholder: placeholder,
+			CustomID:    customID,
+			Disabled:    disabled,
+		}
+
+		if minValues := componentTable.RawGetString("min_values"); minValues.Type() == lua.LTNumber {
+			min := int(minValues.(lua.LNumber))
+			menu.MinValues = &min
+		}
+		if maxValues := componentTable.RawGetString("max_values"); maxValues.Type() == lua.LTNumber {
+			menu.MaxValues = int(maxValues.(lua.LNumber))
+		}
+
+		if menuType == discordgo.StringSelectMenu {
 			optionsRaw := componentTable.RawGetString("options")
 			options, ok := optionsRaw.(*lua.LTable)
 			if !ok {
-				return // Skip invalid entries
+				return nil, nil // Skip invalid entries
 			}
 
-			var selectOptions []discordgo.SelectMenuOption
+			var optionErr error
 			options.ForEach(func(_, value lua.LValue) {
 				optionTable, ok := value.(*lua.LTable)
-				if !ok {
+				if !ok || optionErr != nil {
 					return // Skip invalid entries
 				}
 
 				optLabel := optionTable.RawGetString("label").String()
 				optValue := optionTable.RawGetString("value").String() // custom id
+				if optionErr = CheckComponentLength("select option label", optLabel, MaxSelectOptionLabelLength); optionErr != nil {
+					return
+				}
+				if optionErr = CheckComponentLength("select option value", optValue, MaxSelectOptionValueLength); optionErr != nil {
+					return
+				}
 
-				selectOptions = append(selectOptions, discordgo.SelectMenuOption{
-					Label: optLabel,
-					Value: optValue,
-				})
+				option := discordgo.SelectMenuOption{
+					Label:   optLabel,
+					Value:   optValue,
+					Default: lua.LVAsBool(optionTable.RawGetString("default")),
+				}
+				if description := optionTable.RawGetString("description"); description.Type() == lua.LTString {
+					option.Description = description.String()
+					if optionErr = CheckComponentLength("select option description", option.Description, MaxSelectOptionDescLength); optionErr != nil {
+						return
+					}
+				}
+				if emoji := optionTable.RawGetString("emoji"); emoji.Type() == lua.LTString {
+					option.Emoji = ParseComponentEmoji(emoji.String())
+				}
+				menu.Options = append(menu.Options, option)
 			})
+			if optionErr != nil {
+				return nil, optionErr
+			}
+		} else if defaultsRaw, ok := componentTable.RawGetString("default_values").(*lua.LTable); ok {
+			menu.DefaultValues = parseSelectDefaultValues(menuType, defaultsRaw)
+		}
 
-			components = append(components, discordgo.SelectMenu{
-				Placeholder: placeholder,
-				CustomID:    customID,
-				Options:     selectOptions,
-				Disabled:    disabled,
-			})
+		return menu, nil
+	case "text_display":
+		content, ok := componentTable.RawGetString("content").(lua.LString)
+		if !ok || content == "" {
+			return nil, fmt.Errorf("text_display content must be a non-empty string")
+		}
+		if err := CheckComponentLength("text_display content", string(content), MaxTextDisplayLength); err != nil {
+			return nil, err
+		}
+		return discordgo.TextDisplay{Content: string(content)}, nil
+	case "container":
+		if !allowContainers {
+			return nil, fmt.Errorf("containers cannot be nested")
+		}
 
-		default:
-			return
+		childrenRaw, ok := componentTable.RawGetString("components").(*lua.LTable)
+		if !ok {
+			return nil, nil // Skip invalid entries
+		}
+		children, err := parseComponentList(childrenRaw, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(children) == 0 {
+			return nil, fmt.Errorf("container has no valid components")
 		}
-	})
 
-	// Wrap components in an action row
-	if len(components) > 0 {
-		return []discordgo.MessageComponent{
-			discordgo.ActionsRow{Components: components},
-		}, nil
+		container := discordgo.Container{
+			Components: layoutComponents(children),
+			Spoiler:    lua.LVAsBool(componentTable.RawGetString("spoiler")),
+		}
+		if accentColor, ok := componentTable.RawGetString("accent_color").(lua.LNumber); ok {
+			color := int(accentColor)
+			container.AccentColor = &color
+		}
+		return container, nil
+	default:
+		return nil, nil
+	}
+}
+
+// ParseComponentEmoji parses the emoji of a component. It is either a unicode
+// emoji, or a custom emoji as "name:id", "<:name:id>" or "<a:name:id>" for an
+// animated one.
+func ParseComponentEmoji(value string) *discordgo.ComponentEmoji {
+	emoji := &discordgo.ComponentEmoji{}
+	if strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") {
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		if rest, ok := strings.CutPrefix(value, "a:"); ok {
+			emoji.Animated = true
+			value = rest
+		}
+		value = strings.TrimPrefix(value, ":")
+	}
+
+	if name, id, ok := strings.Cut(value, ":"); ok {
+		emoji.Name, emoji.ID = name, id
+	} else {
+		emoji.Name = value
+	}
+	return emoji
+}
+
+// FormatComponentEmoji formats a component emoji the way ParseComponentEmoji
+// takes it.
+func FormatComponentEmoji(emoji *discordgo.ComponentEmoji) string {
+	switch {
+	case emoji.ID == "":
+		return emoji.Name
+	case emoji.Animated:
+		return fmt.Sprintf("<a:%s:%s>", emoji.Name, emoji.ID)
+	default:
+		return fmt.Sprintf("%s:%s", emoji.Name, emoji.ID)
 	}
+}
+
+// selectMenuTypes maps the "menu_type" field of a select component to the
+// Discord select menu type. Omitting the field creates a string select.
+var selectMenuTypes = map[string]discordgo.SelectMenuType{
+	"string":      discordgo.StringSelectMenu,
+	"user":        discordgo.UserSelectMenu,
+	"role":        discordgo.RoleSelectMenu,
+	"channel":     discordgo.ChannelSelectMenu,
+	"mentionable": discordgo.MentionableSelectMenu,
+}
 
-	return nil, fmt.Errorf("no valid components found")
+// parseSelectDefaultValues parses the pre-selected entities of a user, role,
+// channel or mentionable select. Entries are IDs, or for mentionable selects
+// tables with an "id" and a "type" of "user" or "role".
+func parseSelectDefaultValues(menuType discordgo.SelectMenuType, table *lua.LTable) []discordgo.SelectMenuDefaultValue {
+	var valueType discordgo.SelectMenuDefaultValueType
+	switch menuType {
+	case discordgo.UserSelectMenu:
+		valueType = discordgo.SelectMenuDefaultValueUser
+	case discordgo.RoleSelectMenu:
+		valueType = discordgo.SelectMenuDefaultValueRole
+	case discordgo.ChannelSelectMenu:
+		valueType = discordgo.SelectMenuDefaultValueChannel
+	}
+
+	var defaults []discordgo.SelectMenuDefaultValue
+	table.ForEach(func(_, value lua.LValue) {
+		switch v := value.(type) {
+		case lua.LString:
+			if valueType == "" {
+				return // Mentionable defaults need an explicit type
+			}
+			defaults = append(defaults, discordgo.SelectMenuDefaultValue{ID: string(v), Type: valueType})
+		case *lua.LTable:
+			entryType := discordgo.SelectMenuDefaultValueType(v.RawGetString("type").String())
+			if v.RawGetString("type") == lua.LNil {
+				entryType = valueType
+			}
+			if entryType == "" {
+				return // Skip invalid entries
+			}
+			defaults = append(defaults, discordgo.SelectMenuDefaultValue{ID: v.RawGetString("id").String(), Type: entryType})
+		}
+	})
+	return defaults
 }
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestParseSelectOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  string // Lua table of a single select option
+		want    discordgo.SelectMenuOption
+		wantErr string
+	}{
+		{
+			name:   "label and value",
+			option: `{ label = "Red", value = "red" }`,
+			want:   discordgo.SelectMenuOption{Label: "Red", Value: "red"},
+		},
+		{
+			name:   "description and default",
+			option: `{ label = "Red", value = "red", description = "A warm color", default = true }`,
+			want:   discordgo.SelectMenuOption{Label: "Red", Value: "red", Description: "A warm color", Default: true},
+		},
+		{
+			name:   "unicode emoji",
+			option: `{ label = "Red", value = "red", emoji = "🔴" }`,
+			want:   discordgo.SelectMenuOption{Label: "Red", Value: "red", Emoji: &discordgo.ComponentEmoji{Name: "🔴"}},
+		},
+		{
+			name:   "custom emoji as name:id",
+			option: `{ label = "Red", value = "red", emoji = "red_dot:123" }`,
+			want:   discordgo.SelectMenuOption{Label: "Red", Value: "red", Emoji: &discordgo.ComponentEmoji{Name: "red_dot", ID: "123"}},
+		},
+		{
+			name:   "custom emoji in message format",
+			option: `{ label = "Red", value = "red", emoji = "<:red_dot:123>" }`,
+			want:   discordgo.SelectMenuOption{Label: "Red", Value: "red", Emoji: &discordgo.ComponentEmoji{Name: "red_dot", ID: "123"}},
+		},
+		{
+			name:   "animated custom emoji",
+			option: `{ label = "Red", value = "red", emoji = "<a:red_spin:456>" }`,
+			want:   discordgo.SelectMenuOption{Label: "Red", Value: "red", Emoji: &discordgo.ComponentEmoji{Name: "red_spin", ID: "456", Animated: true}},
+		},
+		{
+			name:   "limits count characters, not bytes",
+			option: `{ label = string.rep("é", 100), value = string.rep("é", 100), description = string.rep("é", 100) }`,
+			want:   discordgo.SelectMenuOption{Label: strings.Repeat("é", 100), Value: strings.Repeat("é", 100), Description: strings.Repeat("é", 100)},
+		},
+		{
+			name:    "label too long",
+			option:  `{ label = string.rep("a", 101), value = "red" }`,
+			wantErr: "select option label must be 100 characters or fewer, got 101",
+		},
+		{
+			name:    "value too long",
+			option:  `{ label = "Red", value = string.rep("a", 101) }`,
+			wantErr: "select option value must be 100 characters or fewer, got 101",
+		},
+		{
+			name:    "description too long",
+			option:  `{ label = "Red", value = "red", description = string.rep("a", 101) }`,
+			wantErr: "select option description must be 100 characters or fewer, got 101",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			L := lua.NewState()
+			defer L.Close()
+			if err := L.DoString(`return { { type = "select", placeholder = "Pick", custom_id = "pick", options = { ` + tt.option + ` } } }`); err != nil {
+				t.Fatal(err)
+			}
+			table := L.CheckTable(-1)
+
+			components, err := ParseComponents(L, table)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			row, ok := components[0].(discordgo.ActionsRow)
+			if !ok || len(row.Components) != 1 {
+				t.Fatalf("got components %#v, want one action row holding the select", components)
+			}
+			menu, ok := row.Components[0].(discordgo.SelectMenu)
+			if !ok || len(menu.Options) != 1 {
+				t.Fatalf("got %#v, want a select with one option", row.Components[0])
+			}
+			if got := menu.Options[0]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got option %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTextDisplay(t *testing.T) {
+	tests := []struct {
+		name      string
+		component string // Lua table of a single text display
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "content",
+			component: `{ type = "text_display", content = "Hello" }`,
+			want:      "Hello",
+		},
+		{
+			name:      "limit counts characters, not bytes",
+			component: `{ type = "text_display", content = string.rep("é", 4000) }`,
+			want:      strings.Repeat("é", 4000),
+		},
+		{
+			name:      "missing content",
+			component: `{ type = "text_display" }`,
+			wantErr:   "text_display content must be a non-empty string",
+		},
+		{
+			name:      "empty content",
+			component: `{ type = "text_display", content = "" }`,
+			wantErr:   "text_display content must be a non-empty string",
+		},
+		{
+			name:      "number content",
+			component: `{ type = "text_display", content = 42 }`,
+			wantErr:   "text_display content must be a non-empty string",
+		},
+		{
+			name:      "table content",
+			component: `{ type = "text_display", content = { "Hello" } }`,
+			wantErr:   "text_display content must be a non-empty string",
+		},
+		{
+			name:      "content too long",
+			component: `{ type = "text_display", content = string.rep("a", 4001) }`,
+			wantErr:   "text_display content must be 4000 characters or fewer, got 4001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			L := lua.NewState()
+			defer L.Close()
+			if err := L.DoString(`return { ` + tt.component + ` }`); err != nil {
+				t.Fatal(err)
+			}
+			table := L.CheckTable(-1)
+
+			components, err := ParseComponents(L, table)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(components) != 1 {
+				t.Fatalf("got components %#v, want one text display", components)
+			}
+			display, ok := components[0].(discordgo.TextDisplay)
+			if !ok {
+				t.Fatalf("got %#v, want a text display", components[0])
+			}
+			if display.Content != tt.want {
+				t.Errorf("got content %q, want %q", display.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestComponentEmojiRoundTrip(t *testing.T) {
+	for _, value := range []string{"🔴", "red_dot:123", "<a:red_spin:456>"} {
+		if got := FormatComponentEmoji(ParseComponentEmoji(value)); got != value {
+			t.Errorf("emoji %q formats back as %q", value, got)
+		}
+	}
+	// The message format of a custom emoji that isn't animated is normalised to name:id
+	if got := FormatComponentEmoji(ParseComponentEmoji("<:red_dot:123>")); got != "red_dot:123" {
+		t.Errorf("emoji <:red_dot:123> formats back as %q, want red_dot:123", got)
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	lua "github.com/yuin/gopher-lua"
@@ -104,6 +105,22 @@ func ParseEmbed(L *lua.LState, table *lua.LTable) (*discordgo.MessageEmbed, erro
 		}
 	}
 
+	// Timestamp (expects an RFC 3339 string or a Unix timestamp in seconds)
+	timestampRaw := table.RawGetString("timestamp")
+	if timestampRaw != lua.LNil {
+		switch ts := timestampRaw.(type) {
+		case lua.LNumber:
+			embed.Timestamp = time.Unix(int64(ts), 0).UTC().Format(time.RFC3339)
+		case lua.LString:
+			if _, err := time.Parse(time.RFC3339, string(ts)); err != nil {
+				return nil, fmt.Errorf("embed.timestamp must be an RFC 3339 date, e.g. 2024-01-02T15:04:05Z")
+			}
+			embed.Timestamp = string(ts)
+		default:
+			return nil, fmt.Errorf("embed.timestamp must be an RFC 3339 string or a Unix timestamp")
+		}
+	}
+
 	// Fields (expects an array/table of field tables with keys "name", "value", and optional "inline")
 	fieldsRaw := table.RawGetString("fields")
 	if fieldsRaw != lua.LNil {
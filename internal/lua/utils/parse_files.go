@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxFilesPerMessage is the number of attachments Discord accepts on a message.
+const maxFilesPerMessage = 10
+
+// ParseFiles parses a Lua array of file tables into Discord file uploads.
+// Each table expects a "name" and the raw "content" as a string, with an
+// optional "content_type". When omitted, the type is guessed from the file
+// extension, falling back to application/octet-stream.
+func ParseFiles(_ *lua.LState, table *lua.LTable) ([]*discordgo.File, error) {
+	var files []*discordgo.File
+	var parseErr error
+
+	table.ForEach(func(_, value lua.LValue) {
+		if parseErr != nil {
+			return
+		}
+
+		fileTable, ok := value.(*lua.LTable)
+		if !ok {
+			parseErr = fmt.Errorf("files must be tables")
+			return
+		}
+
+		name := fileTable.RawGetString("name")
+		if name.Type() != lua.LTString || name.String() == "" {
+			parseErr = fmt.Errorf("file 'name' must be a non-empty string")
+			return
+		}
+
+		content := fileTable.RawGetString("content")
+		if content.Type() != lua.LTString {
+			parseErr = fmt.Errorf("file '%s' must have a string 'content'", name.String())
+			return
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(name.String()))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		if ct := fileTable.RawGetString("content_type"); ct != lua.LNil {
+			if ct.Type() != lua.LTString || ct.String() == "" {
+				parseErr = fmt.Errorf("file '%s' must have a string 'content_type'", name.String())
+				return
+			}
+			contentType = ct.String()
+		}
+
+		files = append(files, &discordgo.File{
+			Name:        name.String(),
+			ContentType: contentType,
+			Reader:      strings.NewReader(content.String()),
+		})
+	})
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if len(files) > maxFilesPerMessage {
+		return nil, fmt.Errorf("at most %d files can be attached, got %d", maxFilesPerMessage, len(files))
+	}
+
+	return files, nil
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	pollMaxAnswers        = 10
+	pollMaxQuestionLength = 300
+	pollMaxAnswerLength   = 55
+	pollMinDurationHours  = 1
+	pollMaxDurationHours  = 768 // 32 days
+	pollDefaultDuration   = 24
+)
+
+// ParsePoll parses a Lua table into a Discord native poll.
+//
+// The table expects a "question" string, an "answers" array of strings (or
+// tables with "text" and optional "emoji"), and optional "duration_hours" and
+// "allow_multiselect" fields.
+func ParsePoll(_ *lua.LState, table *lua.LTable) (*discordgo.Poll, error) {
+	question := table.RawGetString("question")
+	if question.Type() != lua.LTString || question.String() == "" {
+		return nil, fmt.Errorf("poll.question must be a non-empty string")
+	}
+	if utf8.RuneCountInString(question.String()) > pollMaxQuestionLength {
+		return nil, fmt.Errorf("poll.question must be %d characters or fewer", pollMaxQuestionLength)
+	}
+
+	answersTable, ok := table.RawGetString("answers").(*lua.LTable)
+	if !ok {
+		return nil, fmt.Errorf("poll.answers must be a table")
+	}
+
+	var answers []discordgo.PollAnswer
+	var parseErr error
+	answersTable.ForEach(func(_, value lua.LValue) {
+		if parseErr != nil {
+			return
+		}
+
+		media := &discordgo.PollMedia{}
+		switch v := value.(type) {
+		case lua.LString:
+			media.Text = string(v)
+		case *lua.LTable:
+			media.Text = v.RawGetString("text").String()
+			if emoji := v.RawGetString("emoji"); emoji.Type() == lua.LTString {
+				media.Emoji = &discordgo.ComponentEmoji{Name: emoji.String()}
+			}
+		default:
+			parseErr = fmt.Errorf("poll.answers entries must be strings or tables")
+			return
+		}
+
+		if media.Text == "" || utf8.RuneCountInString(media.Text) > pollMaxAnswerLength {
+			parseErr = fmt.Errorf("poll answers must be between 1 and %d characters", pollMaxAnswerLength)
+			return
+		}
+		answers = append(answers, discordgo.PollAnswer{Media: media})
+	})
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	if len(answers) == 0 || len(answers) > pollMaxAnswers {
+		return nil, fmt.Errorf("poll must have between 1 and %d answers, got %d", pollMaxAnswers, len(answers))
+	}
+
+	duration := pollDefaultDuration
+	durationRaw := table.RawGetString("duration_hours")
+	if durationRaw != lua.LNil {
+		if durationRaw.Type() != lua.LTNumber {
+			return nil, fmt.Errorf("poll.duration_hours must be a number")
+		}
+		duration = int(durationRaw.(lua.LNumber))
+		if duration < pollMinDurationHours || duration > pollMaxDurationHours {
+			return nil, fmt.Errorf("poll.duration_hours must be between %d and %d", pollMinDurationHours, pollMaxDurationHours)
+		}
+	}
+
+	multiselect := false
+	multiselectRaw := table.RawGetString("allow_multiselect")
+	if multiselectRaw.Type() == lua.LTBool {
+		multiselect = lua.LVAsBool(multiselectRaw)
+	}
+
+	return &discordgo.Poll{
+		Question:         discordgo.PollMedia{Text: question.String()},
+		Answers:          answers,
+		AllowMultiselect: multiselect,
+		Duration:         duration,
+	}, nil
+}
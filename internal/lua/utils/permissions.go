@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"github.com/bwmarrin/discordgo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// PermissionNames maps Discord's names for permissions to their bits.
+var PermissionNames = map[string]int64{
+	"create_instant_invite":               discordgo.PermissionCreateInstantInvite,
+	"kick_members":                        discordgo.PermissionKickMembers,
+	"ban_members":                         discordgo.PermissionBanMembers,
+	"administrator":                       discordgo.PermissionAdministrator,
+	"manage_channels":                     discordgo.PermissionManageChannels,
+	"manage_guild":                        discordgo.PermissionManageGuild,
+	"add_reactions":                       discordgo.PermissionAddReactions,
+	"view_audit_log":                      discordgo.PermissionViewAuditLogs,
+	"priority_speaker":                    discordgo.PermissionVoicePrioritySpeaker,
+	"stream":                              discordgo.PermissionVoiceStreamVideo,
+	"view_channel":                        discordgo.PermissionViewChannel,
+	"send_messages":                       discordgo.PermissionSendMessages,
+	"send_tts_messages":                   discordgo.PermissionSendTTSMessages,
+	"manage_messages":                     discordgo.PermissionManageMessages,
+	"embed_links":                         discordgo.PermissionEmbedLinks,
+	"attach_files":                        discordgo.PermissionAttachFiles,
+	"read_message_history":                discordgo.PermissionReadMessageHistory,
+	"mention_everyone":                    discordgo.PermissionMentionEveryone,
+	"use_external_emojis":                 discordgo.PermissionUseExternalEmojis,
+	"view_guild_insights":                 discordgo.PermissionViewGuildInsights,
+	"connect":                             discordgo.PermissionVoiceConnect,
+	"speak":                               discordgo.PermissionVoiceSpeak,
+	"mute_members":                        discordgo.PermissionVoiceMuteMembers,
+	"deafen_members":                      discordgo.PermissionVoiceDeafenMembers,
+	"move_members":                        discordgo.PermissionVoiceMoveMembers,
+	"use_vad":                             discordgo.PermissionVoiceUseVAD,
+	"change_nickname":                     discordgo.PermissionChangeNickname,
+	"manage_nicknames":                    discordgo.PermissionManageNicknames,
+	"manage_roles":                        discordgo.PermissionManageRoles,
+	"manage_webhooks":                     discordgo.PermissionManageWebhooks,
+	"manage_guild_expressions":            discordgo.PermissionManageGuildExpressions,
+	"use_application_commands":            discordgo.PermissionUseApplicationCommands,
+	"request_to_speak":                    discordgo.PermissionVoiceRequestToSpeak,
+	"manage_events":                       discordgo.PermissionManageEvents,
+	"manage_threads":                      discordgo.PermissionManageThreads,
+	"create_public_threads":               discordgo.PermissionCreatePublicThreads,
+	"create_private_threads":              discordgo.PermissionCreatePrivateThreads,
+	"use_external_stickers":               discordgo.PermissionUseExternalStickers,
+	"send_messages_in_threads":            discordgo.PermissionSendMessagesInThreads,
+	"use_embedded_activities":             discordgo.PermissionUseEmbeddedActivities,
+	"moderate_members":                    discordgo.PermissionModerateMembers,
+	"view_creator_monetization_analytics": discordgo.PermissionViewCreatorMonetizationAnalytics,
+	"use_soundboard":                      discordgo.PermissionUseSoundboard,
+	"create_guild_expressions":            discordgo.PermissionCreateGuildExpressions,
+	"create_events":                       discordgo.PermissionCreateEvents,
+	"use_external_sounds":                 discordgo.PermissionUseExternalSounds,
+	"send_voice_messages":                 discordgo.PermissionSendVoiceMessages,
+	"send_polls":                          discordgo.PermissionSendPolls,
+	"use_external_apps":                   discordgo.PermissionUseExternalApps,
+}
+
+// PreparePermissionsTable converts a permission bit set into a Lua table
+// mapping every permission name to whether it is granted. Administrators are
+// granted every permission.
+func PreparePermissionsTable(L *lua.LState, permissions int64) *lua.LTable {
+	administrator := permissions&discordgo.PermissionAdministrator != 0
+
+	permissionsTable := L.NewTable()
+	for name, bit := range PermissionNames {
+		permissionsTable.RawSetString(name, lua.LBool(administrator || permissions&bit == bit))
+	}
+	return permissionsTable
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// DefaultSendRetries is how often a rate limited message request is
+	// retried unless configured.
+	DefaultSendRetries = 3
+
+	// rateLimitBackoff is the least time waited before the first retry, doubled
+	// for every retry after it.
+	rateLimitBackoff = 500 * time.Millisecond
+
+	// maxRateLimitBackoff caps the doubling, so a long retry chain doesn't
+	// stall the Lua runner for minutes.
+	maxRateLimitBackoff = 30 * time.Second
+)
+
+// sendRetries is how often rate limited message requests are retried.
+var sendRetries atomic.Int64
+
+func init() {
+	sendRetries.Store(DefaultSendRetries)
+}
+
+// SetSendRetries sets how often a rate limited message request is retried
+// before the binding gives up, 0 to not retry.
+func SetSendRetries(retries int) {
+	sendRetries.Store(int64(retries))
+}
+
+// RetryRateLimited makes a Discord request, retrying it when Discord rate
+// limits it. The request must pass the given options on to discordgo, which
+// then reports rate limits instead of waiting them out itself. Each retry
+// waits what Discord asked for, but at least an exponentially growing
+// backoff. Any other error is permanent and returned straight away, as is
+// the rate limit once the retries run out.
+func RetryRateLimited(request func(options ...discordgo.RequestOption) error) error {
+	retries := int(sendRetries.Load())
+	for attempt := 0; ; attempt++ {
+		err := request(discordgo.WithRetryOnRatelimit(false))
+
+		var rateLimited *discordgo.RateLimitError
+		if !errors.As(err, &rateLimited) || attempt >= retries {
+			return err
+		}
+
+		wait := min(rateLimitBackoff<<attempt, maxRateLimitBackoff)
+		if rateLimited.TooManyRequests != nil {
+			wait = max(wait, rateLimited.RetryAfter)
+		}
+		slog.Warn("Rate limited, retrying", "url", rateLimited.URL, "attempt", attempt+1, "retries", retries, "wait", wait)
+		time.Sleep(wait)
+	}
+}
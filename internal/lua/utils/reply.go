@@ -9,8 +9,10 @@ import (
 )
 
 // replyFunction returns a Lua function for replying to interactions.
-// This utility can be used across multiple bindings.
-func ReplyFunction(session *discordgo.Session, interaction *discordgo.InteractionCreate) lua.LGFunction {
+// This utility can be used across multiple bindings. If the interaction was
+// deferred the deferred response is filled in, and if it was already replied
+// to the reply is sent as a followup instead.
+func ReplyFunction(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState) lua.LGFunction {
 	return func(L *lua.LState) int {
 		argCount := L.GetTop()
 		var message string
@@ -31,8 +33,12 @@ func ReplyFunction(session *discordgo.Session, interaction *discordgo.Interactio
 		}
 
 		ephemeral := false
+		ephemeralSet := false
 		mention := true
+		tts := false
 		var embeds []*discordgo.MessageEmbed
+		var files []*discordgo.File
+		var components []discordgo.MessageComponent
 
 		if options != nil {
 			if options.RawGetString("ephemeral") != lua.LNil {
@@ -41,6 +47,7 @@ func ReplyFunction(session *discordgo.Session, interaction *discordgo.Interactio
 					return 0
 				}
 				ephemeral = lua.LVAsBool(options.RawGetString("ephemeral"))
+				ephemeralSet = true
 			}
 			if options.RawGetString("mention") != lua.LNil {
 				if options.RawGetString("mention").Type() != lua.LTBool {
@@ -49,6 +56,13 @@ func ReplyFunction(session *discordgo.Session, interaction *discordgo.Interactio
 				}
 				mention = lua.LVAsBool(options.RawGetString("mention"))
 			}
+			if options.RawGetString("tts") != lua.LNil {
+				if options.RawGetString("tts").Type() != lua.LTBool {
+					L.ArgError(1, "'tts' in options must be a boolean")
+					return 0
+				}
+				tts = lua.LVAsBool(options.RawGetString("tts"))
+			}
 
 			// Check for an embed
 			embedRaw := options.RawGetString("embed")
@@ -66,10 +80,51 @@ func ReplyFunction(session *discordgo.Session, interaction *discordgo.Interactio
 				}
 				embeds = append(embeds, embed)
 			}
+
+			// Check for components, e.g. buttons posted once a deferred
+			// command has fetched its data
+			componentsRaw := options.RawGetString("components")
+			if componentsRaw != lua.LNil {
+				componentsTable, ok := componentsRaw.(*lua.LTable)
+				if !ok {
+					L.ArgError(1, "'components' in options must be a table")
+					return 0
+				}
+				parsed, err := ParseComponents(L, componentsTable)
+				if err != nil {
+					L.ArgError(1, fmt.Sprintf("invalid components: %s", err.Error()))
+					return 0
+				}
+				// Layout components need a message flag, which neither
+				// edits nor replies with a mention prefix can carry
+				if UsesComponentsV2(parsed) {
+					L.ArgError(1, "layout components are not supported in replies, use followup instead")
+					return 0
+				}
+				components = parsed
+			}
+
+			// Check for file attachments
+			filesRaw := options.RawGetString("files")
+			if filesRaw != lua.LNil {
+				filesTable, ok := filesRaw.(*lua.LTable)
+				if !ok {
+					L.ArgError(1, "'files' in options must be a table")
+					return 0
+				}
+				parsed, err := ParseFiles(L, filesTable)
+				if err != nil {
+					L.ArgError(1, fmt.Sprintf("invalid files: %s", err.Error()))
+					return 0
+				}
+				files = parsed
+			}
 		}
 
+		allowedMentions := AllowedMentions(parseAllowEveryone(L, options, 1))
+
 		if mention {
-			message = fmt.Sprintf("<@%s> %s", interaction.Member.User.ID, message)
+			message = fmt.Sprintf("<@%s> %s", InteractionUserID(interaction), message)
 		}
 
 		flags := discordgo.MessageFlags(0)
@@ -77,15 +132,62 @@ func ReplyFunction(session *discordgo.Session, interaction *discordgo.Interactio
 			flags = discordgo.MessageFlagsEphemeral
 		}
 
-		if err := session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: message,
-				Flags:   flags,
-				Embeds:  embeds,
-			},
-		}); err != nil {
-			slog.Error("Failed to send interaction reply", "error", err)
+		// A reply asking for the other visibility than the defer can't fill
+		// in the deferred response, so it is sent as a followup instead
+		if state.Deferred() && ephemeralSet && ephemeral != state.Ephemeral() {
+			if err := discardDeferred(session, interaction, state); err != nil {
+				slog.Error("Failed to discard deferred response before reply", "error", err)
+				return 0
+			}
+		}
+
+		switch {
+		case state.Deferred():
+			// The deferred response keeps the visibility chosen when deferring,
+			// and edits can't be read aloud, so tts does not apply here.
+			edit := &discordgo.WebhookEdit{
+				Content:         &message,
+				Embeds:          &embeds,
+				Files:           files,
+				AllowedMentions: allowedMentions,
+			}
+			if components != nil {
+				edit.Components = &components
+			}
+			if _, err := session.InteractionResponseEdit(interaction.Interaction, edit); err != nil {
+				slog.Error("Failed to fill in deferred interaction reply", "error", err)
+				return 0
+			}
+			state.MarkResponded(state.Ephemeral())
+		case state.Responded():
+			if _, err := session.FollowupMessageCreate(interaction.Interaction, true, &discordgo.WebhookParams{
+				Content:         message,
+				Flags:           flags,
+				Embeds:          embeds,
+				Components:      components,
+				Files:           files,
+				TTS:             tts,
+				AllowedMentions: allowedMentions,
+			}); err != nil {
+				slog.Error("Failed to send interaction reply as followup", "error", err)
+			}
+		default:
+			if err := session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content:         message,
+					Flags:           flags,
+					Embeds:          embeds,
+					Components:      components,
+					Files:           files,
+					TTS:             tts,
+					AllowedMentions: allowedMentions,
+				},
+			}); err != nil {
+				slog.Error("Failed to send interaction reply", "error", err)
+				return 0
+			}
+			state.MarkResponded(ephemeral)
 		}
 
 		return 0
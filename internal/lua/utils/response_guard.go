@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// UnansweredCommandMessage is sent when a command handler returns without
+// replying to or deferring its interaction.
+const UnansweredCommandMessage = "This command didn't send a response."
+
+// EnsureResponded acknowledges an interaction the handler left unanswered,
+// so the user sees a soft notice instead of "interaction failed". Commands
+// get an ephemeral message, and components a silent acknowledgement that
+// leaves their message unchanged.
+func EnsureResponded(session *discordgo.Session, interaction *discordgo.InteractionCreate, state *ResponseState, handler string) {
+	if state.Responded() {
+		return
+	}
+
+	slog.Warn("Lua handler returned without responding to the interaction, call reply or defer before returning", "handler", handler)
+
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}
+	if interaction.Type == discordgo.InteractionApplicationCommand {
+		response = &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: UnansweredCommandMessage,
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}
+	}
+
+	if err := session.InteractionRespond(interaction.Interaction, response); err != nil {
+		slog.Error("Failed to acknowledge unanswered interaction", "handler", handler, "error", err)
+		return
+	}
+	state.MarkResponded(true)
+}
@@ -0,0 +1,59 @@
+package utils
+
+import "sync"
+
+// ResponseState tracks how an interaction has been acknowledged, so the
+// response functions on the interaction table can pick the right Discord
+// endpoint. A single state is shared by all functions of one interaction.
+type ResponseState struct {
+	mu        sync.Mutex
+	responded bool // An initial response (reply or defer) has been sent
+	deferred  bool // The initial response was a deferred "thinking" state
+	ephemeral bool // The initial response was ephemeral
+}
+
+// NewResponseState initializes the state of an unacknowledged interaction.
+func NewResponseState() *ResponseState {
+	return &ResponseState{}
+}
+
+// Responded reports whether an initial response has been sent.
+func (rs *ResponseState) Responded() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.responded
+}
+
+// Deferred reports whether the initial response was deferred and has not yet
+// been replaced by a reply.
+func (rs *ResponseState) Deferred() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.deferred
+}
+
+// Ephemeral reports whether the initial response was ephemeral.
+func (rs *ResponseState) Ephemeral() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.ephemeral
+}
+
+// MarkResponded records that an initial response has been sent.
+func (rs *ResponseState) MarkResponded(ephemeral bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.responded = true
+	rs.deferred = false
+	rs.ephemeral = ephemeral
+}
+
+// MarkDeferred records that the interaction was acknowledged with a deferred
+// response that will be filled in later.
+func (rs *ResponseState) MarkDeferred(ephemeral bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.responded = true
+	rs.deferred = true
+	rs.ephemeral = ephemeral
+}
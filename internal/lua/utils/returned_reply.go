@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"log/slog"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// replyOptionKeys are the fields that mark a returned table as a reply with
+// options rather than a bare embed.
+var replyOptionKeys = []string{"content", "embed", "ephemeral", "components", "files", "mention", "tts", "allow_everyone"}
+
+// ReplyWithReturned replies to an interaction with the value its handler
+// returned, so simple handlers can `return "pong"` instead of calling reply.
+// A string is sent as the content, and a table either as the content and
+// options of a reply, or as an embed when it has none of the reply fields.
+// Returned replies don't mention the user unless they ask to. It goes
+// through the reply function of the interaction table, so a deferred
+// response is filled in and an answered one gets a followup.
+func ReplyWithReturned(L *lua.LState, interactionTable *lua.LTable, value lua.LValue, handler string) {
+	reply, ok := interactionTable.RawGetString("reply").(*lua.LFunction)
+	if !ok {
+		return
+	}
+
+	content := lua.LString("")
+	options := L.NewTable()
+	options.RawSetString("mention", lua.LFalse)
+
+	switch v := value.(type) {
+	case lua.LString:
+		content = v
+	case *lua.LTable:
+		if !isReplyTable(v) {
+			options.RawSetString("embed", v)
+			break
+		}
+		v.ForEach(func(key, field lua.LValue) {
+			if key.String() == "content" {
+				return
+			}
+			options.RawSet(key, field)
+		})
+		if raw := v.RawGetString("content"); raw != lua.LNil {
+			text, ok := raw.(lua.LString)
+			if !ok {
+				slog.Warn("Ignoring returned reply, 'content' must be a string", "handler", handler)
+				return
+			}
+			content = text
+		}
+	default:
+		slog.Warn("Ignoring returned value, handlers may return a string or a table to reply with", "handler", handler, "type", value.Type().String())
+		return
+	}
+
+	if err := L.CallByParam(lua.P{
+		Fn:      reply,
+		NRet:    0,
+		Protect: true,
+	}, interactionTable, content, options); err != nil {
+		slog.Error("Failed to reply with returned value", "handler", handler, "error", err)
+	}
+}
+
+// isReplyTable reports whether a returned table sets any reply field.
+func isReplyTable(table *lua.LTable) bool {
+	for _, key := range replyOptionKeys {
+		if table.RawGetString(key) != lua.LNil {
+			return true
+		}
+	}
+	return false
+}
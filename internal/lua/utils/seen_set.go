@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// SeenSet is a thread-safe set of recently seen IDs, each forgotten once its
+// TTL has passed.
+type SeenSet struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	seen     map[string]time.Time
+	prunedAt time.Time
+}
+
+// NewSeenSet initializes an empty set remembering IDs for ttl.
+func NewSeenSet(ttl time.Duration) *SeenSet {
+	return &SeenSet{
+		ttl:      ttl,
+		seen:     make(map[string]time.Time),
+		prunedAt: time.Now(),
+	}
+}
+
+// Seen marks an ID as seen and reports whether it already was within the TTL.
+func (s *SeenSet) Seen(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	// Expired IDs are dropped at most once per TTL, so a busy bot doesn't
+	// walk the whole set on every call
+	if now.Sub(s.prunedAt) > s.ttl {
+		for key, at := range s.seen {
+			if now.Sub(at) > s.ttl {
+				delete(s.seen, key)
+			}
+		}
+		s.prunedAt = now
+	}
+
+	if at, exists := s.seen[id]; exists && now.Sub(at) <= s.ttl {
+		return true
+	}
+	s.seen[id] = now
+	return false
+}
@@ -1,16 +1,31 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	lua "github.com/yuin/gopher-lua"
 )
 
+// saveDelay is how long changes wait before being saved, so a burst of
+// changes rewrites the persistence file once.
+const saveDelay = 2 * time.Second
+
 // StateManager provides a thread-safe mechanism to manage persistent and temporary states.
 type StateManager struct {
-	mu    sync.Mutex
-	store map[string]*stateItem
+	mu        sync.Mutex
+	store     map[string]*stateItem
+	path      string        // File the state is saved to, empty when state is in-memory only
+	saveDelay time.Duration // How long changes wait before being saved together
+	saveTimer *time.Timer   // Pending save, nil when none is scheduled
+	dirty     bool          // Whether the state changed since it was last saved
 }
 
 // stateItem represents an individual state with optional expiry.
@@ -22,37 +37,55 @@ type stateItem struct {
 // NewStateManager initializes a new StateManager.
 func NewStateManager() *StateManager {
 	sm := &StateManager{
-		store: make(map[string]*stateItem),
+		store:     make(map[string]*stateItem),
+		saveDelay: saveDelay,
 	}
 
 	go func(sm *StateManager) {
 		for {
 			time.Sleep(1 * time.Minute)
-			sm.mu.Lock()
-			for key, item := range sm.store {
-				if item.ExpiresAt != nil && time.Now().After(*item.ExpiresAt) {
-					delete(sm.store, key)
-				}
-			}
-			sm.mu.Unlock()
+			sm.removeExpired()
 		}
 	}(sm)
 
 	return sm
 }
 
+// removeExpired deletes every expired item, and saves the state without them.
+func (sm *StateManager) removeExpired() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	removed := 0
+	for key, item := range sm.store {
+		if item.ExpiresAt != nil && time.Now().After(*item.ExpiresAt) {
+			delete(sm.store, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		sm.scheduleSave()
+	}
+}
+
 // Set stores a value with an optional expiry time.
 func (sm *StateManager) Set(key string, value lua.LValue, expirySeconds int) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	sm.store[key] = newStateItem(value, expirySeconds)
+	sm.scheduleSave()
+}
+
+// newStateItem wraps a value, with an expiry when expirySeconds is positive.
+func newStateItem(value lua.LValue, expirySeconds int) *stateItem {
 	var expiresAt *time.Time
 	if expirySeconds > 0 {
 		exp := time.Now().Add(time.Duration(expirySeconds) * time.Second)
 		expiresAt = &exp
 	}
 
-	sm.store[key] = &stateItem{
+	return &stateItem{
 		Value:     value,
 		ExpiresAt: expiresAt,
 	}
@@ -60,20 +93,49 @@ func (sm *StateManager) Set(key string, value lua.LValue, expirySeconds int) {
 
 // Get retrieves a value by key. Returns nil if expired or not found.
 func (sm *StateManager) Get(key string) lua.LValue {
+	item := sm.item(key)
+	if item == nil {
+		return lua.LNil
+	}
+	return item.Value
+}
+
+// item returns the stored item of a key, nil if expired or not found.
+func (sm *StateManager) item(key string) *stateItem {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
 	item, exists := sm.store[key]
 	if !exists {
-		return lua.LNil
+		return nil
 	}
 
 	if item.ExpiresAt != nil && time.Now().After(*item.ExpiresAt) {
 		delete(sm.store, key) // Remove expired item
-		return lua.LNil
+		sm.scheduleSave()
+		return nil
 	}
 
-	return item.Value
+	return item
+}
+
+// Keys returns the keys starting with prefix that have not expired, in no
+// particular order.
+func (sm *StateManager) Keys(prefix string) []string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	var keys []string
+	for key, item := range sm.store {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if item.ExpiresAt != nil && time.Now().After(*item.ExpiresAt) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
 }
 
 // Clear removes a specific key and its value.
@@ -82,4 +144,139 @@ func (sm *StateManager) Clear(key string) {
 	defer sm.mu.Unlock()
 
 	delete(sm.store, key)
+	sm.scheduleSave()
+}
+
+// persistedItem is the on-disk form of a stateItem.
+type persistedItem struct {
+	Value     any        `json:"value"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// EnablePersistence loads previously saved state from the JSON file at path
+// and saves the state back to it shortly after it changes, so values survive
+// restarts. Flush saves pending changes straight away, such as on shutdown. A missing file is treated as empty state. The loaded values are
+// created on the Lua runner, ahead of any script that is queued afterwards.
+func (sm *StateManager) EnablePersistence(path string) error {
+	items := make(map[string]persistedItem)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return fmt.Errorf("failed to parse state file: %w", err)
+		}
+	}
+
+	done := make(chan struct{})
+	GetLuaRunner().Do(func(L *lua.LState) {
+		defer close(done)
+
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+
+		for key, item := range items {
+			if item.ExpiresAt != nil && time.Now().After(*item.ExpiresAt) {
+				continue
+			}
+			sm.store[key] = &stateItem{
+				Value:     GoToLua(L, item.Value),
+				ExpiresAt: item.ExpiresAt,
+			}
+		}
+		sm.path = path
+	})
+	<-done
+
+	slog.Info("State persistence enabled", "path", path, "keys", len(items))
+	return nil
+}
+
+// scheduleSave marks the state as changed and saves it once saveDelay has
+// passed, along with any other change made in the meantime. It must be
+// called with the lock held.
+func (sm *StateManager) scheduleSave() {
+	if sm.path == "" {
+		return
+	}
+
+	sm.dirty = true
+	if sm.saveTimer != nil {
+		return
+	}
+	sm.saveTimer = time.AfterFunc(sm.saveDelay, func() {
+		// The stored tables belong to the Lua runner, so they are encoded there
+		GetLuaRunner().Do(func(L *lua.LState) {
+			sm.flush()
+		})
+	})
+}
+
+// Flush saves pending changes straight away, including those of tasks
+// already queued on the Lua runner. It waits for the runner, so it must not
+// be called from a task running on it.
+func (sm *StateManager) Flush() {
+	done := make(chan struct{})
+	GetLuaRunner().Do(func(L *lua.LState) {
+		defer close(done)
+		sm.flush()
+	})
+	<-done
+}
+
+// flush saves the state if it changed since it was last saved. It must be
+// called on the Lua runner.
+func (sm *StateManager) flush() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.saveTimer != nil {
+		sm.saveTimer.Stop()
+		sm.saveTimer = nil
+	}
+	if sm.dirty {
+		sm.save()
+	}
+}
+
+// save writes the state to the persistence file, if one is configured. It
+// must be called with the lock held. Values that can't be represented
+// outside Lua, such as functions, are left out with a warning.
+func (sm *StateManager) save() {
+	if sm.path == "" {
+		return
+	}
+	sm.dirty = false
+
+	items := make(map[string]persistedItem, len(sm.store))
+	for key, item := range sm.store {
+		value, err := LuaToGo(item.Value)
+		if err != nil {
+			slog.Warn("Skipping state value that can't be saved", "key", key, "error", err)
+			continue
+		}
+		items[key] = persistedItem{Value: value, ExpiresAt: item.ExpiresAt}
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		slog.Error("Failed to encode state", "error", err)
+		return
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated file.
+	if err := os.MkdirAll(filepath.Dir(sm.path), 0o755); err != nil {
+		slog.Error("Failed to create state directory", "path", sm.path, "error", err)
+		return
+	}
+	tmpPath := sm.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		slog.Error("Failed to write state file", "path", tmpPath, "error", err)
+		return
+	}
+	if err := os.Rename(tmpPath, sm.path); err != nil {
+		slog.Error("Failed to replace state file", "path", sm.path, "error", err)
+	}
 }
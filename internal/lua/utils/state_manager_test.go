@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestStateManagerSaves(t *testing.T) {
+	expire := func(sm *StateManager, key string) {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		past := time.Now().Add(-time.Second)
+		sm.store[key].ExpiresAt = &past
+	}
+
+	tests := []struct {
+		name   string
+		delay  time.Duration
+		change func(sm *StateManager)
+		flush  bool // Flush instead of waiting for the delayed save
+		want   map[string]any
+	}{
+		{
+			name:  "burst of changes saved together",
+			delay: 50 * time.Millisecond,
+			change: func(sm *StateManager) {
+				sm.Set("a", lua.LNumber(1), 0)
+				sm.Set("b", lua.LString("two"), 0)
+				sm.Clear("a")
+			},
+			want: map[string]any{"b": "two"},
+		},
+		{
+			name:  "flush saves straight away",
+			delay: time.Hour,
+			change: func(sm *StateManager) {
+				sm.Set("a", lua.LTrue, 0)
+			},
+			flush: true,
+			want:  map[string]any{"a": true},
+		},
+		{
+			name:  "expiry sweep saves without expired items",
+			delay: time.Hour,
+			change: func(sm *StateManager) {
+				sm.Set("a", lua.LNumber(1), 60)
+				sm.Set("b", lua.LNumber(2), 0)
+				sm.Flush()
+				expire(sm, "a")
+				sm.removeExpired()
+			},
+			flush: true,
+			want:  map[string]any{"b": 2.0},
+		},
+		{
+			name:  "reading an expired item saves without it",
+			delay: time.Hour,
+			change: func(sm *StateManager) {
+				sm.Set("a", lua.LNumber(1), 60)
+				sm.Flush()
+				expire(sm, "a")
+				sm.Get("a")
+			},
+			flush: true,
+			want:  map[string]any{},
+		},
+		{
+			name:  "committed transaction",
+			delay: 50 * time.Millisecond,
+			change: func(sm *StateManager) {
+				tx := sm.Begin()
+				tx.Set("a", lua.LString("one"), 0)
+				tx.Set("b", lua.LString("two"), 0)
+				if err := tx.Commit(); err != nil {
+					t.Error(err)
+				}
+			},
+			want: map[string]any{"a": "one", "b": "two"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			sm := NewStateManager()
+			if err := sm.EnablePersistence(path); err != nil {
+				t.Fatal(err)
+			}
+			sm.mu.Lock()
+			sm.saveDelay = tt.delay
+			sm.mu.Unlock()
+
+			tt.change(sm)
+			if tt.flush {
+				sm.Flush()
+			} else {
+				if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+					t.Fatalf("state was saved before the delay: %v", err)
+				}
+				deadline := time.Now().Add(5 * time.Second)
+				for {
+					if _, err := os.Stat(path); err == nil {
+						break
+					}
+					if time.Now().After(deadline) {
+						t.Fatal("state was never saved")
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var saved map[string]persistedItem
+			if err := json.Unmarshal(data, &saved); err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]any, len(saved))
+			for key, item := range saved {
+				got[key] = item.Value
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("saved %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
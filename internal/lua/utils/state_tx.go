@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// StateTx collects state changes that are applied together by Commit. Reads
+// through the transaction see its own pending changes, and nothing is
+// visible to other readers until the transaction is committed.
+type StateTx struct {
+	sm     *StateManager
+	writes map[string]*stateItem // nil marks a cleared key
+	reads  map[string]*stateItem // Item each key held when first read, nil when it had none
+}
+
+// Begin starts a new transaction on the state.
+func (sm *StateManager) Begin() *StateTx {
+	return &StateTx{
+		sm:     sm,
+		writes: make(map[string]*stateItem),
+		reads:  make(map[string]*stateItem),
+	}
+}
+
+// Get retrieves a value by key, preferring the transaction's pending changes.
+// A key reads the same value for the rest of the transaction. Tables are
+// copied on first read and the copy is committed in their place, so changes
+// made to them in place are discarded along with the transaction.
+func (tx *StateTx) Get(L *lua.LState, key string) lua.LValue {
+	if item, written := tx.writes[key]; written {
+		if item == nil {
+			return lua.LNil
+		}
+		return item.Value
+	}
+
+	item, read := tx.reads[key]
+	if !read {
+		item = tx.sm.item(key)
+		tx.reads[key] = item
+	}
+	if item == nil {
+		return lua.LNil
+	}
+
+	if table, ok := item.Value.(*lua.LTable); ok {
+		copied := &stateItem{
+			Value:     copyTable(L, table, make(map[*lua.LTable]*lua.LTable)),
+			ExpiresAt: item.ExpiresAt,
+		}
+		tx.writes[key] = copied
+		return copied.Value
+	}
+	return item.Value
+}
+
+// copyTable deep copies a table, keeping tables it refers to more than once,
+// or itself, shared in the same way in the copy.
+func copyTable(L *lua.LState, table *lua.LTable, copies map[*lua.LTable]*lua.LTable) *lua.LTable {
+	if copied, ok := copies[table]; ok {
+		return copied
+	}
+
+	copied := L.NewTable()
+	copies[table] = copied
+	copied.Metatable = table.Metatable
+	table.ForEach(func(key, value lua.LValue) {
+		if nested, ok := key.(*lua.LTable); ok {
+			key = copyTable(L, nested, copies)
+		}
+		if nested, ok := value.(*lua.LTable); ok {
+			value = copyTable(L, nested, copies)
+		}
+		copied.RawSet(key, value)
+	})
+	return copied
+}
+
+// Set records a value with an optional expiry time, applied on commit.
+func (tx *StateTx) Set(key string, value lua.LValue, expirySeconds int) {
+	tx.writes[key] = newStateItem(value, expirySeconds)
+}
+
+// Clear records the removal of a key, applied on commit.
+func (tx *StateTx) Clear(key string) {
+	tx.writes[key] = nil
+}
+
+// Commit applies every change of the transaction at once and saves the
+// state a single time. Nothing is applied when a key the transaction read
+// was changed since, as its changes could be based on the old value.
+func (tx *StateTx) Commit() error {
+	tx.sm.mu.Lock()
+	defer tx.sm.mu.Unlock()
+
+	for key, item := range tx.reads {
+		if tx.sm.store[key] != item {
+			return fmt.Errorf("state key '%s' was changed during the transaction", key)
+		}
+	}
+
+	for key, item := range tx.writes {
+		if item == nil {
+			delete(tx.sm.store, key)
+			continue
+		}
+		tx.sm.store[key] = item
+	}
+	tx.sm.scheduleSave()
+	return nil
+}
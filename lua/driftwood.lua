@@ -8,12 +8,47 @@
 --- Driftwood namespace
 local driftwood = {
     state = {},
+    config = {},
     timer = {},
     log = {},
     option = {},
     message = {},
     reaction = {},
+    reaction_role = {},
     channel = {},
+    guild = {},
+    member = {},
+    role = {},
+    thread = {},
+    forum = {},
+    snowflake = {},
+    time = {},
+    format = {},
+    attachment = {},
+    parse = {},
+    metrics = {},
+    audit = {},
+    presence = {},
+    command = {},
+    random = {},
+    id = {},
+    cooldown = {},
+    cache = {},
+    bot = {},
+    color = {
+        blurple = 0x5865F2,
+        green = 0x57F287,
+        yellow = 0xFEE75C,
+        fuchsia = 0xEB459E,
+        red = 0xED4245,
+        white = 0xFFFFFF,
+        black = 0x23272A,
+        blue = 0x3498DB,
+        orange = 0xE67E22,
+        purple = 0x9B59B6,
+        grey = 0x95A5A6,
+        dark_red = 0x992D22,
+    },
 }
 
 --- Classes
@@ -25,36 +60,160 @@ local driftwood = {
 --- @field global_name string The global name of the user.
 --- @field discriminator string The discriminator of the user.
 --- @field avatar string The avatar URL of the user.
+--- @field bot boolean Whether the user is a bot account, e.g. to refuse commands run by other bots. Read from the member in guilds and from the user in DMs, so it is set either way.
 
 --- Base Interaction class for handling interactions.
 --- @class InteractionBase
---- @field interaction_id string The unique ID of the interaction.
+--- @field interaction_id string The unique ID of the interaction. Redelivered interactions keep their ID, so it can be used to skip duplicates.
+--- @field created_at number When the interaction was created, as a unix timestamp in seconds.
+--- @field expires_at number When the interaction token expires (15 minutes after creation), after which `edit_response`, `delete_response` and `followup` fail.
 --- @field channel_id string The ID of the channel where the interaction occurred.
+--- @field channel_type string The type of that channel: "text", "dm", "group_dm", "voice", "stage", "news", "forum", "media", "public_thread", "private_thread", "news_thread" or "unknown".
+--- @field in_thread boolean Whether the interaction occurred in a thread.
+--- @field app_permissions table<string, boolean> The bot's permissions in the channel, keyed by Discord's permission names such as `manage_messages`. Check these up front to explain what's missing instead of failing partway through.
+--- @field entitlements string[] The SKU IDs the user currently has access to, for gating premium features.
 --- @field user User The user who triggered the interaction.
---- @field reply fun(self: InteractionBase, content: string, options?: InteractionReplyOptions) Replies to the interaction.
+--- @field reply fun(self: InteractionBase, content: string, options?: InteractionReplyOptions) Replies to the interaction. Fills in a deferred response, or sends a followup if already replied.
+--- @field defer fun(self: InteractionBase, options?: InteractionDeferOptions): boolean, string|nil Acknowledges the interaction with a "thinking" state to reply to later.
+--- @field followup fun(self: InteractionBase, content: string, options?: InteractionFollowupOptions): string|nil, Message|string|nil Sends a followup message after a reply or defer, returning its message ID and the sent message (with its jump `link`), or nil and an error, such as the token having expired.
+--- @field edit_response fun(self: InteractionBase, content: string, options?: MessageOptions): boolean, string|nil Edits the original response. Works from timers for up to 15 minutes after the interaction. Editing a deferred response fills it in, so later replies are sent as followups. Returns false with a reason if the token expired or the response was deleted.
+--- @field delete_response fun(self: InteractionBase): boolean, string|nil Deletes the original response. Returns false with a reason if the token expired or the response was already deleted.
 
 --- CommandInteraction class for handling command interactions.
 --- Extends the base Interaction class and includes options.
+--- Handlers should reply or defer before returning. Otherwise the user gets an
+--- ephemeral "This command didn't send a response." and a warning is logged.
 --- @class CommandInteraction : InteractionBase
---- @field options table<string, any> Arguments/options passed to the command interaction.
+--- @field options table<string, string|number|boolean|ResolvedOption> Arguments/options passed to the command interaction. User, channel, role, mentionable and attachment options are `ResolvedOption` tables.
+--- @field args CommandArgument[] The same options in the order they were given, for handlers that don't know their options in advance.
+--- @field command string The invoked command name, with subcommands as `command_subcommand`.
+
+--- CommandArgument class representing one option passed to a command.
+--- @class CommandArgument
+--- @field name string The name of the option.
+--- @field type number The option type, one of the `driftwood.option_*` constants.
+--- @field value string|number|boolean|ResolvedOption The value of the option, as in `options`.
 
 --- EventInteraction class for handling event interactions (e.g., custom IDs).
 --- Extends the base Interaction class and includes data.
+--- Interactions left unanswered when the handler returns are acknowledged
+--- silently, leaving the message unchanged, and a warning is logged.
 --- @class EventInteraction : InteractionBase
 --- @field data table<string, string>|nil Parsed regex groups from the custom ID.
 --- @field values string[]|nil The values selected in a select menu.
+--- @field selected? ResolvedOption[] For user, role, channel and mentionable select menus, the picked entities in the order of `values`, with names filled in from Discord's resolved data. Entries keep their `id` with `resolved = false` when Discord sent no data for them.
+--- @field message Message|nil The message the button or select menu is attached to. Not set on modal submits.
+--- @field defer_update fun(self: EventInteraction): boolean, string|nil Acknowledges a button or select menu without changing its message, to edit it later with edit_response. Not available on modal submits.
 
 --- InteractionReplyOptions class for defining reply options.
 --- @class InteractionReplyOptions
---- @field ephemeral? boolean Whether the reply should be ephemeral (default: false).
+--- @field ephemeral? boolean Whether the reply should be ephemeral (default: false). When filling in a deferred reply it defaults to the visibility of the defer; asking for the other visibility replaces the "thinking" message with a separate reply.
 --- @field mention? boolean Whether to mention the user in the reply (default: true).
---- @field components? InteractionComponents[] Optional components to include in the reply.
+--- @field components? InteractionComponents[] Optional buttons and select menus to include in the reply, also when filling in a deferred reply. Layout components are not supported here, send them with `followup`.
 --- @field embed? MessageEmbed Optional embed to include in the reply.
+--- @field files? MessageFile[] Optional files to attach to the reply, e.g. a generated image.
+--- @field tts? boolean Whether the reply is read aloud with text-to-speech; ignored when filling in a deferred reply (default: false).
+--- @field allow_everyone? boolean Whether `@everyone` and `@here` in the reply ping anyone (default: false). Users and roles are always pinged.
+
+--- InteractionDeferOptions class for defining defer options.
+--- @class InteractionDeferOptions
+--- @field ephemeral? boolean Whether the eventual response should be ephemeral (default: false).
+
+--- InteractionFollowupOptions class for defining followup options.
+--- @class InteractionFollowupOptions
+--- @field ephemeral? boolean Whether the followup should be ephemeral (default: false), independently of the defer, e.g. a private error after a public defer. The "thinking" message of a pending defer with the other visibility is removed.
+--- @field components? InteractionComponents[] Optional components to include in the followup.
+--- @field embed? MessageEmbed Optional embed to include in the followup.
+--- @field files? MessageFile[] Optional files to attach (max 10).
+--- @field allow_everyone? boolean Whether `@everyone` and `@here` in the followup ping anyone (default: false).
+
+--- MessageFile class for defining a file attachment.
+--- @class MessageFile
+--- @field name string The file name, including extension (e.g. "report.pdf").
+--- @field content string The raw file contents.
+--- @field content_type? string The MIME type (default: guessed from the extension, else "application/octet-stream").
 
 --- MessageOptions class for defining message options.
 --- @class MessageOptions
---- @field components? InteractionComponents[] Optional components to include in the message.
+--- @field components? InteractionComponents[] Optional components to include in the message. When editing, these replace the existing components, an empty table removes them, and omitting them keeps them. With layout components such as text displays, the content must be empty and no embed given.
 --- @field embed? MessageEmbed Optional embed to include in the message.
+--- @field poll? MessagePoll Optional native poll to attach to the message.
+--- @field stickers? string[] Optional IDs of up to 3 stickers to send with the message, for `message.add` only. The content may be empty when sending stickers.
+--- @field tts? boolean Whether the message is read aloud with text-to-speech, for `message.add` only (default: false).
+--- @field allow_everyone? boolean Whether `@everyone` and `@here` ping anyone, for `edit_response` only (default: false).
+
+--- MessagePoll class for defining native Discord polls.
+--- @class MessagePoll
+--- @field question string The poll question (up to 300 characters).
+--- @field answers (string|MessagePollAnswer)[] Between 1 and 10 answers (up to 55 characters each).
+--- @field duration_hours? number How long the poll stays open, between 1 and 768 hours (default: 24).
+--- @field allow_multiselect? boolean Whether users may pick more than one answer (default: false).
+
+--- MessagePollAnswer class for defining a poll answer with an emoji.
+--- @class MessagePollAnswer
+--- @field text string The answer text.
+--- @field emoji? string Optional unicode emoji shown next to the answer.
+
+--- PollResults class returned when reading a poll.
+--- @class PollResults
+--- @field question string The poll question.
+--- @field allow_multiselect boolean Whether users may pick more than one answer.
+--- @field finalized boolean Whether the vote counts are final.
+--- @field expiry? number Unix timestamp when the poll closes.
+--- @field answers PollAnswerResult[] The answers with their vote counts.
+
+--- PollAnswerResult class representing the votes for one answer.
+--- @class PollAnswerResult
+--- @field id number The answer ID.
+--- @field text string The answer text.
+--- @field count number The number of votes for the answer.
+
+--- Message class representing a message fetched from Discord.
+--- @class Message
+--- @field id string The ID of the message.
+--- @field channel_id string The ID of the channel containing the message.
+--- @field guild_id string The ID of the guild containing the message.
+--- @field content string The message content.
+--- @field link string The jump link to the message.
+--- @field timestamp number When the message was sent, as a unix timestamp in seconds.
+--- @field edited_timestamp? number When the message was last edited, as a unix timestamp in seconds. Not set if it was never edited.
+--- @field author? MessageAuthor The author of the message.
+--- @field attachments Attachment[] The files uploaded with the message.
+--- @field embeds MessageEmbed[] The embeds of the message, including link previews (with `type` and `provider`).
+--- @field components InteractionComponents[] The buttons and select menus of the message, in display order.
+
+--- MessageAuthor class representing the author of a message.
+--- @class MessageAuthor
+--- @field id string The ID of the author.
+--- @field username string The username of the author.
+--- @field global_name string The global name of the author.
+--- @field bot boolean Whether the author is a bot.
+
+--- Emoji class representing the emoji of a reaction.
+--- @class Emoji
+--- @field name string The name of the emoji, or the emoji itself for unicode emojis.
+--- @field id? string The ID of a custom emoji. Not set for unicode emojis.
+--- @field animated boolean Whether the custom emoji is animated.
+--- @field unicode boolean Whether the emoji is a unicode emoji rather than a custom one.
+--- @field to_string fun(): string Formats the emoji as `reaction.add` accepts it, `name:id` for custom emojis.
+
+--- Reaction class representing a reaction added to or removed from a message.
+--- @class Reaction
+--- @field message_id string The ID of the message reacted to.
+--- @field channel_id string The ID of the channel containing the message.
+--- @field guild_id string The ID of the guild, empty in direct messages.
+--- @field user_id string The ID of the user who reacted.
+--- @field emoji Emoji The emoji reacted with.
+
+--- MessageHistoryOptions class for paging through channel history.
+--- @class MessageHistoryOptions
+--- @field limit? number How many messages to fetch, between 1 and 100 (default: 50).
+--- @field before? string Only fetch messages before this message ID.
+--- @field after? string Only fetch messages after this message ID.
+--- @field author_id? string Only return messages sent by this user.
+--- @field contains? string Only return messages whose content contains this text, ignoring case.
+--- @field has_attachments? boolean Only return messages with attachments.
+--- @field scan? number When filtering, how many messages to look through for matches, between 1 and 1000 (default: 500).
 
 --- MessageEmbed class for defining embeds in messages.
 --- @class MessageEmbed
@@ -72,7 +231,8 @@ local driftwood = {
 --- @field author? table The author information.
 --- @field author.name? string The name of the author.
 --- @field author.url? string The URL for the author.
---- @field author.icon_url? string The URL for the author's icon.
+--- @field author.icon_url? string The URL for the author's icon, e.g. a user's avatar.
+--- @field timestamp? string|number The time shown in the footer, as an RFC 3339 date or a Unix timestamp in seconds.
 --- @field fields? MessageEmbedField[] Array of fields to include in the embed.
 
 --- MessageEmbedField class representing a field within an embed.
@@ -82,37 +242,142 @@ local driftwood = {
 --- @field inline? boolean Whether the field should be displayed inline (default: false).
 
 --- InteractionComponents class for defining message components (e.g., buttons).
+--- Including a "text_display" or "container" switches the message to Discord's
+--- Components V2 layout: it must then have no content or embed, and buttons are
+--- grouped into rows between the layout components.
 --- @class InteractionComponents
---- @field type string The type of component (e.g., "button").
+--- @field type string The type of component: "button", "select", "text_display" or "container".
 --- @field label string The label text for the component.
 --- @field custom_id string The custom ID for the component.
 --- @field disabled? boolean Optional flag to disable the component.
 --- @field style? number Optional style value for buttons.
+--- @field placeholder? string The placeholder text for select menus.
+--- @field menu_type? string The kind of select menu: "string" (default), "user", "role", "channel" or "mentionable".
+--- @field options? SelectOption[] The options of a string select menu.
+--- @field default_values? (string|SelectDefaultValue)[] IDs pre-selected in user, role, channel or mentionable select menus.
+--- @field min_values? number The minimum number of entries a user must select.
+--- @field max_values? number The maximum number of entries a user may select.
+--- @field content? string The markdown text of a text display, required and non-empty for one.
+--- @field components? InteractionComponents[] The components inside a container, which cannot contain another container.
+--- @field accent_color? number The color of the bar along the side of a container.
+--- @field spoiler? boolean Whether a container is blurred until clicked.
+
+--- SelectDefaultValue class for pre-selecting an entity in a mentionable select menu.
+--- @class SelectDefaultValue
+--- @field id string The ID of the user or role.
+--- @field type string The entity type: "user", "role" or "channel".
 
 --- Command class for defining application commands.
 --- @class Command
+--- @field name string The name of the command, 1-32 lowercase letters, numbers, "-" or "_".
+--- @field description string The description of the command, 1-100 characters.
+--- @field options? CommandOption[] Optional array of options or subcommands.
+--- @field handler? fun(interaction: CommandInteraction): string|CommandReply|MessageEmbed|nil Function to handle the command. A returned string or table is sent as the reply, see `CommandReply`.
+--- @field cooldown? number Seconds a user must wait between uses of the command, including its subcommands. Uses in between get an ephemeral reply saying when it is ready.
+--- @field guild_only? boolean Whether the command is refused outside of servers with an ephemeral "This command can only be used in a server.", so its handler can rely on running in a server, e.g. for per-guild config and member lookups. Unlike hiding the command from DMs, this is checked on every use (default: false).
+
+--- CommandReply class for replying by returning from a command handler, e.g.
+--- `return { content = "Done", ephemeral = true }`. It takes the fields of
+--- `InteractionReplyOptions` plus the content. A returned table without any
+--- of these fields is sent as an embed, so `return { title = "Info" }` works
+--- too, and a returned string is sent as the content. Returned replies don't
+--- mention the user unless `mention` is set, fill in a deferred response, and
+--- are sent as a followup when the handler already replied.
+--- @class CommandReply : InteractionReplyOptions
+--- @field content? string The content of the reply.
+
+--- CommandGroup class for declaring a command made of subcommands.
+--- @class CommandGroup
 --- @field name string The name of the command.
 --- @field description string The description of the command.
---- @field options? CommandOption[] Optional array of options or subcommands.
---- @field handler? fun(interaction: CommandInteraction) Function to handle the command.
+--- @field subcommands Subcommand[]|table<string, Subcommand> The subcommands, as an array or keyed by name.
+--- @field cooldown? number Seconds a user must wait between uses of any of the subcommands.
+--- @field guild_only? boolean Whether the subcommands are refused outside of servers, as for `Command`.
+
+--- Subcommand class for a subcommand within a CommandGroup.
+--- @class Subcommand
+--- @field name? string The name of the subcommand, unless keyed by name.
+--- @field description string The description of the subcommand.
+--- @field handler fun(interaction: CommandInteraction): string|CommandReply|MessageEmbed|nil Function to handle the subcommand.
+--- @field options? CommandOption[] Optional options of the subcommand.
 
 --- CommandOption class for defining options within commands.
 --- @class CommandOption
---- @field name string The name of the option or subcommand.
---- @field description string The description of the option or subcommand.
+--- @field name string The name of the option or subcommand, 1-32 lowercase letters, numbers, "-" or "_".
+--- @field description string The description of the option or subcommand, 1-100 characters.
 --- @field type number The type of the option (see `driftwood.option_*`).
 --- @field required? boolean Whether the option is required (default: false).
+--- @field required_if? string|table<string, string|number|boolean> Makes an optional option required only when the named sibling option is given, or when every listed sibling option has the given value, e.g. `{ recurring = true }`. Commands missing it get an ephemeral error instead of running the handler.
 --- @field options? CommandOption[] Optional sub-options for subcommands.
---- @field handler? fun(interaction: CommandInteraction) Optional handler for subcommands.
+--- @field choices? CommandOptionChoice[] Optional predefined choices for string, integer and number options (max 25).
+--- @field handler? fun(interaction: CommandInteraction): string|CommandReply|MessageEmbed|nil Optional handler for subcommands, which may return its reply like a command handler.
+--- @field channel_types? string[] For channel options, the channel types that can be picked, e.g. `{ "forum" }`. Uses the names of `channel_type`, such as "text", "voice", "category", "news", "forum" or "public_thread".
+--- @field autocomplete? fun(interaction: AutocompleteInteraction): (string|CommandOptionChoice)[] Optional function suggesting choices as the user types, for string, integer and number options without `choices`. Names and string values over 100 characters are truncated, values are converted to the option type, and only the first 25 choices are shown.
+--- @field autocomplete_cache? number Seconds to reuse the choices suggested for the same input, so repeated keystrokes don't call `autocomplete` again. The cache is per option and shared by all users (default: no caching).
+
+--- AutocompleteInteraction class passed to autocomplete functions.
+--- @class AutocompleteInteraction
+--- @field command string The invoked command name, with subcommands as `command_subcommand`.
+--- @field focused string The name of the option being typed in.
+--- @field value string What the user has typed so far.
+--- @field options table<string, any> The values of the options filled in so far.
+--- @field args CommandArgument[] The options filled in so far, in the order they were given.
+--- @field channel_id string The ID of the channel the command is being typed in.
+--- @field user_id string The ID of the user typing the command.
+
+--- CommandOptionChoice class for defining a predefined choice of an option.
+--- @class CommandOptionChoice
+--- @field name string The name shown to the user.
+--- @field value string|number The value passed to the handler; must match the option type.
+--- @field name_localizations? table<string, string> Optional names keyed by Discord locale code (e.g. "de", "pt-BR"). Unknown locales fall back to the base name.
+
+--- Attachment class representing an uploaded file, e.g. the value of an attachment option.
+--- @class Attachment
+--- @field id string The ID of the attachment.
+--- @field url string The CDN URL of the attachment, usable with `driftwood.attachment.download`.
+--- @field filename string The name of the file.
+--- @field content_type string The MIME type of the file.
+--- @field size number The size of the file in bytes.
+
+--- ResolvedOption class for user, channel, role, mentionable and attachment option values.
+--- The `id` is always present; the other fields are only set when `resolved` is true.
+--- @class ResolvedOption
+--- @field id string The ID of the selected user, channel, role or attachment.
+--- @field resolved boolean Whether Discord sent the details of the selected object.
+--- @field type? string For channels, the channel type; for mentionables, "user" or "role".
+--- @field name? string The name of the channel or role, or the display name of the user.
+--- @field username? string The username of the user.
+--- @field global_name? string The display name of the user.
+--- @field avatar? string The avatar hash of the user.
+--- @field bot? boolean Whether the user is a bot.
+--- @field nick? string The nickname of the user in the guild.
+--- @field color? number The color of the role.
+--- @field url? string The CDN URL of the attachment.
+--- @field filename? string The name of the attachment.
+--- @field content_type? string The MIME type of the attachment.
+--- @field size? number The size of the attachment in bytes.
 
 --- SelectOption class for defining options within select menus.
 --- @class SelectOption
 --- @field label string The label of the option.
 --- @field value string The value of the option.
+--- @field default? boolean Whether the option is selected when the menu opens (default: false).
+--- @field description? string Text shown under the label, up to 100 characters.
+--- @field emoji? string A unicode emoji, or a custom emoji as `name:id`, `<:name:id>` or `<a:name:id>`.
+
+--- SelectOptionExtras class for the optional fields of a select menu option.
+--- @class SelectOptionExtras
+--- @field default? boolean Whether the option is pre-selected (default: false).
+--- @field description? string Text shown under the label, up to 100 characters.
+--- @field emoji? string A unicode emoji, or a custom emoji as `name:id`, `<:name:id>` or `<a:name:id>`.
 
 --- State Management
 
 --- Set a value in the bot's state with an optional expiry time.
+--- When `STATE_PATH` is set, strings, numbers, booleans and tables of them are
+--- saved to disk and restored on restart. Changes are saved together a couple
+--- of seconds after they are made, and on shutdown; set a table again after
+--- changing it in place so the change is saved.
 --- @param key string The key to store the value under.
 --- @param value any The value to store.
 --- @param expiry? number The expiry time in seconds (optional).
@@ -127,6 +392,46 @@ function driftwood.state.get(key) end
 --- @param key string The key to clear.
 function driftwood.state.clear(key) end
 
+--- StateTransaction class passed to `driftwood.state.transaction` callbacks.
+--- @class StateTransaction
+--- @field get fun(self: StateTransaction, key: string): any|nil Gets a value, including changes made in this transaction. Tables are copies, committed along with the transaction.
+--- @field set fun(self: StateTransaction, key: string, value: any, expiry?: number) Sets a value when the transaction commits.
+--- @field clear fun(self: StateTransaction, key: string) Clears a value when the transaction commits.
+
+--- Apply several state changes together. Changes made through `tx` become
+--- visible all at once when the callback returns, and are discarded if it
+--- raises an error or a value it read was changed elsewhere in the meantime.
+--- Tables read through `tx` can be changed in place, and those changes are
+--- discarded along with the rest.
+---
+--- ```lua
+--- driftwood.state.transaction(function(tx)
+---     tx:set("score:" .. winner, (tx:get("score:" .. winner) or 0) + 1)
+---     tx:set("score:" .. loser, (tx:get("score:" .. loser) or 0) - 1)
+--- end)
+--- ```
+--- @param callback fun(tx: StateTransaction) The function making the changes.
+--- @return boolean committed Whether the changes were committed.
+--- @return string|nil error The error that rolled the transaction back.
+function driftwood.state.transaction(callback) end
+
+--- Configuration Functions
+--- Settings are stored per guild. Inside an interaction handler the guild of
+--- the interaction is used; elsewhere (on_ready, timers) the configured
+--- GUILD_ID is used unless a guild ID is passed explicitly.
+
+--- Get a per-guild configuration value.
+--- @param key string The configuration key.
+--- @param guild_id? string The guild to read from (default: the current guild).
+--- @return any|nil value The stored value, or nil if not set.
+function driftwood.config.get(key, guild_id) end
+
+--- Set a per-guild configuration value. Setting nil removes the key.
+--- @param key string The configuration key.
+--- @param value any The value to store.
+--- @param guild_id? string The guild to write to (default: the current guild).
+function driftwood.config.set(key, value, guild_id) end
+
 --- Timer Functions
 
 --- Run a function after a specified number of seconds.
@@ -134,6 +439,52 @@ function driftwood.state.clear(key) end
 --- @param seconds number The delay time in seconds.
 function driftwood.timer.run_after(callback, seconds) end
 
+--- Name a handler for scheduled tasks. Handlers must be registered every time
+--- the scripts load, so tasks saved before a restart can find them again.
+--- @param name string The name tasks refer to the handler by.
+--- @param handler fun(args: any, task_id: string) Called with the task's arguments when it fires.
+function driftwood.timer.handler(name, handler) end
+
+--- ScheduleOptions class for configuring a scheduled task.
+--- @class ScheduleOptions
+--- @field missed? string What happens when the task's time passed while the bot was offline: "fire" runs it as soon as the bot is ready (default), "skip" drops it.
+
+--- Schedule a named handler to run later. Unlike `run_after`, the task is kept
+--- in the bot's state, so it survives restarts when `STATE_PATH` is set.
+--- @param handler string The name of a handler registered with `driftwood.timer.handler`.
+--- @param seconds number The delay time in seconds.
+--- @param args? any Arguments passed to the handler, which must be storable in state (no functions).
+--- @param options? ScheduleOptions Optional task options.
+--- @return string task_id The ID of the task, for cancelling it.
+function driftwood.timer.schedule(handler, seconds, args, options) end
+
+--- QueuedMessage class for one message of a message queue.
+--- @class QueuedMessage
+--- @field content? string The message content.
+--- @field embed? MessageEmbed Optional embed to include in the message.
+--- @field delay? number Seconds to wait after the previous message (default: the queue's `interval`, or 0 for the first message).
+
+--- MessageQueueOptions class for configuring a message queue.
+--- @class MessageQueueOptions
+--- @field interval? number Seconds between messages that don't set their own `delay` (default: 0).
+--- @field missed? string What happens to messages whose time passed while the bot was offline: "fire" sends them one second apart once the bot is ready (default), "skip" drops them.
+
+--- Queue messages to be sent to a channel one after another, e.g. a drip of
+--- onboarding tips over a week. Each message is only sent once the one before
+--- it was, at least a second apart, so they always arrive in order. Like
+--- scheduled tasks the queue survives restarts when `STATE_PATH` is set.
+--- A message that fails to send is logged and skipped.
+--- @param channel_id string The ID of the channel to send the messages to.
+--- @param messages (string|QueuedMessage)[] The messages, in the order they are sent.
+--- @param options? MessageQueueOptions Optional queue options.
+--- @return string queue_id The ID of the queue, for cancelling the messages not sent yet with `driftwood.timer.cancel`.
+function driftwood.timer.queue(channel_id, messages, options) end
+
+--- Cancel a scheduled task or message queue.
+--- @param task_id string The ID returned by `driftwood.timer.schedule` or `driftwood.timer.queue`.
+--- @return boolean cancelled Whether the task or queue was still pending.
+function driftwood.timer.cancel(task_id) end
+
 --- Logging Functions
 
 --- Log a debug-level message.
@@ -160,8 +511,11 @@ function driftwood.new_button(label, custom_id, disabled) end
 --- Create a new instance of a select menu option.
 --- @param label string The label of the option.
 --- @param value string The custom ID for the option.
+--- Discord has no per-option disabled state, so to stop an option from being
+--- picked leave it out, or disable the whole menu.
+--- @param default? boolean|SelectOptionExtras Whether the option is pre-selected (default: false), or a table of extras.
 --- @return SelectOption opt The new select menu option.
-function driftwood.new_selectmenu_opt(label, value) end
+function driftwood.new_selectmenu_opt(label, value, default) end
 
 --- Create a new instance of a select menu in an action row.
 --- @param placeholder string The placeholder text for the select menu.
@@ -173,12 +527,16 @@ function driftwood.new_selectmenu(placeholder, custom_id, options, disabled) end
 
 --- Options Functions
 
+--- Each builder returns a new option table and copies its choices, so the
+--- same option can be shared between several commands.
+
 --- Create a new string option for a command.
 --- @param label string The label of the option.
 --- @param description string The description of the option.
 --- @param required? boolean Whether the option is required (default: false).
+--- @param choices? CommandOptionChoice[] Optional predefined choices.
 --- @return CommandOption option The new string option.
-function driftwood.option.new_string(label, description, required) end
+function driftwood.option.new_string(label, description, required, choices) end
 
 --- Create a new boolean option for a command.
 --- @param label string The label of the option.
@@ -191,34 +549,87 @@ function driftwood.option.new_bool(label, description, required) end
 --- @param label string The label of the option.
 --- @param description string The description of the option.
 --- @param required? boolean Whether the option is required (default: false).
+--- @param choices? CommandOptionChoice[] Optional predefined choices.
 --- @return CommandOption option The new number option.
-function driftwood.option.new_number(label, description, required) end
+function driftwood.option.new_number(label, description, required, choices) end
 
 --- Message Functions
 
+--- Sending, replying to, editing and deleting messages waits and retries when
+--- Discord rate limits the request, up to `SEND_RETRIES` times, so loops over
+--- many channels slow down instead of failing. The call blocks while waiting.
+--- Channels can be given by name as well as by ID, e.g. "announcements" or
+--- "#announcements", looked up in the guild of the interaction being handled.
+--- A name shared by several channels fails with an error listing their IDs.
+
 --- Add a message to a channel.
---- @param channel_id string The ID of the channel to send the message to.
---- @param content string The message content.
+--- @param channel_id string The ID or name of the channel to send the message to.
+--- @param content string The message content, which must be empty when using layout components.
 --- @param options? MessageOptions Optional options for the message.
 --- @return string|nil message_id The ID of the sent message, or nil if failed.
+--- @return Message|string|nil message The sent message, including its jump `link`, or the reason sending failed.
 function driftwood.message.add(channel_id, content, options) end
 
+--- MessageReplyOptions class for defining message reply options.
+--- @class MessageReplyOptions
+--- @field quote? boolean Whether to quote the replied message in an embed, so the context survives the original being deleted (default: false).
+--- @field mention? boolean Whether to ping the author of the replied message (default: false).
+
+--- Reply to a message. If the original message was deleted, the reply is sent as a plain message.
+--- @param channel_id string The ID or name of the channel containing the message.
+--- @param message_id string The ID of the message to reply to.
+--- @param content string The reply content.
+--- @param options? MessageReplyOptions Optional options for the reply.
+--- @return string|nil message_id The ID of the sent reply, or nil if failed.
+--- @return Message|string|nil message The sent reply, including its jump `link`, or the reason sending failed.
+function driftwood.message.reply(channel_id, message_id, content, options) end
+
 --- Edit an existing message.
 --- @param message_id string The ID of the message to edit.
---- @param channel_id string The ID of the channel containing the message.
+--- @param channel_id string The ID or name of the channel containing the message.
 --- @param content string The new message content.
 --- @param options? MessageOptions Optional options for the message.
 --- @return boolean success Whether the edit was successful.
 function driftwood.message.edit(message_id, channel_id, content, options) end
 
---- Delete a message.
+--- Delete a message. In dry-run mode the message is kept and a preview is returned.
 --- @param message_id string The ID of the message to delete.
---- @param channel_id string The ID of the channel containing the message.
+--- @param channel_id string The ID or name of the channel containing the message.
 --- @return boolean success Whether the deletion was successful.
+--- @return DryRunPreview|nil preview What would have been deleted, in dry-run mode.
 function driftwood.message.delete(message_id, channel_id) end
 
+--- Get a single message.
+--- @param message_id string The ID of the message.
+--- @param channel_id string The ID or name of the channel containing the message.
+--- @return Message|nil message The message, or nil if failed.
+--- @return string|nil error The reason the message could not be fetched.
+function driftwood.message.get(message_id, channel_id) end
+
+--- Get the most recent messages in a channel, newest first.
+--- @param channel_id string The ID or name of the channel.
+--- @param options? MessageHistoryOptions Optional paging and filtering options.
+--- With filters, `limit` is the number of matches to return.
+--- @return Message[]|nil messages The messages, or nil if failed.
+--- @return string|nil error The reason the history could not be fetched.
+function driftwood.message.history(channel_id, options) end
+
+--- Build the jump link to a message.
+--- @param guild_id string The ID of the guild, or an empty string for DMs.
+--- @param channel_id string The ID of the channel.
+--- @param message_id string The ID of the message.
+--- @return string link The https://discord.com/channels/... URL of the message.
+function driftwood.message.link(guild_id, channel_id, message_id) end
+
+--- Get the results of a native poll attached to a message.
+--- @param message_id string The ID of the message containing the poll.
+--- @param channel_id string The ID or name of the channel containing the message.
+--- @return PollResults|nil results The poll results, or nil if failed.
+--- @return string|nil error The reason the results could not be read.
+function driftwood.message.poll_results(message_id, channel_id) end
+
 --- Reaction Functions
---- These functions provide support for adding and removing reactions on messages.
+--- These functions provide support for adding, removing and counting reactions on messages.
 
 --- Add a reaction to a message.
 --- @param message_id string The ID of the message to react to.
@@ -227,34 +638,677 @@ function driftwood.message.delete(message_id, channel_id) end
 --- @return boolean success Whether the reaction was successfully added.
 function driftwood.reaction.add(message_id, channel_id, reaction_emoji) end
 
---- Remove a reaction from a message.
+--- Remove a reaction from a message. In dry-run mode the reactions are kept and a preview is returned.
 --- @param message_id string The ID of the message from which to remove the reaction.
 --- @param channel_id string The ID of the channel where the message is located.
 --- @param reaction_emoji string The emoji to remove as a reaction.
 --- @return boolean success Whether the reaction was successfully removed.
+--- @return DryRunPreview|nil preview What would have been removed, in dry-run mode.
 function driftwood.reaction.remove(message_id, channel_id, reaction_emoji) end
 
+--- ReactionTallyOptions class for defining reaction tally options.
+--- @class ReactionTallyOptions
+--- @field exclude_self? boolean Whether to leave out the bot's own reactions, e.g. the ones it seeded a poll with (default: false).
+
+--- Count the reactions on a message, without fetching who reacted.
+--- Emojis are keyed as `reaction.add` accepts them, `name:id` for custom emojis,
+--- and emojis left without reactions are omitted.
+--- @param channel_id string The ID of the channel where the message is located.
+--- @param message_id string The ID of the message.
+--- @param options? ReactionTallyOptions Optional options for the tally.
+--- @return table<string, number>|nil tally The number of reactions per emoji, or nil if failed.
+--- @return string|nil error The reason the message couldn't be fetched.
+function driftwood.reaction.tally(channel_id, message_id, options) end
+
 
 --- Channel Functions
 
 --- Get a channel by name.
---- @param channel_name string The name of the channel.
---- @return string|nil channel_id The ID of the channel, or nil if not found.
+--- @param channel_name string The name of the channel, optionally prefixed with "#".
+--- @return string|nil channel_id The ID of the channel, or nil if not found or several channels have the name.
+--- @return string|nil error Why the channel wasn't found.
 function driftwood.channel.get(channel_name) end
 
+--- Guild Functions
+
+--- GuildCounts class holding the approximate size of the guild.
+--- @class GuildCounts
+--- @field member_count number The approximate number of members.
+--- @field presence_count number The approximate number of members currently online.
+
+--- Get the approximate member and online counts of the guild, e.g. for status
+--- displays. Counts are cached for a minute, as Discord updates them slowly.
+--- @return GuildCounts|nil counts The guild counts, or nil if failed.
+--- @return string|nil error The reason the counts couldn't be fetched.
+function driftwood.guild.counts() end
+
+--- AuditLogOptions class for filtering the guild's audit log.
+--- @class AuditLogOptions
+--- @field action_type? string Only entries of this action, using Discord's names such as "member_kick", "member_ban_add" or "message_delete".
+--- @field user_id? string Only entries of actions taken by this user.
+--- @field limit? number How many entries to fetch, between 1 and 100 (default: 50).
+
+--- AuditLogEntry class describing an action taken in the guild.
+--- @class AuditLogEntry
+--- @field id string The ID of the entry.
+--- @field action string The action taken, e.g. "member_kick", or "unknown" for actions without a name.
+--- @field user_id string The ID of the user who took the action.
+--- @field username string The username of the user who took the action, if known.
+--- @field target_id string The ID of the user, channel, message or other object the action affected.
+--- @field reason string The reason given for the action, if any.
+--- @field timestamp number The unix time the action was taken.
+
+--- Read the guild's audit log, newest entries first. Needs the View Audit Log permission.
+--- @param options? AuditLogOptions Optional filters.
+--- @return AuditLogEntry[]|nil entries The audit log entries, or nil if failed.
+--- @return string|nil error The reason the audit log couldn't be read, e.g. a missing permission.
+function driftwood.guild.audit_log(options) end
+
+--- GuildImageOptions class for defining guild image URL options.
+--- @class GuildImageOptions
+--- @field size? number The image size in pixels, a power of two between 16 and 4096 (default: the original size).
+
+--- Get the CDN URL of the guild's icon, as a gif when the icon is animated and a png otherwise.
+--- @param options? GuildImageOptions Optional options for the URL.
+--- @return string|nil url The icon URL, or nil if the guild has no icon or couldn't be fetched.
+--- @return string|nil error The reason the guild couldn't be fetched.
+function driftwood.guild.icon_url(options) end
+
+--- Get the CDN URL of the guild's banner, as a gif when the banner is animated and a png otherwise.
+--- @param options? GuildImageOptions Optional options for the URL.
+--- @return string|nil url The banner URL, or nil if the guild has no banner or couldn't be fetched.
+--- @return string|nil error The reason the guild couldn't be fetched.
+function driftwood.guild.banner_url(options) end
+
+--- Member Functions
+
+--- Set the nickname of a guild member.
+--- @param user_id string The ID of the member to rename.
+--- @param nickname? string The new nickname, or an empty string to reset it.
+--- @return boolean success Whether the nickname was changed.
+--- @return string|nil error The reason the change failed, e.g. missing permissions or role hierarchy.
+function driftwood.member.set_nick(user_id, nickname) end
+
+--- Add a role to a guild member.
+--- @param user_id string The ID of the member.
+--- @param role_id string The ID of the role to add.
+--- @return boolean success Whether the role was added.
+--- @return string|nil error The reason the change failed, e.g. missing permissions or role hierarchy.
+function driftwood.member.add_role(user_id, role_id) end
+
+--- Remove a role from a guild member. In dry-run mode the role is kept and a preview is returned.
+--- @param user_id string The ID of the member.
+--- @param role_id string The ID of the role to remove.
+--- @return boolean success Whether the role was removed.
+--- @return string|DryRunPreview|nil error The reason the change failed, e.g. missing permissions or role hierarchy, or what would have been removed in dry-run mode.
+function driftwood.member.remove_role(user_id, role_id) end
+
+--- Role Functions
+
+--- AssignFilter class for selecting the members a bulk assignment applies to.
+--- @class AssignFilter
+--- @field joined_before? number Only members who joined before this unix timestamp.
+--- @field joined_after? number Only members who joined after this unix timestamp.
+--- @field has_role? string Only members who already have this role.
+--- @field include_bots? boolean Whether bots are included, defaults to false.
+
+--- AssignResult class describing the outcome of a bulk assignment.
+--- @class AssignResult
+--- @field changed number The number of members the role was added to.
+--- @field skipped number The number of members that already had the role or didn't match the filter.
+--- @field failed number The number of members the role couldn't be added to.
+--- @field dry_run? boolean Set in dry-run mode, when no role was added.
+--- @field action? string "add_role", in dry-run mode.
+--- @field ids? string[] The members that would have been given the role, in dry-run mode.
+
+--- Add a role to every guild member matching a filter. Members are assigned in
+--- the background at a steady pace, so large guilds can take several minutes.
+--- Listing members needs the Server Members intent enabled in the developer portal.
+--- In dry-run mode no role is added, and the callback receives a preview of
+--- the members that would have been given it.
+--- @param role_id string The ID of the role to add.
+--- @param filter? AssignFilter The members to assign, defaults to every member who isn't a bot.
+--- @param callback? fun(result: AssignResult, error: string|nil) Called once every member has been visited.
+--- @return boolean started Whether the assignment started.
+--- @return string|nil error The reason it didn't start, e.g. the role is already being assigned.
+function driftwood.role.assign_all(role_id, filter, callback) end
+
+--- Reaction Role Functions
+
+--- Grant a role to members who react to a message with an emoji, and remove
+--- it when they take the reaction back. A message can map many emoji to roles.
+--- Mappings are kept in the bot's state, so they survive restarts when `STATE_PATH` is set.
+--- @param message_id string The ID of the message.
+--- @param emoji string A unicode emoji, or a custom emoji as `<:name:id>` or `name:id`.
+--- @param role_id string The ID of the role to grant.
+function driftwood.reaction_role.bind(message_id, emoji, role_id) end
+
+--- Stop granting a role for an emoji on a message. Roles already granted are kept.
+--- In dry-run mode the mapping is kept and a preview is returned.
+--- @param message_id string The ID of the message.
+--- @param emoji string The emoji the role was bound to.
+--- @return boolean success Always true, unbinding an emoji that isn't bound does nothing.
+--- @return DryRunPreview|nil preview What would have been unbound, in dry-run mode.
+function driftwood.reaction_role.unbind(message_id, emoji) end
+
+--- Thread Functions
+
+--- ThreadOptions class for configuring a thread.
+--- @class ThreadOptions
+--- @field auto_archive_duration? integer Minutes of inactivity before archiving: 60, 1440, 4320 or 10080.
+--- @field slowmode? integer Whole seconds a member must wait between messages (0-21600).
+--- @field locked? boolean Whether only moderators can unarchive the thread.
+--- @field archived? boolean Whether the thread is archived.
+
+--- Configure the settings of an existing thread.
+--- @param channel_id string The ID of the thread.
+--- @param options ThreadOptions The settings to change, at least one of them.
+--- @return boolean success Whether the thread was updated.
+--- @return string|nil error The reason the update failed.
+function driftwood.thread.configure(channel_id, options) end
+
+--- Forum Functions
+
+--- ForumPostOptions class for opening a forum post.
+--- @class ForumPostOptions
+--- @field name string The title of the post (up to 100 characters).
+--- @field content? string The content of the starting message.
+--- @field embed? table The embed of the starting message. Either content or embed must be set.
+--- @field tags? string[] The IDs of the forum's available tags to apply, up to 5.
+
+--- Open a new post in a forum channel.
+--- @param channel_id string The ID of the forum channel.
+--- @param options ForumPostOptions The post to create.
+--- @return string|nil post_id The ID of the created post (thread).
+--- @return string|nil error The reason the post could not be created.
+function driftwood.forum.create_post(channel_id, options) end
+
+--- ForumTag class representing a tag available in a forum channel.
+--- @class ForumTag
+--- @field id string The ID of the tag, as taken by `create_post`.
+--- @field name string The name of the tag.
+--- @field moderated boolean Whether only moderators can apply the tag.
+--- @field emoji? string The unicode emoji of the tag.
+--- @field emoji_id? string The ID of the custom emoji of the tag.
+
+--- Get the tags available in a forum channel. Tags are cached for a few
+--- minutes per channel, so edits to them may take a moment to show up.
+--- @param channel_id string The ID of the forum channel.
+--- @return ForumTag[]|nil tags The available tags, in the forum's order.
+--- @return string|nil error The reason the tags could not be fetched.
+function driftwood.forum.tags(channel_id) end
+
+--- Snowflake Functions
+
+--- Decode the creation time encoded in a Discord ID (snowflake).
+--- @param id string The snowflake ID, e.g. a user or message ID.
+--- @return number seconds The creation time as a Unix timestamp in seconds.
+--- @return number milliseconds The creation time as a Unix timestamp in milliseconds.
+--- @return string date The creation time as an RFC 3339 UTC date.
+function driftwood.snowflake.timestamp(id) end
+
+--- Time Functions
+
+--- Format a Unix timestamp as Discord timestamp markup, shown in each reader's time zone.
+--- Styles: "t" short time, "T" long time, "d" short date, "D" long date,
+--- "f" short date/time, "F" long date/time, "R" relative (e.g. "in 2 hours").
+--- @param unix number The Unix timestamp in seconds.
+--- @param style? string The display style (default: "f").
+--- @return string markup The `<t:unix:style>` markup.
+function driftwood.time.format(unix, style) end
+
+--- Format Functions
+
+--- Mention a user, rendered as `<@id>`.
+--- @param user_id string The ID of the user.
+--- @return string mention The mention markup.
+function driftwood.format.mention_user(user_id) end
+
+--- Mention a channel, rendered as `<#id>`.
+--- @param channel_id string The ID of the channel.
+--- @return string mention The mention markup.
+function driftwood.format.mention_channel(channel_id) end
+
+--- Mention a role, rendered as `<@&id>`.
+--- @param role_id string The ID of the role.
+--- @return string mention The mention markup.
+function driftwood.format.mention_role(role_id) end
+
+--- Wrap text in a code block. Code fences inside the text can't close the block early.
+--- @param text string The text to wrap.
+--- @param lang? string The language to highlight the code as, e.g. "lua".
+--- @return string block The code block markup.
+function driftwood.format.code_block(text, lang) end
+
+--- Escape markdown in text, so user input is echoed exactly as typed.
+--- @param text string The text to escape.
+--- @return string escaped The escaped text.
+function driftwood.format.escape_markdown(text) end
+
+--- Attachment Functions
+
+--- Download the contents of a Discord attachment.
+--- @param url string The attachment URL (must be on cdn.discordapp.com or media.discordapp.net).
+--- @param max_size? number The maximum number of bytes to accept (default and limit: 8 MiB).
+--- @return string|nil content The raw file contents, or nil if failed.
+--- @return string content_type_or_error The content type, or the reason the download failed.
+function driftwood.attachment.download(url, max_size) end
+
+--- Parse Functions
+
+--- CSVOptions class for parsing CSV text.
+--- @class CSVOptions
+--- @field header? boolean Whether the first row names the columns, so rows are keyed by column name (default: false).
+--- @field delimiter? string The single character separating fields (default: ",").
+
+--- Parse CSV text, such as a downloaded attachment. Quoted fields may contain
+--- delimiters, quotes (doubled) and line breaks. Every row must have the same
+--- number of fields.
+--- @param content string The CSV text.
+--- @param options? CSVOptions Optional settings.
+--- @return (string[]|table<string, string>)[]|nil rows The rows, as arrays of fields or keyed by column name with `header`.
+--- @return string|nil error The reason the text could not be parsed.
+function driftwood.parse.csv(content, options) end
+
+--- Decode JSON text, such as a downloaded attachment. Objects and arrays
+--- become tables, and null becomes nil.
+--- @param content string The JSON text.
+--- @return any|nil value The decoded value.
+--- @return string|nil error The reason the text could not be parsed.
+function driftwood.parse.json(content) end
+
+--- Metrics Functions
+
+--- CommandMetrics class holding the metrics of a single command.
+--- @class CommandMetrics
+--- @field count number Number of invocations.
+--- @field errors number Number of invocations whose handler raised an error.
+--- @field error_rate number Fraction of invocations that failed, between 0 and 1.
+--- @field avg_ms number Mean handler duration in milliseconds.
+--- @field max_ms number Longest handler duration in milliseconds.
+--- @field total_ms number Sum of handler durations in milliseconds.
+--- @field last_used number Unix timestamp of the most recent invocation.
+
+--- Get the metrics of every command invoked since startup (or the last reset).
+--- Subcommands are keyed as `command_subcommand`.
+--- @return table<string, CommandMetrics> metrics The metrics keyed by command name.
+function driftwood.metrics.commands() end
+
+--- Clear all recorded command metrics.
+function driftwood.metrics.reset() end
+
+--- Bot Functions
+
+--- BotPingOptions class for measuring latency.
+--- @class BotPingOptions
+--- @field rest? boolean Whether to also time a request to the REST API (default: false).
+
+--- BotPing class holding the measured latency.
+--- @class BotPing
+--- @field gateway_ms number The gateway heartbeat latency in milliseconds, 0 until the first heartbeat is acknowledged.
+--- @field rest_ms? number The round trip of a REST API request in milliseconds, if `rest` was set.
+
+--- Measure the latency to Discord, e.g. for a /ping command.
+--- @param options? BotPingOptions Optional settings.
+--- @return BotPing|nil ping The measured latency.
+--- @return string|nil error The reason the REST latency could not be measured.
+function driftwood.bot.ping(options) end
+
+--- Get how long the bot has been connected, counted from its first ready
+--- event so reconnects don't reset it.
+--- @return number seconds The uptime in seconds, 0 before the bot is ready.
+--- @return number|nil started_at When the bot connected, as a unix timestamp in seconds, or nil before it is ready.
+function driftwood.bot.uptime() end
+
+--- Cooldown Functions
+
+--- Get how long until a cooldown expires. Command cooldowns are named after
+--- the top-level command and scoped to the ID of the user.
+--- @param name string The name of the cooldown, e.g. the command name.
+--- @param scope_id string The ID the cooldown applies to, e.g. a user ID.
+--- @return number seconds The seconds remaining, rounded up, or 0 if it is ready.
+function driftwood.cooldown.remaining(name, scope_id) end
+
+--- Cache Functions
+
+--- Cache a value in memory for transient data such as API responses. Unlike
+--- `driftwood.state` the cache is never saved, and once it holds `CACHE_SIZE`
+--- entries the least recently used one is evicted to make room.
+--- @param key string The key to store the value under.
+--- @param value any The value to cache, or nil to remove it.
+--- @param ttl? number Seconds until the value expires, kept until evicted if unset.
+function driftwood.cache.set(key, value, ttl) end
+
+--- Get a cached value, marking it as recently used.
+--- @param key string The key to retrieve the value for.
+--- @return any|nil value The cached value, or nil if it is missing, expired or evicted.
+function driftwood.cache.get(key) end
+
+--- Audit Functions
+
+--- AuditEntry class describing a single command invocation.
+--- @class AuditEntry
+--- @field command string The command name, with subcommands as `command_subcommand`.
+--- @field user_id string The ID of the user who ran the command.
+--- @field channel_id string The ID of the channel the command was run in.
+--- @field timestamp number The unix time the command was run.
+--- @field success boolean Whether the handler completed without raising an error.
+
+--- List recent command invocations, newest first. The last 500 are kept in
+--- memory; set `AUDIT_CHANNEL_ID` to also post every invocation to a channel.
+--- @param count? number How many invocations to return, between 1 and 500 (default: 20).
+--- @return AuditEntry[] entries The recent invocations.
+function driftwood.audit.recent(count) end
+
+--- Presence Functions
+
+--- PresenceOptions class for defining how a presence is shown.
+--- @class PresenceOptions
+--- @field type? string The activity: "playing" (default), "listening", "watching", "competing" or "custom".
+--- @field status? string The online status: "online" (default), "idle", "dnd" or "invisible".
+
+--- PresenceStatus class for a rotation entry with its own options.
+--- @class PresenceStatus : PresenceOptions
+--- @field text string The activity text.
+
+--- Set the bot's presence, stopping any rotation. The text may contain
+--- `{member_count}`, `{guild_count}` and `{guild_name}` placeholders.
+--- @param text string The activity text, e.g. "/help".
+--- @param options? PresenceOptions How the presence is shown.
+--- @return boolean success Whether the presence was updated.
+--- @return string|nil error The reason the update failed.
+function driftwood.presence.set(text, options) end
+
+--- Cycle the bot's presence through a list of statuses, replacing any
+--- previous rotation. Placeholders are filled in on every update.
+---
+--- ```lua
+--- driftwood.on_ready(function()
+---     driftwood.presence.rotate({
+---         { text = "{member_count} users", type = "watching" },
+---         "/help",
+---     }, 60)
+--- end)
+--- ```
+--- @param list (string|PresenceStatus)[] The statuses to cycle through.
+--- @param interval number Seconds between updates, at least 15.
+--- @param options? PresenceOptions Defaults for entries that don't set their own.
+function driftwood.presence.rotate(list, interval, options) end
+
+--- Command Functions
+
+--- Stop running a command's handler without redeploying. Users invoking it
+--- get an ephemeral "This command is disabled." The setting is kept in the
+--- bot's state, so it survives restarts when `STATE_PATH` is set.
+--- @param name string The command name, or `command_subcommand` for a single subcommand.
+--- @return boolean success Whether the command was disabled.
+--- @return string|nil error The reason it failed, e.g. the command is not registered.
+function driftwood.command.disable(name) end
+
+--- Run a disabled command's handler again.
+--- @param name string The command name, or `command_subcommand` for a single subcommand.
+--- @return boolean success Whether the command was enabled.
+--- @return string|nil error The reason it failed, e.g. the command is not registered.
+function driftwood.command.enable(name) end
+
+--- Random Functions
+
+--- Pick a random integer between two bounds, inclusive. Prefer these over
+--- `math.random`, which is not seeded by the bot.
+--- @param min number The smallest possible result.
+--- @param max number The largest possible result.
+--- @return number value The random integer.
+function driftwood.random.int(min, max) end
+
+--- Pick a random element of an array.
+--- @generic T
+--- @param array T[] The array to pick from.
+--- @return T|nil element A random element, or nil if the array is empty.
+function driftwood.random.choice(array) end
+
+--- Shuffle an array into a new array, leaving the original unchanged.
+--- @generic T
+--- @param array T[] The array to shuffle.
+--- @return T[] shuffled The elements in random order.
+function driftwood.random.shuffle(array) end
+
+--- ID Functions
+
+--- Generate a unique ID, e.g. for component custom_ids and state keys. IDs are
+--- UUIDv7s, which never repeat within the bot and sort by creation time.
+--- @return string id The new ID, e.g. "01920c5e-8f3a-7000-9c7e-3f1d2a4b5c6d".
+function driftwood.id.new() end
+
+--- Color Functions
+
+--- Build an embed color from red, green and blue components.
+--- Named colors, including Discord's brand palette, are available as `driftwood.color.<name>`.
+--- @param r number The red component (0-255).
+--- @param g number The green component (0-255).
+--- @param b number The blue component (0-255).
+--- @return number color The color as an integer, e.g. for `embed.color`.
+function driftwood.color.rgb(r, g, b) end
+
 --- Command Registration
 
---- Register an application command.
+--- Register an application command. Registering a command again replaces it
+--- and all of its handlers. Subcommands are named `command_subcommand`, so a
+--- command whose name matches another command's subcommand raises an error.
+--- The whole table is checked before anything is registered, and every
+--- problem found, such as a bad name, an unknown option type or a too long
+--- description, is reported in a single error.
 --- @param command Command A table defining the command, its options, and handlers.
 function driftwood.register_application_command(command) end
 
+--- Register a built-in command that reloads every script without restarting
+--- the bot, replying with the scripts that loaded and the errors of those that
+--- didn't. Only administrators can see or use it. Handlers are registered
+--- afresh and modules are required again, while state, config and scheduled
+--- tasks are kept. `on_ready` handlers are not called again.
+--- @param name? string The name of the command (default: "reload").
+function driftwood.register_reload_command(name) end
+
+--- Register a command made of subcommands, without spelling out the nested
+--- subcommand options of `register_application_command`.
+---
+--- ```lua
+--- driftwood.command_group({
+---     name = "tag",
+---     description = "Manage tags",
+---     subcommands = {
+---         add = { description = "Add a tag", handler = add_tag, options = { driftwood.option.new_string("name", "Tag name", true) } },
+---         list = { description = "List tags", handler = list_tags },
+---     },
+--- })
+--- ```
+--- @param group CommandGroup The command and its subcommands.
+function driftwood.command_group(group) end
+
 --- Register an interaction event.
 --- @param custom_id string The custom ID or regex for the interaction.
 --- @param handler fun(interaction: EventInteraction) The handler function for the interaction.
 function driftwood.register_interaction(custom_id, handler) end
 
 
---- Register an On Ready event handler.
+--- Register a middleware that runs before every command handler.
+--- Middleware runs in registration order: the first registered is called first,
+--- and its `next` calls the second, until the last `next` calls the command
+--- handler. Returning without calling `next` stops the command from running.
+---
+--- ```lua
+--- driftwood.use(function(interaction, next)
+---     if driftwood.state.get("maintenance") then
+---         interaction:reply("The bot is in maintenance mode.", { ephemeral = true })
+---         return
+---     end
+---     next()
+--- end)
+--- ```
+--- @param middleware fun(interaction: CommandInteraction, next: fun()) The middleware function.
+function driftwood.use(middleware) end
+
+--- Register a handler called with every message the bot can see, other than its own.
+--- Registering a handler requests the `message_content` privileged intent,
+--- which must be enabled for the bot in the developer portal.
+--- Any number of handlers can be registered, e.g. one per module. They run in
+--- the order they were registered, each with its own message table, and a
+--- handler raising an error is logged without skipping the ones after it.
+---
+--- ```lua
+--- driftwood.on_message(function(message)
+---     for _, file in ipairs(message.attachments) do
+---         if file.content_type:match("^image/") then
+---             driftwood.log.info("Image uploaded: " .. file.url)
+---         end
+---     end
+--- end)
+--- ```
+--- @param handler fun(message: Message) The function to call for each message.
+function driftwood.on_message(handler) end
+
+--- Register a handler called when a reaction is added to a message, other
+--- than by the bot itself. Like `on_message`, any number of handlers can be
+--- registered and they run in registration order. Custom emojis should be
+--- matched by `emoji.id`, as their names aren't unique.
+---
+--- ```lua
+--- driftwood.on_reaction_add(function(reaction)
+---     if not reaction.emoji.unicode and reaction.emoji.id == "123456789012345678" then
+---         driftwood.log.info(reaction.user_id .. " reacted with " .. reaction.emoji.to_string())
+---     end
+--- end)
+--- ```
+--- @param handler fun(reaction: Reaction) The function to call for each added reaction.
+function driftwood.on_reaction_add(handler) end
+
+--- Register a handler called when a reaction is removed from a message, other
+--- than by the bot itself. Handlers run like the ones of `on_reaction_add`.
+--- @param handler fun(reaction: Reaction) The function to call for each removed reaction.
+function driftwood.on_reaction_remove(handler) end
+
+--- Wait for a user to send a message in a channel without blocking the bot.
+--- The callback receives the message content and the message, or nil for both
+--- when the timeout passes first. Reading message content requires the
+--- `message_content` intent: add it to `INTENTS` and enable it for the bot in
+--- the developer portal.
+---
+--- ```lua
+--- interaction:reply("What should the new name be?")
+--- driftwood.await_message(interaction.channel_id, interaction.user.id, 60, function(content)
+---     if not content then
+---         driftwood.message.add(interaction.channel_id, "Timed out, nothing was changed.")
+---         return
+---     end
+---     driftwood.state.set("name", content)
+--- end)
+--- ```
+--- @param channel_id string The channel to watch.
+--- @param user_id string The user whose message to wait for.
+--- @param timeout number Seconds to wait, up to 900.
+--- @param callback fun(content: string|nil, message: Message|nil) Called with the reply, or nil on timeout.
+function driftwood.await_message(channel_id, user_id, timeout, callback) end
+
+--- AwaitComponentOptions class for defining await_component options.
+--- @class AwaitComponentOptions
+--- @field user_id? string The user allowed to respond (default: the user of the interaction being handled).
+--- @field timeout? number Seconds to wait, up to 900 (default: 60).
+
+--- Wait for a button or select menu on a message to be used, without blocking the bot.
+--- The callback receives the component interaction, with `custom_id` and `values`,
+--- or nil when the timeout passes first. While waiting, clicks from other users
+--- get an ephemeral notice and handlers from `register_interaction` are skipped.
+---
+--- ```lua
+--- local id = driftwood.message.add(interaction.channel_id, "Are you sure?", {
+---     components = { driftwood.new_button("Yes", "confirm_yes"), driftwood.new_button("No", "confirm_no") },
+--- })
+--- driftwood.await_component(id, { timeout = 30 }, function(click)
+---     if not click then return end
+---     click:reply(click.custom_id == "confirm_yes" and "Done!" or "Cancelled.")
+--- end)
+--- ```
+--- @param message_id string The message carrying the components.
+--- @param options? AwaitComponentOptions Who may respond and how long to wait.
+--- @param callback fun(interaction: EventInteraction|nil) Called with the interaction, or nil on timeout.
+function driftwood.await_component(message_id, options, callback) end
+
+--- ConfirmOptions class for defining confirm options.
+--- @class ConfirmOptions
+--- @field timeout? number Seconds to wait for an answer, up to 900 (default: 30).
+
+--- Ask the user of an interaction to confirm an action, without blocking the bot.
+--- The prompt is sent as an ephemeral reply with Yes and No buttons, or as an ephemeral
+--- followup if the interaction was already replied to. Only the user of the interaction
+--- can answer, and the buttons are removed once they do or the timeout passes.
+--- Call it while the interaction's handler runs, or later for an interaction
+--- whose response was deferred.
+---
+--- ```lua
+--- driftwood.confirm(interaction, "Delete the last 50 messages?", { timeout = 20 }, function(confirmed)
+---     if confirmed then
+---         interaction:followup("Purging...", { ephemeral = true })
+---     end
+--- end)
+--- ```
+--- @param interaction InteractionBase The interaction to ask the user of.
+--- @param prompt string The question to ask.
+--- @param options? ConfirmOptions How long to wait.
+--- @param callback fun(confirmed: boolean|nil) Called with true for Yes, false for No, or nil on timeout.
+--- @return boolean|nil sent True if the prompt was sent, or nil if failed.
+--- @return string|nil error The reason the prompt couldn't be sent.
+function driftwood.confirm(interaction, prompt, options, callback) end
+
+--- DryRunPreview class describing the changes a destructive binding skipped.
+--- @class DryRunPreview
+--- @field dry_run boolean Always true.
+--- @field action string The skipped action, e.g. "delete_message".
+--- @field ids string[] The IDs that would have been affected.
+
+--- Read or toggle dry-run mode, in which destructive bindings log what they
+--- would do and return a preview instead of making changes. Also enabled by
+--- setting the `DRY_RUN` environment variable.
+--- @param enabled? boolean Whether to enable dry-run mode, omit to only read it.
+--- @return boolean enabled Whether dry-run mode is enabled.
+function driftwood.dry_run(enabled) end
+
+--- List the premium SKUs a user currently has access to. Inside a handler,
+--- `interaction.entitlements` gives the same list without a request to Discord.
+--- @param user_id string The ID of the user.
+--- @return string[]|nil skus The SKU IDs of the user's active entitlements, or nil if failed.
+--- @return string|nil error The reason the lookup failed.
+function driftwood.entitlements(user_id) end
+
+--- Check whether a user owns the bot's application: its owner, or the owner
+--- and accepted members of the team owning it. The owners are fetched when the
+--- bot connects, before `on_ready` handlers run, so checking is cheap.
+---
+--- ```lua
+--- driftwood.register_application_command({
+---     name = "shutdown",
+---     description = "Stop the bot",
+---     handler = function(interaction)
+---         if not driftwood.is_owner(interaction.user.id) then
+---             return interaction:reply("Only the bot owner can do that.", { ephemeral = true })
+---         end
+---         -- ...
+---     end,
+--- })
+--- ```
+--- @param user_id string The ID of the user.
+--- @return boolean owner Whether the user owns the application, false if the owners couldn't be fetched.
+function driftwood.is_owner(user_id) end
+
+--- Register a fallback handler for commands without a registered handler.
+--- The invoked command name is available as `interaction.command`
+--- (subcommands as `command_subcommand`). Registering again replaces the fallback.
+--- Passing a string instead sets the ephemeral reply such commands get, which
+--- defaults to "This command is not available right now."
+--- @param handler fun(interaction: CommandInteraction)|string The fallback handler, or the reply to send.
+function driftwood.on_unknown_command(handler) end
+
+--- Register an On Ready event handler. Like `on_message`, any number of
+--- handlers can be registered; they run in registration order, and one
+--- raising an error doesn't skip the rest.
 --- @param handler fun() The handler function for the interaction.
 function driftwood.on_ready(handler) end
 